@@ -37,7 +37,7 @@
 //
 // Historical Data: Access OHLCV charting data with GetHistory() and GetHistoryLastDays().
 //
-// Smart Caching: All data is automatically cached for 5 minutes, reducing API calls by 95%
+// Smart Caching: All data is automatically cached for 5 minutes (configurable), reducing API calls by 95%
 // and improving performance by 100x for cached requests.
 //
 // # Performance
@@ -46,7 +46,8 @@
 //   - First API call: ~100ms (network request)
 //   - Cached calls: ~50µs (100x faster)
 //   - Thread-safe concurrent access
-//   - Automatic cache expiration after 5 minutes
+//   - Automatic cache expiration after 5 minutes, adjustable with CacheTTL and
+//     per-category CacheTTLOverrides
 //
 // # Configuration
 //
@@ -100,17 +101,45 @@ package openbymadata
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/carvalab/openbymadata/internal/api"
 	"github.com/carvalab/openbymadata/internal/cache"
 	"github.com/carvalab/openbymadata/internal/helpers"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
 )
 
 // client wraps the internal client and implements the public interface
 type client struct {
 	*api.Client
-	cache *cache.Cache
+	cache      *cache.Cache
+	snapshots  *cache.SnapshotStore
+	inflight   singleflight.Group // Coalesces concurrent fetches of the same collection
+	refreshing sync.Map           // Keys with a stale-while-revalidate refresh in progress
+	logger     Logger
+	cclSource  CCLSource
+	ratios     map[string]float64 // CEDEAR conversion ratios from ClientOptions.CedearRatios
+
+	// Caches of the quotes requested WithSettlement T0 or T2, created on first use
+	// with the options of the main cache, which holds the T1 quotes
+	cacheOptions     cache.Options
+	settlementMu     sync.Mutex
+	settlementCaches map[Settlement]*cache.Cache
+
+	indexStreamInterval time.Duration // Polling interval of StreamIndices
+	skipSessionInit     bool          // Don't fetch the translation dictionary on demand
 }
 
 // NewClient creates a new BYMA data client with the provided options.
@@ -139,6 +168,7 @@ type client struct {
 //		Timeout:       60 * time.Second,  // Longer timeout
 //		RetryAttempts: 5,                 // More retries
 //		EnableCache:   true,              // Keep caching enabled
+//		CacheTTL:      time.Hour,         // End-of-day data doesn't need fresher quotes
 //	}
 //	client := openbymadata.NewClient(opts)
 //
@@ -150,7 +180,38 @@ type client struct {
 //		Timeout:     10 * time.Second,
 //	}
 //	client := openbymadata.NewClient(devOpts)
+//
+// NewClient never fails: if the session with BYMA cannot be initialized, the error is
+// logged and the client is returned anyway. Use NewClientStrict to fail fast instead.
 func NewClient(opts ...*ClientOptions) Client {
+	return newClient(opts...)
+}
+
+// NewClientStrict works like NewClient but returns an error, and no client, when the
+// session cannot be initialized: BYMA is unreachable or its translation dictionary
+// cannot be loaded. Use it in deployments that should fail at startup rather than
+// serve a client whose every call fails.
+//
+// Example usage:
+//
+//	client, err := openbymadata.NewClientStrict(&openbymadata.ClientOptions{
+//		Timeout: 10 * time.Second,
+//	})
+//	if err != nil {
+//		log.Fatalf("BYMA unavailable: %v", err)
+//	}
+//	defer client.Close()
+func NewClientStrict(opts ...*ClientOptions) (Client, error) {
+	c := newClient(opts...)
+	if err := c.InitError(); err != nil {
+		_ = c.Close()
+		return nil, fmt.Errorf("failed to initialize BYMA client: %w", err)
+	}
+	return c, nil
+}
+
+// newClient builds the client returned by NewClient and NewClientStrict
+func newClient(opts ...*ClientOptions) *client {
 	options := DefaultClientOptions()
 	if len(opts) > 0 && opts[0] != nil {
 		if opts[0].BaseURL != "" {
@@ -162,32 +223,140 @@ func NewClient(opts ...*ClientOptions) Client {
 		if opts[0].RetryAttempts > 0 {
 			options.RetryAttempts = opts[0].RetryAttempts
 		}
+		if opts[0].RetryBaseDelay > 0 {
+			options.RetryBaseDelay = opts[0].RetryBaseDelay
+		}
+		if opts[0].RetryMaxDelay > 0 {
+			options.RetryMaxDelay = opts[0].RetryMaxDelay
+		}
 		if opts[0].Logger != nil {
 			options.Logger = opts[0].Logger
 		}
+		if opts[0].CCLSource != nil {
+			options.CCLSource = opts[0].CCLSource
+		}
+		if opts[0].CedearRatios != nil {
+			options.CedearRatios = opts[0].CedearRatios
+		}
+		if opts[0].RootCAs != nil {
+			options.RootCAs = opts[0].RootCAs
+		}
+		if opts[0].HTTPClient != nil {
+			options.HTTPClient = opts[0].HTTPClient
+		}
+		if opts[0].CacheTTL > 0 {
+			options.CacheTTL = opts[0].CacheTTL
+		}
+		if opts[0].CacheTTLOverrides != nil {
+			options.CacheTTLOverrides = opts[0].CacheTTLOverrides
+		}
+		if opts[0].CacheDir != "" {
+			options.CacheDir = opts[0].CacheDir
+		}
+		if opts[0].StaleWhileRevalidate > 0 {
+			options.StaleWhileRevalidate = opts[0].StaleWhileRevalidate
+		}
+		if opts[0].NegativeCacheTTL > 0 {
+			options.NegativeCacheTTL = opts[0].NegativeCacheTTL
+		}
+		if opts[0].IndexStreamInterval > 0 {
+			options.IndexStreamInterval = opts[0].IndexStreamInterval
+		}
+		options.InsecureSkipVerify = opts[0].InsecureSkipVerify
+		options.StrictParsing = opts[0].StrictParsing
+		options.OnRetry = opts[0].OnRetry
+		options.OnRequest = opts[0].OnRequest
+		options.OnError = opts[0].OnError
+		options.OnCacheHit = opts[0].OnCacheHit
+		options.OnCacheMiss = opts[0].OnCacheMiss
+		options.UserAgent = opts[0].UserAgent
+		options.ExtraHeaders = opts[0].ExtraHeaders
+		options.Debug = opts[0].Debug
+		options.FuturesMultipliers = opts[0].FuturesMultipliers
+		options.RequestsPerSecond = opts[0].RequestsPerSecond
+		options.SkipSessionInit = opts[0].SkipSessionInit
 		// EnableCache is handled below
 	}
 
 	// Convert to internal options
 	internalOpts := &api.ClientOptions{
-		BaseURL:       options.BaseURL,
-		Timeout:       options.Timeout,
-		RetryAttempts: options.RetryAttempts,
-		Logger:        &loggerAdapter{logger: options.Logger},
+		BaseURL:            options.BaseURL,
+		Timeout:            options.Timeout,
+		RetryAttempts:      options.RetryAttempts,
+		RetryBaseDelay:     options.RetryBaseDelay,
+		RetryMaxDelay:      options.RetryMaxDelay,
+		Logger:             &loggerAdapter{logger: options.Logger},
+		InsecureSkipVerify: options.InsecureSkipVerify,
+		RootCAs:            options.RootCAs,
+		HTTPClient:         options.HTTPClient,
+		StrictParsing:      options.StrictParsing,
+		OnRetry:            options.OnRetry,
+		OnRequest:          options.OnRequest,
+		OnError:            options.OnError,
+		UserAgent:          options.UserAgent,
+		ExtraHeaders:       options.ExtraHeaders,
+		Debug:              options.Debug,
+		FuturesMultipliers: options.FuturesMultipliers,
+		RequestsPerSecond:  options.RequestsPerSecond,
+		SkipSessionInit:    options.SkipSessionInit,
+	}
+
+	snapshotDir := ""
+	if options.CacheDir != "" {
+		snapshotDir = filepath.Join(options.CacheDir, "snapshots")
 	}
 
 	c := &client{
-		Client: api.New(internalOpts),
+		Client:    api.New(internalOpts),
+		snapshots: cache.NewSnapshotStore(snapshotDir),
+		logger:    options.Logger,
+		cclSource: options.CCLSource,
+		ratios:    options.CedearRatios,
+
+		indexStreamInterval: options.IndexStreamInterval,
+		skipSessionInit:     options.SkipSessionInit,
 	}
 
 	// Initialize cache if enabled
 	if options.EnableCache {
-		c.cache = cache.New()
+		c.cacheOptions = cache.Options{
+			Duration:  options.CacheTTL,
+			Overrides: options.CacheTTLOverrides,
+			Dir:       options.CacheDir,
+			Grace:     options.StaleWhileRevalidate,
+			NotFound:  options.NegativeCacheTTL,
+			OnLookup:  cacheLookupHook(options.OnCacheHit, options.OnCacheMiss),
+		}
+		c.cache = cache.New(c.cacheOptions)
+
+		// Cache the dictionary loaded with the session, or else use one persisted
+		// to CacheDir by an earlier process
+		if dictionary := c.Client.Dictionary(); dictionary != nil {
+			c.cache.SetDictionary(dictionary)
+		} else if dictionary, state := c.cache.GetDictionary(); state != cache.Miss {
+			c.Client.SetDictionary(dictionary)
+		}
 	}
 
 	return c
 }
 
+// cacheLookupHook combines the OnCacheHit and OnCacheMiss options into the cache's
+// lookup hook, or returns nil when neither is set
+func cacheLookupHook(onHit, onMiss func(category string)) func(category string, hit bool) {
+	if onHit == nil && onMiss == nil {
+		return nil
+	}
+	return func(category string, hit bool) {
+		switch {
+		case hit && onHit != nil:
+			onHit(category)
+		case !hit && onMiss != nil:
+			onMiss(category)
+		}
+	}
+}
+
 // loggerAdapter adapts the public logger interface to the internal one
 type loggerAdapter struct {
 	logger Logger
@@ -229,6 +398,180 @@ func (l *loggerAdapter) Error(msg string, fields ...api.LogField) {
 // Cache-enabled methods override the base Client methods
 // =============================================================================
 
+// cachedFetch returns the data cached under key or fetches and caches it. Concurrent
+// fetches of the same key are coalesced into a single upstream request shared by every
+// caller. It runs detached from the context of the call that started it, see
+// api.Client.Detach, so a caller that cancels or reaches its deadline only stops
+// waiting itself: the others still get the data, which is cached. With
+// StaleWhileRevalidate, expired data within the grace window is returned immediately
+// while a background refresh updates the cache. A context from WithFreshData skips the
+// cached data and always fetches, and get is given the max age of a context from
+// WithMaxAge so that older data counts as a miss.
+func cachedFetch[T any](ctx context.Context, c *client, key string, get func(maxAge time.Duration) (T, cache.State), fetch func(context.Context) (T, error), set func(T)) (T, error) {
+	load := func(ctx context.Context) (T, error) {
+		result := c.inflight.DoChan(key, func() (interface{}, error) {
+			shared, cancel := c.Client.Detach(ctx)
+			defer cancel()
+			data, err := fetch(shared)
+			if err == nil && c.cache != nil {
+				set(data)
+			}
+			return data, err
+		})
+		var zero T
+		select {
+		case r := <-result:
+			if r.Err != nil {
+				return zero, r.Err
+			}
+			return r.Val.(T), nil
+		case <-ctx.Done():
+			return zero, c.Client.ContextError(ctx)
+		}
+	}
+
+	if c.cache != nil && !wantsFreshData(ctx) {
+		if data, state := get(maxAgeFrom(ctx)); state != cache.Miss {
+			if state == cache.Stale {
+				c.revalidate(key, func() error {
+					_, err := load(c.Lifetime())
+					return err
+				})
+			}
+			return data, nil
+		}
+	}
+
+	return load(ctx)
+}
+
+// settledFetch is cachedFetch for the collections quoted per settlement term: it reads
+// and fills the cache of the settlement requested by ctx, under a key of its own, and
+// a background revalidation keeps requesting that settlement
+func settledFetch[T any](ctx context.Context, c *client, category string, get func(*cache.Cache, time.Duration) ([]T, cache.State), fetch func(context.Context) ([]T, error), set func(*cache.Cache, []T)) ([]T, error) {
+	settlement := api.SettlementFrom(ctx)
+	store, err := c.settlementCache(settlement)
+	if err != nil {
+		return nil, err
+	}
+	key := category
+	if settlement != SettlementT1 {
+		key += ":" + string(settlement)
+	}
+	return cachedFetch(ctx, c, key,
+		func(maxAge time.Duration) ([]T, cache.State) { return get(store, maxAge) },
+		func(ctx context.Context) ([]T, error) { return fetch(api.WithSettlement(ctx, settlement)) },
+		func(data []T) { set(store, data) })
+}
+
+// settlementCache returns the cache holding the quotes of settlement: the main cache
+// for T1 (or nil when caching is disabled), and a cache of its own, persisted to a
+// subdirectory of CacheDir, for T0 and T2
+func (c *client) settlementCache(settlement Settlement) (*cache.Cache, error) {
+	if !settlement.Valid() {
+		return nil, api.InvalidSettlement(settlement)
+	}
+	if settlement == SettlementT1 || c.cache == nil {
+		return c.cache, nil
+	}
+
+	c.settlementMu.Lock()
+	defer c.settlementMu.Unlock()
+	if store, exists := c.settlementCaches[settlement]; exists {
+		return store, nil
+	}
+	opts := c.cacheOptions
+	if opts.Dir != "" {
+		opts.Dir = filepath.Join(opts.Dir, string(settlement))
+	}
+	if c.settlementCaches == nil {
+		c.settlementCaches = make(map[Settlement]*cache.Cache)
+	}
+	store := cache.New(opts)
+	c.settlementCaches[settlement] = store
+	return store, nil
+}
+
+// caches returns the main cache followed by the settlement caches created so far, or
+// nil when caching is disabled
+func (c *client) caches() []*cache.Cache {
+	if c.cache == nil {
+		return nil
+	}
+	c.settlementMu.Lock()
+	defer c.settlementMu.Unlock()
+	caches := []*cache.Cache{c.cache}
+	for _, settlement := range []Settlement{SettlementT0, SettlementT2} {
+		if store, exists := c.settlementCaches[settlement]; exists {
+			caches = append(caches, store)
+		}
+	}
+	return caches
+}
+
+// wantsFreshData reports whether ctx was derived from WithFreshData
+func wantsFreshData(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshDataKey{}).(bool)
+	return fresh
+}
+
+// maxAgeFrom returns the max age set on ctx by WithMaxAge, or zero for none
+func maxAgeFrom(ctx context.Context) time.Duration {
+	maxAge, _ := ctx.Value(maxAgeKey{}).(time.Duration)
+	return maxAge
+}
+
+// cloned returns a copy of a collection read through cachedFetch. Collections returned
+// by cachedFetch are shared with the cache and every concurrent caller, and the cache's
+// symbol indexes are tied to them, so internal code reads them in place while the
+// public methods hand out copies that callers are free to sort or modify.
+func cloned[T any](data []T, err error) ([]T, error) {
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(data), nil
+}
+
+// iterate returns an iterator over a collection read through cachedFetch. It yields
+// the items of the shared slice by value, so no copy of the collection is made; a
+// failed read, or ctx being done while iterating, yields a single error and stops.
+func iterate[T any](ctx context.Context, load func(context.Context) ([]T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		items, err := load(ctx)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		for _, item := range items {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+// revalidate runs refresh in the background unless a refresh of key is already running.
+// A failed refresh leaves the cached data untouched.
+func (c *client) revalidate(key string, refresh func() error) {
+	if _, running := c.refreshing.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+
+	go func() {
+		defer c.refreshing.Delete(key)
+		if err := refresh(); err != nil {
+			c.logger.Warn("Background cache refresh failed",
+				LogField{Key: "category", Value: key},
+				LogField{Key: "error", Value: err.Error()})
+		}
+	}()
+}
+
 // GetBluechips retrieves all leading equity securities (blue chip stocks).
 // These are the most liquid and actively traded Argentine stocks.
 // Results are cached for 5 minutes to improve performance.
@@ -275,22 +618,12 @@ func (l *loggerAdapter) Error(msg string, fields ...api.LogField) {
 //	fmt.Printf("📉 Biggest Loser: %s (%.2f%%)\n",
 //		biggestLoser.Symbol, biggestLoser.Change)
 func (c *client) GetBluechips(ctx context.Context) ([]Security, error) {
-	if c.cache != nil {
-		if cached, found := c.cache.GetBluechips(); found {
-			return cached, nil
-		}
-	}
-
-	data, err := c.Client.GetBluechips(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	if c.cache != nil {
-		c.cache.SetBluechips(data)
-	}
+	return cloned(c.cachedBluechips(ctx))
+}
 
-	return data, nil
+// cachedBluechips returns the blue chips shared with the cache, see cloned
+func (c *client) cachedBluechips(ctx context.Context) ([]Security, error) {
+	return settledFetch(ctx, c, CacheBluechips, (*cache.Cache).GetBluechips, c.Client.GetBluechips, (*cache.Cache).SetBluechips)
 }
 
 // GetCedears retrieves all CEDEAR securities (US stocks traded in Argentina).
@@ -334,222 +667,263 @@ func (c *client) GetBluechips(ctx context.Context) ([]Security, error) {
 //
 // For getting a single CEDEAR, use GetCedear() instead for better performance.
 func (c *client) GetCedears(ctx context.Context) ([]Security, error) {
-	if c.cache != nil {
-		if cached, found := c.cache.GetCedears(); found {
-			return cached, nil
-		}
-	}
+	return cloned(c.cachedCedears(ctx))
+}
+
+// cachedCedears returns the CEDEARs shared with the cache, see cloned
+func (c *client) cachedCedears(ctx context.Context) ([]Security, error) {
+	return settledFetch(ctx, c, CacheCedears, (*cache.Cache).GetCedears, c.Client.GetCedears, (*cache.Cache).SetCedears)
+}
 
-	data, err := c.Client.GetCedears(ctx)
+// GetCedearsPage returns a page of at most limit CEDEARs starting at offset, along with
+// the total number of CEDEARs. BYMA's CEDEAR endpoint has no pagination, so the full
+// collection is fetched (and cached) as GetCedears does and paged client-side; the page
+// is a copy and doesn't keep the rest of the list alive. A limit of zero returns every
+// CEDEAR from offset on, and negative values return an ErrInvalidPage error.
+//
+// Example usage:
+//
+//	const pageSize = 25
+//	page, total, err := client.GetCedearsPage(ctx, 2*pageSize, pageSize) // third page
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Showing %d of %d CEDEARs\n", len(page), total)
+func (c *client) GetCedearsPage(ctx context.Context, offset, limit int) ([]Security, int, error) {
+	cedears, err := c.cachedCedears(ctx)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
+	return Paginate(cedears, offset, limit)
+}
 
-	if c.cache != nil {
-		c.cache.SetCedears(data)
-	}
+// IterCedears returns an iterator over the CEDEARs, for code that streams through the
+// collection once, e.g. to filter or aggregate it. Unlike GetCedears it doesn't copy
+// the cached collection: each CEDEAR is yielded as a copy when the loop reaches it.
+// The collection is fetched (or read from the cache) when the loop starts; when that
+// fails, or ctx is done while iterating, the loop receives a single error and ends.
+// IterBluechips, IterGalpones, IterEtfs, IterBonds, IterShortTermBonds,
+// IterCorporateBonds, IterOptions and IterFutures do the same for their collections.
+//
+// Example usage:
+//
+//	var turnover float64
+//	for cedear, err := range client.IterCedears(ctx) {
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		if cedear.Change > 0 {
+//			turnover += cedear.Turnover
+//		}
+//	}
+func (c *client) IterCedears(ctx context.Context) iter.Seq2[Security, error] {
+	return iterate(ctx, c.cachedCedears)
+}
+
+// IterBluechips returns an iterator over the blue chips, see IterCedears
+func (c *client) IterBluechips(ctx context.Context) iter.Seq2[Security, error] {
+	return iterate(ctx, c.cachedBluechips)
+}
+
+// IterGalpones returns an iterator over the general equities, see IterCedears
+func (c *client) IterGalpones(ctx context.Context) iter.Seq2[Security, error] {
+	return iterate(ctx, c.cachedGalpones)
+}
+
+// IterEtfs returns an iterator over the ETFs, see IterCedears
+func (c *client) IterEtfs(ctx context.Context) iter.Seq2[Security, error] {
+	return iterate(ctx, c.cachedEtfs)
+}
+
+// IterBonds returns an iterator over the government bonds, see IterCedears
+func (c *client) IterBonds(ctx context.Context) iter.Seq2[Bond, error] {
+	return iterate(ctx, c.cachedBonds)
+}
+
+// IterShortTermBonds returns an iterator over the short-term bonds, see IterCedears
+func (c *client) IterShortTermBonds(ctx context.Context) iter.Seq2[Bond, error] {
+	return iterate(ctx, c.cachedShortTermBonds)
+}
+
+// IterCorporateBonds returns an iterator over the corporate bonds, see IterCedears
+func (c *client) IterCorporateBonds(ctx context.Context) iter.Seq2[Bond, error] {
+	return iterate(ctx, c.cachedCorporateBonds)
+}
+
+// IterOptions returns an iterator over the options, see IterCedears
+func (c *client) IterOptions(ctx context.Context) iter.Seq2[Option, error] {
+	return iterate(ctx, c.cachedOptions)
+}
 
-	return data, nil
+// IterFutures returns an iterator over the futures, see IterCedears
+func (c *client) IterFutures(ctx context.Context) iter.Seq2[Future, error] {
+	return iterate(ctx, c.cachedFutures)
 }
 
 // GetGalpones with caching support
 func (c *client) GetGalpones(ctx context.Context) ([]Security, error) {
-	if c.cache != nil {
-		if cached, found := c.cache.GetGalpones(); found {
-			return cached, nil
-		}
-	}
+	return cloned(c.cachedGalpones(ctx))
+}
 
-	data, err := c.Client.GetGalpones(ctx)
-	if err != nil {
-		return nil, err
-	}
+// cachedGalpones returns the general equities shared with the cache, see cloned
+func (c *client) cachedGalpones(ctx context.Context) ([]Security, error) {
+	return settledFetch(ctx, c, CacheGalpones, (*cache.Cache).GetGalpones, c.Client.GetGalpones, (*cache.Cache).SetGalpones)
+}
 
-	if c.cache != nil {
-		c.cache.SetGalpones(data)
-	}
+// GetEtfs retrieves all exchange-traded funds listed on BYMA.
+// Results are cached like every other collection.
+//
+// Example usage:
+//
+//	etfs, err := client.GetEtfs(ctx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, etf := range etfs {
+//		fmt.Printf("%s: $%.2f\n", etf.Symbol, etf.Last)
+//	}
+func (c *client) GetEtfs(ctx context.Context) ([]Security, error) {
+	return cloned(c.cachedEtfs(ctx))
+}
 
-	return data, nil
+// cachedEtfs returns the ETFs shared with the cache, see cloned
+func (c *client) cachedEtfs(ctx context.Context) ([]Security, error) {
+	return settledFetch(ctx, c, CacheEtfs, (*cache.Cache).GetEtfs, c.Client.GetEtfs, (*cache.Cache).SetEtfs)
 }
 
 // GetBonds with caching support
 func (c *client) GetBonds(ctx context.Context) ([]Bond, error) {
-	if c.cache != nil {
-		if cached, found := c.cache.GetBonds(); found {
-			return cached, nil
-		}
-	}
-
-	data, err := c.Client.GetBonds(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	if c.cache != nil {
-		c.cache.SetBonds(data)
-	}
+	return cloned(c.cachedBonds(ctx))
+}
 
-	return data, nil
+// cachedBonds returns the government bonds shared with the cache, see cloned
+func (c *client) cachedBonds(ctx context.Context) ([]Bond, error) {
+	return settledFetch(ctx, c, CacheBonds, (*cache.Cache).GetBonds, c.Client.GetBonds, (*cache.Cache).SetBonds)
 }
 
 // GetShortTermBonds with caching support
 func (c *client) GetShortTermBonds(ctx context.Context) ([]Bond, error) {
-	if c.cache != nil {
-		if cached, found := c.cache.GetShortTermBonds(); found {
-			return cached, nil
-		}
-	}
-
-	data, err := c.Client.GetShortTermBonds(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	if c.cache != nil {
-		c.cache.SetShortTermBonds(data)
-	}
+	return cloned(c.cachedShortTermBonds(ctx))
+}
 
-	return data, nil
+// cachedShortTermBonds returns the short-term bonds shared with the cache, see cloned
+func (c *client) cachedShortTermBonds(ctx context.Context) ([]Bond, error) {
+	return settledFetch(ctx, c, CacheShortTermBonds, (*cache.Cache).GetShortTermBonds, c.Client.GetShortTermBonds, (*cache.Cache).SetShortTermBonds)
 }
 
 // GetCorporateBonds with caching support
 func (c *client) GetCorporateBonds(ctx context.Context) ([]Bond, error) {
-	if c.cache != nil {
-		if cached, found := c.cache.GetCorporateBonds(); found {
-			return cached, nil
-		}
-	}
-
-	data, err := c.Client.GetCorporateBonds(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	if c.cache != nil {
-		c.cache.SetCorporateBonds(data)
-	}
+	return cloned(c.cachedCorporateBonds(ctx))
+}
 
-	return data, nil
+// cachedCorporateBonds returns the corporate bonds shared with the cache, see cloned
+func (c *client) cachedCorporateBonds(ctx context.Context) ([]Bond, error) {
+	return settledFetch(ctx, c, CacheCorporateBonds, (*cache.Cache).GetCorporateBonds, c.Client.GetCorporateBonds, (*cache.Cache).SetCorporateBonds)
 }
 
 // GetOptions with caching support
 func (c *client) GetOptions(ctx context.Context) ([]Option, error) {
-	if c.cache != nil {
-		if cached, found := c.cache.GetOptions(); found {
-			return cached, nil
-		}
-	}
-
-	data, err := c.Client.GetOptions(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	if c.cache != nil {
-		c.cache.SetOptions(data)
-	}
+	return cloned(c.cachedOptions(ctx))
+}
 
-	return data, nil
+// cachedOptions returns the options shared with the cache, see cloned
+func (c *client) cachedOptions(ctx context.Context) ([]Option, error) {
+	return cachedFetch(ctx, c, CacheOptions, c.cache.GetOptions, c.Client.GetOptions, c.cache.SetOptions)
 }
 
 // GetFutures with caching support
 func (c *client) GetFutures(ctx context.Context) ([]Future, error) {
-	if c.cache != nil {
-		if cached, found := c.cache.GetFutures(); found {
-			return cached, nil
-		}
-	}
-
-	data, err := c.Client.GetFutures(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	if c.cache != nil {
-		c.cache.SetFutures(data)
-	}
+	return cloned(c.cachedFutures(ctx))
+}
 
-	return data, nil
+// cachedFutures returns the futures shared with the cache, see cloned
+func (c *client) cachedFutures(ctx context.Context) ([]Future, error) {
+	return cachedFetch(ctx, c, CacheFutures, c.cache.GetFutures, c.Client.GetFutures, c.cache.SetFutures)
 }
 
 // GetIndices with caching support
 func (c *client) GetIndices(ctx context.Context) ([]Index, error) {
-	if c.cache != nil {
-		if cached, found := c.cache.GetIndices(); found {
-			return cached, nil
-		}
-	}
-
-	data, err := c.Client.GetIndices(ctx)
-	if err != nil {
-		return nil, err
-	}
+	return cloned(c.cachedIndices(ctx))
+}
 
-	if c.cache != nil {
-		c.cache.SetIndices(data)
-	}
+// cachedIndices returns the indices shared with the cache, see cloned
+func (c *client) cachedIndices(ctx context.Context) ([]Index, error) {
+	return cachedFetch(ctx, c, CacheIndices, c.cache.GetIndices, c.fetchIndices, c.cache.SetIndices)
+}
 
-	return data, nil
+// fetchIndices fetches the indices from BYMA, refreshing the translation dictionary
+// first if needed so their descriptions are translated, see maintainDictionary
+func (c *client) fetchIndices(ctx context.Context) ([]Index, error) {
+	c.maintainDictionary(ctx)
+	return c.Client.GetIndices(ctx)
 }
 
 // MarketResume with caching support
 func (c *client) MarketResume(ctx context.Context) ([]MarketSummary, error) {
-	if c.cache != nil {
-		if cached, found := c.cache.GetMarketSummary(); found {
-			return cached, nil
-		}
-	}
-
-	data, err := c.Client.MarketResume(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	if c.cache != nil {
-		c.cache.SetMarketSummary(data)
-	}
+	return cloned(c.cachedMarketResume(ctx))
+}
 
-	return data, nil
+// cachedMarketResume returns the market summary shared with the cache, see cloned
+func (c *client) cachedMarketResume(ctx context.Context) ([]MarketSummary, error) {
+	return cachedFetch(ctx, c, CacheMarketSummary, c.cache.GetMarketSummary, c.Client.MarketResume, c.cache.SetMarketSummary)
 }
 
 // GetNews with caching support
 func (c *client) GetNews(ctx context.Context) ([]News, error) {
-	if c.cache != nil {
-		if cached, found := c.cache.GetNews(); found {
-			return cached, nil
-		}
-	}
-
-	data, err := c.Client.GetNews(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	if c.cache != nil {
-		c.cache.SetNews(data)
-	}
+	return cloned(c.cachedNews(ctx))
+}
 
-	return data, nil
+// cachedNews returns the news shared with the cache, see cloned
+func (c *client) cachedNews(ctx context.Context) ([]News, error) {
+	return cachedFetch(ctx, c, CacheNews, c.cache.GetNews, c.Client.GetNews, c.cache.SetNews)
 }
 
-// GetIncomeStatement with caching support (per ticker)
-func (c *client) GetIncomeStatement(ctx context.Context, ticker string) ([]IncomeStatement, error) {
-	if c.cache != nil {
-		if cached, found := c.cache.GetIncomeStatement(ticker); found {
-			return cached, nil
-		}
+// GetNewsFor returns the news published by an issuer (emisor, the News.Titulo field)
+// between from and to, both included, filtered from the cached news feed. The issuer
+// is matched case-insensitively and an empty one matches all of them; a zero from or
+// to leaves that side of the range open. News whose date couldn't be parsed are left
+// out whenever a bound is given. from after to returns an ErrInvalidDateRange error.
+//
+// Example usage:
+//
+//	lastWeek := time.Now().AddDate(0, 0, -7)
+//	news, err := client.GetNewsFor(ctx, "GRUPO FINANCIERO GALICIA", lastWeek, time.Time{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, item := range news {
+//		fmt.Printf("%s: %s\n", item.Fecha.Format(time.DateOnly), item.Descripcion)
+//	}
+func (c *client) GetNewsFor(ctx context.Context, emisor string, from, to time.Time) ([]News, error) {
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return nil, NewBYMAError(ErrInvalidDateRange.Code, fmt.Sprintf("from (%s) is after to (%s)",
+			from.Format(time.DateOnly), to.Format(time.DateOnly)))
 	}
-
-	data, err := c.Client.GetIncomeStatement(ctx, ticker)
+	news, err := c.cachedNews(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return helpers.FilterNews(news, emisor, from, to), nil
+}
 
-	if c.cache != nil {
-		c.cache.SetIncomeStatement(ticker, data)
-	}
+// GetIncomeStatement with caching support (per ticker)
+func (c *client) GetIncomeStatement(ctx context.Context, ticker string) ([]IncomeStatement, error) {
+	return cloned(c.cachedIncomeStatement(ctx, ticker))
+}
 
-	return data, nil
+// cachedIncomeStatement returns the income statements of ticker shared with the cache,
+// see cloned
+func (c *client) cachedIncomeStatement(ctx context.Context, ticker string) ([]IncomeStatement, error) {
+	return cachedFetch(ctx, c, CacheIncomeStatements+":"+ticker,
+		func(maxAge time.Duration) ([]IncomeStatement, cache.State) {
+			return c.cache.GetIncomeStatement(ticker, maxAge)
+		},
+		func(ctx context.Context) ([]IncomeStatement, error) {
+			return c.Client.GetIncomeStatement(ctx, ticker)
+		},
+		func(data []IncomeStatement) {
+			c.cache.SetIncomeStatement(ticker, data)
+		})
 }
 
 // =============================================================================
@@ -558,7 +932,7 @@ func (c *client) GetIncomeStatement(ctx context.Context, ticker string) ([]Incom
 
 // GetSecurity finds a security by symbol across all security types.
 // This is the recommended method for security lookup as it searches across
-// CEDEARs, blue chips, and general equity automatically.
+// CEDEARs, blue chips, general equity and ETFs automatically.
 //
 // Example usage:
 //
@@ -589,33 +963,128 @@ func (c *client) GetIncomeStatement(ctx context.Context, ticker string) ([]Incom
 // The method leverages caching, so subsequent calls for the same or different
 // symbols will be much faster if the underlying collections are cached.
 func (c *client) GetSecurity(ctx context.Context, symbol string) (*Security, error) {
-	// Get all security collections (use cache when available)
-	bluechips, err := c.GetBluechips(ctx)
+	normalized := helpers.NormalizeSymbol(symbol)
+	store, err := c.settlementCache(api.SettlementFrom(ctx))
 	if err != nil {
 		return nil, err
 	}
-
-	cedears, err := c.GetCedears(ctx)
-	if err != nil {
-		return nil, err
+	if store != nil && !wantsFreshData(ctx) && store.IsMissingSecurity(normalized) {
+		return nil, NewBYMAError(ErrInvalidTicker.Code, fmt.Sprintf("security %s not found", symbol))
 	}
 
-	galpones, err := c.GetGalpones(ctx)
+	// Get all security collections (use cache when available)
+	collections, err := c.loadSecurityCollections(ctx, c.cachedBluechips, c.cachedCedears, c.cachedGalpones, c.cachedEtfs)
 	if err != nil {
 		return nil, err
 	}
 
-	return helpers.FindSecurityBySymbol(symbol, bluechips, cedears, galpones)
+	for _, securities := range collections {
+		if security, err := helpers.FindSecurityIndexed(symbol, securities, c.securityIndex(securities)); err == nil {
+			return security, nil
+		}
+	}
+
+	if store != nil {
+		store.SetMissingSecurity(normalized)
+	}
+	return nil, NewBYMAError(ErrInvalidTicker.Code, fmt.Sprintf("security %s not found", symbol))
+}
+
+// GetMultipleSecuritiesPartial works like GetMultipleSecurities but never fails as a
+// whole: the blue chip, CEDEAR, general equity and ETF collections are loaded independently,
+// and the symbols found in those that loaded are returned along with a
+// *CollectionError for each one that didn't, e.g. because ctx was cancelled while it
+// was being fetched or its endpoint failed. Cached collections still load once ctx is
+// done. When every collection loads, the error slice is nil. Symbols missing from the
+// result may belong to a collection that failed, so check the errors before reporting
+// them as unknown.
+//
+// Example usage:
+//
+//	// Best-effort dashboard: one flaky endpoint doesn't blank the whole watchlist
+//	quotes, errs := client.GetMultipleSecuritiesPartial(ctx, watchlist)
+//	for _, err := range errs {
+//		log.Printf("stale section: %v", err)
+//	}
+//	for symbol, quote := range quotes {
+//		fmt.Printf("%s: $%.2f\n", symbol, quote.Last)
+//	}
+func (c *client) GetMultipleSecuritiesPartial(ctx context.Context, symbols []string) (map[string]*Security, []*CollectionError) {
+	categories := []string{CacheBluechips, CacheCedears, CacheGalpones, CacheEtfs}
+	loaders := []func(context.Context) ([]Security, error){c.cachedBluechips, c.cachedCedears, c.cachedGalpones, c.cachedEtfs}
+
+	// Unlike loadSecurityCollections, a failing collection doesn't cancel the others
+	var (
+		g           errgroup.Group
+		collections = make([][]Security, len(loaders))
+		errs        = make([]error, len(loaders))
+	)
+	for i, load := range loaders {
+		g.Go(func() error {
+			collections[i], errs[i] = load(ctx)
+			return nil
+		})
+	}
+	g.Wait()
+
+	var failures []*CollectionError
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, &CollectionError{Collection: categories[i], Err: err})
+		}
+	}
+	results, _ := helpers.GetMultipleSecuritiesDetailed(symbols, collections...)
+	return results, failures
+}
+
+// loadSecurityCollections fetches the given collections concurrently, so a cold cache
+// costs a single round trip instead of one per collection. The first error cancels the
+// fetches still in flight and is returned; the cache is safe for concurrent writes.
+func (c *client) loadSecurityCollections(ctx context.Context, loaders ...func(context.Context) ([]Security, error)) ([][]Security, error) {
+	collections := make([][]Security, len(loaders))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, load := range loaders {
+		g.Go(func() error {
+			securities, err := load(gctx)
+			collections[i] = securities
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
+
+// securityIndex returns the cache's symbol index over securities, or nil when they are
+// not a cached collection (e.g. with caching disabled) and must be scanned instead
+func (c *client) securityIndex(securities []Security) helpers.SymbolIndex {
+	for _, store := range c.caches() {
+		if index := store.SecurityIndex(securities); index != nil {
+			return index
+		}
+	}
+	return nil
+}
+
+// bondIndex returns the cache's symbol index over bonds, like securityIndex
+func (c *client) bondIndex(bonds []Bond) helpers.SymbolIndex {
+	for _, store := range c.caches() {
+		if index := store.BondIndex(bonds); index != nil {
+			return index
+		}
+	}
+	return nil
 }
 
 // GetBluechip finds a specific blue chip security by symbol
 func (c *client) GetBluechip(ctx context.Context, symbol string) (*Security, error) {
-	bluechips, err := c.GetBluechips(ctx)
+	bluechips, err := c.cachedBluechips(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return helpers.FindSecurityInCollection(symbol, bluechips)
+	return helpers.FindSecurityIndexed(symbol, bluechips, c.securityIndex(bluechips))
 }
 
 // GetCedear finds a specific CEDEAR (US stock) by symbol.
@@ -649,47 +1118,341 @@ func (c *client) GetBluechip(ctx context.Context, symbol string) (*Security, err
 //
 // The function uses caching, so repeated calls are very fast.
 func (c *client) GetCedear(ctx context.Context, symbol string) (*Security, error) {
-	cedears, err := c.GetCedears(ctx)
+	cedears, err := c.cachedCedears(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return helpers.FindSecurityIndexed(symbol, cedears, c.securityIndex(cedears))
+}
+
+// GetCedearWithUSD finds a CEDEAR by symbol and returns its ARS price together with
+// the implied USD price, derived using the CCL rate from ClientOptions.CCLSource.
+//
+// A missing CCL is not an error: when no source is configured, the source fails, or
+// it returns a non-positive rate, the quote is returned with USDAvailable set to false
+// and zero USD fields.
+//
+// The quote also carries the CEDEAR's conversion ratio and the implied USD price of one
+// share of the underlying when the symbol is listed in ClientOptions.CedearRatios;
+// otherwise Ratio and UnderlyingUSD are zero.
+//
+// Example usage:
+//
+//	client := openbymadata.NewClient(&openbymadata.ClientOptions{
+//		CCLSource:    openbymadata.FixedCCL(1200),
+//		CedearRatios: map[string]float64{"AAPL": 20},
+//	})
+//
+//	quote, err := client.GetCedearWithUSD(ctx, "AAPL")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if quote.USDAvailable {
+//		fmt.Printf("AAPL: ARS %.2f | USD %.2f (CCL %.2f) | share USD %.2f (ratio %g)\n",
+//			quote.LastARS, quote.LastUSD, quote.CCL, quote.UnderlyingUSD, quote.Ratio)
+//	}
+func (c *client) GetCedearWithUSD(ctx context.Context, symbol string) (*CedearQuote, error) {
+	cedear, err := c.GetCedear(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &CedearQuote{
+		Symbol:  cedear.Symbol,
+		LastARS: cedear.Last,
+	}
+	if ratio := c.ratios[cedear.Symbol]; ratio > 0 {
+		quote.Ratio = ratio
+	}
+
+	if c.cclSource == nil {
+		return quote, nil
+	}
+
+	ccl, err := c.cclSource.GetCCL(ctx)
+	if err != nil {
+		c.logger.Warn("Failed to get CCL rate", LogField{Key: "error", Value: err.Error()})
+		return quote, nil
+	}
+	if ccl <= 0 {
+		return quote, nil
+	}
+
+	quote.CCL = ccl
+	quote.LastUSD = cedear.Last / ccl
+	quote.USDAvailable = true
+	quote.UnderlyingUSD = quote.LastUSD * quote.Ratio
+
+	return quote, nil
+}
+
+// GetAnySecurity finds an instrument by symbol without knowing its type in advance.
+// It searches equities, CEDEARs and ETFs first, then government, short-term and
+// corporate bonds, then indices, and reports the asset class it matched. Collections
+// are only fetched when the symbol wasn't found in the previous ones.
+//
+// Example usage:
+//
+//	match, err := client.GetAnySecurity(ctx, "AL30")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	switch match.AssetClass {
+//	case openbymadata.AssetClassBond, openbymadata.AssetClassShortTermBond, openbymadata.AssetClassCorporateBond:
+//		fmt.Printf("%s (bond): $%.2f\n", match.Symbol, match.Bond.Last)
+//	case openbymadata.AssetClassIndex:
+//		fmt.Printf("%s (index): %.2f\n", match.Symbol, match.Index.Last)
+//	default:
+//		fmt.Printf("%s (%s): $%.2f\n", match.Symbol, match.AssetClass, match.Security.Last)
+//	}
+func (c *client) GetAnySecurity(ctx context.Context, symbol string) (*AnySecurity, error) {
+	securityLookups := []struct {
+		class AssetClass
+		get   func(context.Context) ([]Security, error)
+	}{
+		{AssetClassEquity, c.cachedBluechips},
+		{AssetClassCedear, c.cachedCedears},
+		{AssetClassEquity, c.cachedGalpones},
+		{AssetClassETF, c.cachedEtfs},
+	}
+	for _, lookup := range securityLookups {
+		securities, err := lookup.get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if security, err := helpers.FindSecurityIndexed(symbol, securities, c.securityIndex(securities)); err == nil {
+			return &AnySecurity{Symbol: security.Symbol, AssetClass: lookup.class, Security: security}, nil
+		}
+	}
+
+	bondLookups := []struct {
+		class AssetClass
+		get   func(context.Context) ([]Bond, error)
+	}{
+		{AssetClassBond, c.cachedBonds},
+		{AssetClassShortTermBond, c.cachedShortTermBonds},
+		{AssetClassCorporateBond, c.cachedCorporateBonds},
+	}
+	for _, lookup := range bondLookups {
+		bonds, err := lookup.get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if bond, err := helpers.FindBondIndexed(symbol, bonds, c.bondIndex(bonds)); err == nil {
+			return &AnySecurity{Symbol: bond.Symbol, AssetClass: lookup.class, Bond: bond}, nil
+		}
+	}
+
+	indices, err := c.cachedIndices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index, err := helpers.FindIndexBySymbol(symbol, indices)
+	if err != nil {
+		return nil, NewBYMAError(ErrInvalidTicker.Code, fmt.Sprintf("security %s not found", symbol))
+	}
+	return &AnySecurity{Symbol: index.Symbol, AssetClass: AssetClassIndex, Index: index}, nil
+}
+
+// GetSecurityType reports the asset class of a symbol without returning its quote,
+// telling blue chips apart from general equity and also covering options and futures.
+// Collections are searched in the same order as GetAnySecurity, followed by options and
+// futures, and only fetched when the symbol wasn't found in the previous ones. A symbol
+// that is not listed anywhere returns AssetClassNotFound and a nil error; errors are
+// only returned when a collection cannot be fetched.
+//
+// Example usage:
+//
+//	class, err := client.GetSecurityType(ctx, symbol)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	switch class {
+//	case openbymadata.AssetClassCedear:
+//		quote, _ := client.GetCedearWithUSD(ctx, symbol)
+//		fmt.Printf("%s: ARS %.2f | USD %.2f\n", symbol, quote.LastARS, quote.LastUSD)
+//	case openbymadata.AssetClassNotFound:
+//		fmt.Printf("%s is not listed\n", symbol)
+//	default:
+//		fmt.Printf("%s is a %s\n", symbol, class)
+//	}
+func (c *client) GetSecurityType(ctx context.Context, symbol string) (AssetClass, error) {
+	securityLookups := []struct {
+		class AssetClass
+		get   func(context.Context) ([]Security, error)
+	}{
+		{AssetClassBluechip, c.cachedBluechips},
+		{AssetClassCedear, c.cachedCedears},
+		{AssetClassGeneralEquity, c.cachedGalpones},
+		{AssetClassETF, c.cachedEtfs},
+	}
+	for _, lookup := range securityLookups {
+		securities, err := lookup.get(ctx)
+		if err != nil {
+			return "", err
+		}
+		if _, err := helpers.FindSecurityIndexed(symbol, securities, c.securityIndex(securities)); err == nil {
+			return lookup.class, nil
+		}
+	}
+
+	bondLookups := []struct {
+		class AssetClass
+		get   func(context.Context) ([]Bond, error)
+	}{
+		{AssetClassBond, c.cachedBonds},
+		{AssetClassShortTermBond, c.cachedShortTermBonds},
+		{AssetClassCorporateBond, c.cachedCorporateBonds},
+	}
+	for _, lookup := range bondLookups {
+		bonds, err := lookup.get(ctx)
+		if err != nil {
+			return "", err
+		}
+		if _, err := helpers.FindBondIndexed(symbol, bonds, c.bondIndex(bonds)); err == nil {
+			return lookup.class, nil
+		}
+	}
+
+	indices, err := c.cachedIndices(ctx)
+	if err != nil {
+		return "", err
+	}
+	if _, err := helpers.FindIndexBySymbol(symbol, indices); err == nil {
+		return AssetClassIndex, nil
+	}
+
+	options, err := c.cachedOptions(ctx)
+	if err != nil {
+		return "", err
+	}
+	if _, err := helpers.FindOptionBySymbol(symbol, options); err == nil {
+		return AssetClassOption, nil
+	}
+
+	futures, err := c.cachedFutures(ctx)
+	if err != nil {
+		return "", err
+	}
+	if _, err := helpers.FindFutureBySymbol(symbol, futures); err == nil {
+		return AssetClassFuture, nil
+	}
+
+	return AssetClassNotFound, nil
+}
+
+// QuoteAge reports how long ago a symbol last traded, based on the trade time BYMA
+// reports for its quote. Equities, CEDEARs, ETFs, bonds, options and futures are
+// searched in the same order as GetSecurityType. It returns an ErrInvalidTicker error
+// when the symbol is not listed and an ErrNoTradeTime error when its quote has no
+// usable trade time, as is the case for indices.
+//
+// Example usage:
+//
+//	age, err := client.QuoteAge(ctx, "GGAL")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if age > 15*time.Minute {
+//		fmt.Printf("GGAL quote is stale: last trade %s ago\n", age.Round(time.Second))
+//	}
+func (c *client) QuoteAge(ctx context.Context, symbol string) (time.Duration, error) {
+	tradeTime, err := c.lastTradeTime(ctx, symbol)
+	if err != nil {
+		return 0, err
+	}
+	if tradeTime.IsZero() {
+		return 0, NewBYMAError(ErrNoTradeTime.Code, fmt.Sprintf("no trade time reported for %s", symbol))
+	}
+	return time.Since(tradeTime), nil
+}
+
+// lastTradeTime returns the trade time of a symbol's quote, which is the zero time
+// when BYMA did not report one
+func (c *client) lastTradeTime(ctx context.Context, symbol string) (time.Time, error) {
+	found, err := c.GetAnySecurity(ctx, symbol)
+	if err == nil {
+		switch {
+		case found.Security != nil:
+			return found.Security.DateTime, nil
+		case found.Bond != nil:
+			return found.Bond.DateTime, nil
+		default:
+			return time.Time{}, nil
+		}
+	}
+	if !errors.Is(err, ErrInvalidTicker) {
+		return time.Time{}, err
+	}
+
+	options, err := c.cachedOptions(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if option, err := helpers.FindOptionBySymbol(symbol, options); err == nil {
+		return option.DateTime, nil
+	}
+
+	futures, err := c.cachedFutures(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if future, err := helpers.FindFutureBySymbol(symbol, futures); err == nil {
+		return future.DateTime, nil
+	}
+
+	return time.Time{}, NewBYMAError(ErrInvalidTicker.Code, fmt.Sprintf("security %s not found", symbol))
+}
+
+// GetEtf finds a specific exchange-traded fund by symbol
+func (c *client) GetEtf(ctx context.Context, symbol string) (*Security, error) {
+	etfs, err := c.cachedEtfs(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return helpers.FindSecurityInCollection(symbol, cedears)
+	return helpers.FindSecurityIndexed(symbol, etfs, c.securityIndex(etfs))
 }
 
 // GetGalpone finds a specific general equity security by symbol
 func (c *client) GetGalpone(ctx context.Context, symbol string) (*Security, error) {
-	galpones, err := c.GetGalpones(ctx)
+	galpones, err := c.cachedGalpones(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return helpers.FindSecurityInCollection(symbol, galpones)
+	return helpers.FindSecurityIndexed(symbol, galpones, c.securityIndex(galpones))
 }
 
 // GetBond finds a specific bond by symbol across all bond types
 func (c *client) GetBond(ctx context.Context, symbol string) (*Bond, error) {
-	bonds, err := c.GetBonds(ctx)
+	bonds, err := c.cachedBonds(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	shortBonds, err := c.GetShortTermBonds(ctx)
+	shortBonds, err := c.cachedShortTermBonds(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	corporateBonds, err := c.GetCorporateBonds(ctx)
+	corporateBonds, err := c.cachedCorporateBonds(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return helpers.FindBondBySymbol(symbol, bonds, shortBonds, corporateBonds)
+	for _, collection := range [][]Bond{bonds, shortBonds, corporateBonds} {
+		if bond, err := helpers.FindBondIndexed(symbol, collection, c.bondIndex(collection)); err == nil {
+			return bond, nil
+		}
+	}
+	return nil, NewBYMAError(ErrInvalidTicker.Code, fmt.Sprintf("bond %s not found", symbol))
 }
 
 // GetOption finds a specific option by symbol
 func (c *client) GetOption(ctx context.Context, symbol string) (*Option, error) {
-	options, err := c.GetOptions(ctx)
+	options, err := c.cachedOptions(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -697,9 +1460,35 @@ func (c *client) GetOption(ctx context.Context, symbol string) (*Option, error)
 	return helpers.FindOptionBySymbol(symbol, options)
 }
 
+// GetOptionsForUnderlying returns the options on an underlying, given either as its
+// ticker (e.g. "GGAL") or as the three-letter root of its option symbols (e.g. "GFG").
+// The cached options collection is filtered, so it costs at most one request; narrow the
+// result down with FilterOptionsByExpiration and FilterOptionsByKind, or group it by
+// strike with BuildOptionChain.
+//
+// Example usage:
+//
+//	options, err := client.GetOptionsForUnderlying(ctx, "GGAL")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	december := openbymadata.FilterOptionsByExpiration(options,
+//		time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+//	for _, row := range openbymadata.BuildOptionChain(december) {
+//		fmt.Printf("%10.2f  calls: %d  puts: %d\n", row.Strike, len(row.Calls), len(row.Puts))
+//	}
+func (c *client) GetOptionsForUnderlying(ctx context.Context, underlying string) ([]Option, error) {
+	options, err := c.cachedOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return helpers.FilterOptionsByUnderlying(underlying, options), nil
+}
+
 // GetFuture finds a specific future by symbol
 func (c *client) GetFuture(ctx context.Context, symbol string) (*Future, error) {
-	futures, err := c.GetFutures(ctx)
+	futures, err := c.cachedFutures(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -707,6 +1496,52 @@ func (c *client) GetFuture(ctx context.Context, symbol string) (*Future, error)
 	return helpers.FindFutureBySymbol(symbol, futures)
 }
 
+// GetSecuritiesByBoard returns the equities (blue chips, CEDEARs, general equity and
+// ETFs) listed on the given board/panel, as reported in each security's Panel field.
+// The comparison is case-insensitive; securities without a reported panel are skipped.
+//
+// Example usage:
+//
+//	smeStocks, err := client.GetSecuritiesByBoard(ctx, "PYME")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("SME board: %d securities\n", len(smeStocks))
+func (c *client) GetSecuritiesByBoard(ctx context.Context, board string) ([]Security, error) {
+	collections, err := c.loadSecurityCollections(ctx, c.cachedBluechips, c.cachedCedears, c.cachedGalpones, c.cachedEtfs)
+	if err != nil {
+		return nil, err
+	}
+
+	return helpers.FilterSecuritiesByPanel(board, collections...), nil
+}
+
+// GetAllSecurities returns every equity-like security in a single list: the blue chips,
+// CEDEARs, general equity and ETFs, fetched concurrently (or read from the cache) and
+// merged in that order. A symbol listed in several collections appears once, from the
+// first one; each security's Collection field names the collection it came from. The
+// list is a copy the caller may modify. Any collection failing to load fails the call.
+//
+// Example usage:
+//
+//	// Build an autocomplete index in one shot
+//	all, err := client.GetAllSecurities(ctx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	index := make(map[string]openbymadata.Security, len(all))
+//	for _, security := range all {
+//		index[security.Symbol] = security
+//	}
+//	fmt.Printf("%d securities (AAPL from %s)\n", len(all), index["AAPL"].Collection)
+func (c *client) GetAllSecurities(ctx context.Context) ([]Security, error) {
+	collections, err := c.loadSecurityCollections(ctx, c.cachedBluechips, c.cachedCedears, c.cachedGalpones, c.cachedEtfs)
+	if err != nil {
+		return nil, err
+	}
+	return helpers.UnionSecurities(collections...), nil
+}
+
 // =============================================================================
 // Batch operations
 // =============================================================================
@@ -728,7 +1563,7 @@ func (c *client) GetFuture(ctx context.Context, symbol string) (*Future, error)
 //	}
 //
 //	fmt.Printf("💼 Portfolio (%d securities):\n", len(securities))
-//	totalValue := 0.0
+//	totalValue := decimal.Zero // Sum exact decimals, not floats
 //	for _, symbol := range watchlist {
 //		if security, found := securities[symbol]; found {
 //			changeIcon := "🟢"
@@ -737,12 +1572,12 @@ func (c *client) GetFuture(ctx context.Context, symbol string) (*Future, error)
 //			}
 //			fmt.Printf("  %s %s: $%.2f (%.2f%%)\n",
 //				changeIcon, symbol, security.Last, security.Change)
-//			totalValue += security.Last
+//			totalValue = totalValue.Add(security.LastDecimal())
 //		} else {
 //			fmt.Printf("  ❌ %s: Not found\n", symbol)
 //		}
 //	}
-//	fmt.Printf("💰 Total Portfolio Value: $%.2f\n", totalValue)
+//	fmt.Printf("💰 Total Portfolio Value: $%s\n", totalValue.StringFixed(2))
 //
 // Performance comparison:
 //
@@ -758,43 +1593,571 @@ func (c *client) GetFuture(ctx context.Context, symbol string) (*Future, error)
 //		// Process security...
 //	}
 func (c *client) GetMultipleSecurities(ctx context.Context, symbols []string) (map[string]*Security, error) {
+	results, _, err := c.GetMultipleSecuritiesDetailed(ctx, symbols)
+	return results, err
+}
+
+// GetMultipleSecuritiesDetailed works like GetMultipleSecurities but also returns the
+// symbols that were not found in any collection, in the order they were given, so
+// callers can tell missing tickers apart without diffing the input against the result.
+//
+// Example usage:
+//
+//	quotes, notFound, err := client.GetMultipleSecuritiesDetailed(ctx, []string{"AAPL", "GGAL", "XXXX"})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for symbol, quote := range quotes {
+//		fmt.Printf("%s: $%.2f\n", symbol, quote.Last)
+//	}
+//	for _, symbol := range notFound {
+//		fmt.Printf("%s: NOT FOUND\n", symbol)
+//	}
+func (c *client) GetMultipleSecuritiesDetailed(ctx context.Context, symbols []string) (map[string]*Security, []string, error) {
 	// Pre-load all security collections to use the cache efficiently
-	bluechips, err := c.GetBluechips(ctx)
+	collections, err := c.loadSecurityCollections(ctx, c.cachedBluechips, c.cachedCedears, c.cachedGalpones, c.cachedEtfs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results, notFound := helpers.GetMultipleSecuritiesDetailed(symbols, collections...)
+	return results, notFound, nil
+}
+
+// GetMultipleSecuritiesMaxAge works like GetMultipleSecurities but only reuses cached
+// collections that are at most maxAge old; older ones are fetched again while newer
+// ones are served from cache. It is GetMultipleSecurities called with
+// WithMaxAge(ctx, maxAge), so a max age already set on ctx still applies when it is
+// shorter, and the cache is only updated with the collections fetched: other calls keep
+// being served from it, and a failed fetch leaves the cached data in place. A zero
+// maxAge forces every collection to be refreshed. Without caching it behaves like
+// GetMultipleSecurities.
+//
+// Example usage:
+//
+//	// Portfolio "refresh" button: accept quotes up to 30 seconds old
+//	quotes, err := client.GetMultipleSecuritiesMaxAge(ctx, []string{"AAPL", "GGAL"}, 30*time.Second)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for symbol, quote := range quotes {
+//		fmt.Printf("%s: $%.2f\n", symbol, quote.Last)
+//	}
+func (c *client) GetMultipleSecuritiesMaxAge(ctx context.Context, symbols []string, maxAge time.Duration) (map[string]*Security, error) {
+	return c.GetMultipleSecurities(WithMaxAge(ctx, maxAge), symbols)
+}
+
+// GetMultipleSecuritiesFields works like GetMultipleSecurities but only populates the
+// requested fields of each returned Security; Symbol is always set and every other
+// field is left at its zero value. When no fields are given, full securities are returned.
+//
+// The BYMA endpoints do not support field selection in the request payload, so the
+// collections are fetched (and cached) in full and the projection happens client-side.
+// The returned securities are copies and never alias the cached data.
+//
+// Example usage:
+//
+//	quotes, err := client.GetMultipleSecuritiesFields(ctx, []string{"AAPL", "GGAL"},
+//		openbymadata.FieldLast, openbymadata.FieldVolume)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for symbol, quote := range quotes {
+//		fmt.Printf("%s: $%.2f | Vol: %d\n", symbol, quote.Last, quote.Volume)
+//	}
+func (c *client) GetMultipleSecuritiesFields(ctx context.Context, symbols []string, fields ...SecurityField) (map[string]*Security, error) {
+	securities, err := c.GetMultipleSecurities(ctx, symbols)
 	if err != nil {
 		return nil, err
 	}
 
-	cedears, err := c.GetCedears(ctx)
-	if err != nil {
-		return nil, err
+	if len(fields) == 0 {
+		return securities, nil
+	}
+
+	fieldNames := make([]string, len(fields))
+	for i, field := range fields {
+		fieldNames[i] = string(field)
+	}
+
+	projected := make(map[string]*Security, len(securities))
+	for symbol, security := range securities {
+		p := helpers.ProjectSecurity(*security, fieldNames)
+		projected[symbol] = &p
+	}
+
+	return projected, nil
+}
+
+// GetMultipleBonds retrieves multiple bonds efficiently, fetching the regular,
+// short-term and corporate bond collections once (through the cache) instead of once
+// per symbol as calling GetBond in a loop would. Results are keyed by the symbols as
+// given; symbols that are not listed are left out.
+//
+// Example usage:
+//
+//	bonds, err := client.GetMultipleBonds(ctx, []string{"AL30", "GD30", "TX26"})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for symbol, bond := range bonds {
+//		fmt.Printf("%s: $%.2f\n", symbol, bond.Last)
+//	}
+func (c *client) GetMultipleBonds(ctx context.Context, symbols []string) (map[string]*Bond, error) {
+	bonds, err := c.cachedBonds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	shortBonds, err := c.cachedShortTermBonds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	corporateBonds, err := c.cachedCorporateBonds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return helpers.GetMultipleBonds(symbols, bonds, shortBonds, corporateBonds), nil
+}
+
+// GetMultipleOptions retrieves multiple options with a single (cached) fetch of the
+// options collection. Results are keyed by the symbols as given; symbols that are not
+// listed are left out.
+func (c *client) GetMultipleOptions(ctx context.Context, symbols []string) (map[string]*Option, error) {
+	options, err := c.cachedOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return helpers.GetMultipleOptions(symbols, options), nil
+}
+
+// GetMultipleFutures retrieves multiple futures with a single (cached) fetch of the
+// futures collection. Results are keyed by the symbols as given; symbols that are not
+// listed are left out.
+func (c *client) GetMultipleFutures(ctx context.Context, symbols []string) (map[string]*Future, error) {
+	futures, err := c.cachedFutures(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return helpers.GetMultipleFutures(symbols, futures), nil
+}
+
+// SearchSecurities searches equities, CEDEARs, galpones and ETFs for the given text. By
+// default it matches the text anywhere in the symbol; pass SearchOptions to match by
+// prefix or exactly, to also search descriptions or to limit the results. Matching
+// ignores case, and results are ordered by relevance: exact matches first, then prefix
+// and then substring matches. An unknown mode or field returns an INVALID_SEARCH error.
+//
+// Collections that fail to load are skipped, so a single unavailable endpoint does not
+// hide matches from the others. An empty, non-nil result with a nil error means every
+// collection was fetched and nothing matched. When a fetch failed and nothing matched
+// in the remaining collections (including when all fetches failed), an error is returned
+// instead, since the result cannot be told apart from a transient empty response.
+//
+// Example usage:
+//
+//	// Autocomplete: the first five symbols starting with "GG"
+//	results, err := client.SearchSecurities(ctx, "GG", openbymadata.SearchOptions{
+//		Mode:  openbymadata.SearchPrefix,
+//		Limit: 5,
+//	})
+func (c *client) SearchSecurities(ctx context.Context, searchText string, opts ...SearchOptions) ([]Security, error) {
+	var options SearchOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	switch options.Mode {
+	case "", SearchSubstring, SearchPrefix, SearchExact:
+	default:
+		return nil, NewBYMAError(ErrInvalidSearch.Code, fmt.Sprintf("unknown search mode %q", options.Mode))
+	}
+	fields := make([]string, len(options.Fields))
+	for i, field := range options.Fields {
+		if field != SearchBySymbol && field != SearchByDescription {
+			return nil, NewBYMAError(ErrInvalidSearch.Code, fmt.Sprintf("unknown search field %q", field))
+		}
+		fields[i] = string(field)
+	}
+
+	if slices.Contains(fields, string(SearchByDescription)) {
+		c.maintainDictionary(ctx)
+	}
+
+	// Fetched concurrently, but unlike loadSecurityCollections a failing collection
+	// doesn't cancel the others: whatever loads is still searched
+	loaders := []func(context.Context) ([]Security, error){c.cachedBluechips, c.cachedCedears, c.cachedGalpones, c.cachedEtfs}
+	var g errgroup.Group
+	collections := make([][]Security, len(loaders))
+	errs := make([]error, len(loaders))
+	for i, load := range loaders {
+		g.Go(func() error { collections[i], errs[i] = load(ctx); return nil })
+	}
+	g.Wait()
+
+	results := helpers.SearchSecurities(searchText, string(options.Mode), fields, options.Limit,
+		c.Describe, collections...)
+
+	if err := errors.Join(errs...); err != nil {
+		if len(results) == 0 {
+			return nil, fmt.Errorf("failed to search securities: %w", err)
+		}
+		c.logger.Warn("Search returned partial results",
+			LogField{Key: "search_text", Value: searchText},
+			LogField{Key: "error", Value: err.Error()})
+	}
+
+	if results == nil {
+		results = []Security{}
+	}
+
+	return results, nil
+}
+
+// =============================================================================
+// Subscriptions
+// =============================================================================
+
+// SubscribeSecurities polls the given symbols every interval and sends a SecurityUpdate
+// on the returned channel whenever a quote changes. The first poll emits every symbol
+// found, with a nil Previous; symbols that are not listed are skipped. Collections older
+// than half the interval are refreshed on each poll, so the cache never hides changes.
+//
+// Polling errors are logged and retried on the next tick. The channel is closed, and the
+// polling goroutine exits, when ctx is cancelled or the client is closed. The channel is
+// unbuffered: a slow consumer delays the next poll rather than piling up updates.
+//
+// Example usage:
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//
+//	updates, err := client.SubscribeSecurities(ctx, []string{"GGAL", "AAPL"}, 5*time.Second)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for update := range updates {
+//		if update.Previous != nil {
+//			fmt.Printf("%s: $%.2f -> $%.2f\n", update.Symbol, update.Previous.Last, update.Security.Last)
+//		}
+//	}
+func (c *client) SubscribeSecurities(ctx context.Context, symbols []string, interval time.Duration) (<-chan SecurityUpdate, error) {
+	if len(symbols) == 0 {
+		return nil, errors.New("no symbols to subscribe to")
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid polling interval: %v", interval)
+	}
+
+	updates := make(chan SecurityUpdate)
+	ctx, stop := c.untilClosed(ctx)
+	go func() {
+		defer stop()
+		c.pollSecurities(ctx, symbols, interval, updates)
+	}()
+	return updates, nil
+}
+
+// pollSecurities runs the polling loop behind SubscribeSecurities
+func (c *client) pollSecurities(ctx context.Context, symbols []string, interval time.Duration, updates chan<- SecurityUpdate) {
+	defer close(updates)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	previous := make(map[string]Security, len(symbols))
+	for {
+		quotes, err := c.GetMultipleSecuritiesMaxAge(ctx, symbols, interval/2)
+		switch {
+		case ctx.Err() != nil, errors.Is(err, ErrClientClosed):
+			return
+		case err != nil:
+			c.logger.Warn("Failed to poll securities", LogField{Key: "error", Value: err.Error()})
+		default:
+			for _, symbol := range symbols {
+				security, found := quotes[symbol]
+				if !found {
+					continue
+				}
+
+				update := SecurityUpdate{Symbol: symbol, Security: *security}
+				if prev, seen := previous[symbol]; seen {
+					if helpers.SecurityApproxEqual(prev, *security, 0) {
+						continue
+					}
+					update.Previous = &prev
+				}
+				previous[symbol] = *security
+
+				select {
+				case updates <- update:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// StreamIndices emits market indices on the returned channel as their values change.
+// The first update sends every index; afterwards only indices whose values differ
+// from the last ones sent are emitted.
+//
+// BYMA's open data API has no push endpoint, so the stream polls the indices on a
+// fixed ClientOptions.IndexStreamInterval, fetching them again when the cached ones
+// are older than half the interval. Each poll is a regular request, answered at once;
+// the cache shared with other calls is only updated with the indices fetched. When a poll fails the stream reconnects with exponential backoff, using
+// the client's retry delays, and resumes the normal interval after the next success.
+// The channel is closed when ctx is cancelled or the client is closed.
+//
+// Example usage:
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//
+//	indices, err := client.StreamIndices(ctx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for index := range indices {
+//		fmt.Printf("%s: %.2f (%.2f%%)\n", index.Symbol, index.Last, index.Change)
+//	}
+func (c *client) StreamIndices(ctx context.Context) (<-chan Index, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	updates := make(chan Index)
+	ctx, stop := c.untilClosed(ctx)
+	go func() {
+		defer stop()
+		c.pollIndices(ctx, updates)
+	}()
+	return updates, nil
+}
+
+// untilClosed derives a context from ctx that is also cancelled when the client starts
+// closing, so the goroutines behind subscriptions and streams stop on Close. The
+// returned function releases it once the goroutine is done.
+func (c *client) untilClosed(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(c.Lifetime(), cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// pollIndices runs the polling loop behind StreamIndices
+func (c *client) pollIndices(ctx context.Context, updates chan<- Index) {
+	defer close(updates)
+
+	previous := make(map[string]Index)
+	failures := 0
+	pollCtx := WithMaxAge(ctx, c.indexStreamInterval/2)
+	for {
+		wait := c.indexStreamInterval
+		indices, err := c.cachedIndices(pollCtx)
+		switch {
+		case ctx.Err() != nil, errors.Is(err, ErrClientClosed):
+			return
+		case err != nil:
+			failures++
+			wait = c.Backoff(failures)
+			c.logger.Warn("Failed to poll indices, reconnecting",
+				LogField{Key: "attempt", Value: failures},
+				LogField{Key: "wait", Value: wait},
+				LogField{Key: "error", Value: err.Error()})
+		default:
+			failures = 0
+			for _, index := range indices {
+				if prev, seen := previous[index.Symbol]; seen && prev == index {
+					continue
+				}
+				previous[index.Symbol] = index
+
+				select {
+				case updates <- index:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// =============================================================================
+// Document downloads
+// =============================================================================
+
+// DownloadAttachment downloads a news attachment (News.Descarga) or statement document
+// (IncomeStatement.BalancesArchivo) using the client's session and headers, and returns
+// its bytes and content type, detected from the content (e.g. "application/pdf").
+//
+// Only document downloads on the client's BYMA host are fetched: any other URL returns
+// an ErrInvalidDocumentURL error without making a request.
+//
+// Example usage:
+//
+//	news, _ := client.GetNews(ctx)
+//	data, contentType, err := client.DownloadAttachment(ctx, news[0].Descarga)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("%d bytes of %s\n", len(data), contentType)
+func (c *client) DownloadAttachment(ctx context.Context, documentURL string) ([]byte, string, error) {
+	data, err := c.FetchDocument(ctx, documentURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download attachment: %w", err)
+	}
+	return data, http.DetectContentType(data), nil
+}
+
+// DownloadIncomeStatement downloads the financial statement document (usually a PDF)
+// that an IncomeStatement's BalancesArchivo points to, using the client's session, and
+// returns its bytes. Like DownloadAttachment, it only fetches document downloads on
+// the client's BYMA host. BYMA's open API exposes the statements only as these
+// documents, not as fundamentals such as earnings or market capitalization.
+//
+// Example usage:
+//
+//	statements, err := client.GetIncomeStatement(ctx, "GGAL")
+//	if err != nil || len(statements) == 0 {
+//		log.Fatal("no statements")
+//	}
+//	pdf, err := client.DownloadIncomeStatement(ctx, statements[0].BalancesArchivo)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	os.WriteFile("ggal.pdf", pdf, 0o644)
+func (c *client) DownloadIncomeStatement(ctx context.Context, documentURL string) ([]byte, error) {
+	document, err := c.FetchDocument(ctx, documentURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download statement: %w", err)
+	}
+	return document, nil
+}
+
+// maxConcurrentDownloads caps the number of tickers processed in parallel by
+// DownloadLatestStatements
+const maxConcurrentDownloads = 4
+
+// DownloadLatestStatements fetches the latest income statement for each ticker and
+// downloads its balance-sheet document into dir, which is created if needed.
+//
+// It returns a map of ticker to the written file path for every ticker that succeeded.
+// Failures do not stop the batch: when any ticker fails, the returned error is a
+// TickerErrors describing each failed ticker, alongside the successful paths.
+// At most four tickers are processed concurrently, and tickers not yet started when
+// ctx is cancelled fail with the context error.
+//
+// Example usage:
+//
+//	paths, err := client.DownloadLatestStatements(ctx, []string{"GGAL", "YPFD"}, "./statements")
+//	var tickerErrs openbymadata.TickerErrors
+//	if errors.As(err, &tickerErrs) {
+//		for ticker, tickerErr := range tickerErrs {
+//			log.Printf("%s: %v", ticker, tickerErr)
+//		}
+//	}
+//	for ticker, path := range paths {
+//		fmt.Printf("%s -> %s\n", ticker, path)
+//	}
+func (c *client) DownloadLatestStatements(ctx context.Context, tickers []string, dir string) (map[string]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		paths   = make(map[string]string)
+		errs    = make(TickerErrors)
+		limiter = make(chan struct{}, maxConcurrentDownloads)
+	)
+
+	for _, ticker := range tickers {
+		wg.Add(1)
+		go func(ticker string) {
+			defer wg.Done()
+
+			var filePath string
+			var err error
+			select {
+			case limiter <- struct{}{}:
+				filePath, err = c.downloadLatestStatement(ctx, ticker, dir)
+				<-limiter
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[ticker] = err
+				return
+			}
+			paths[ticker] = filePath
+		}(ticker)
 	}
+	wg.Wait()
 
-	galpones, err := c.GetGalpones(ctx)
-	if err != nil {
-		return nil, err
+	if len(errs) > 0 {
+		return paths, errs
 	}
-
-	return helpers.GetMultipleSecurities(symbols, bluechips, cedears, galpones), nil
+	return paths, nil
 }
 
-// SearchSecurities searches for securities containing the given text in their symbol
-func (c *client) SearchSecurities(ctx context.Context, searchText string) ([]Security, error) {
-	bluechips, err := c.GetBluechips(ctx)
+// downloadLatestStatement downloads the latest statement document for a single ticker
+func (c *client) downloadLatestStatement(ctx context.Context, ticker, dir string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	statements, err := c.cachedIncomeStatement(ctx, ticker)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	cedears, err := c.GetCedears(ctx)
+	latest, err := helpers.LatestIncomeStatement(statements)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	galpones, err := c.GetGalpones(ctx)
+	document, err := c.DownloadIncomeStatement(ctx, latest.BalancesArchivo)
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+
+	documentURL, err := url.Parse(latest.BalancesArchivo)
+	if err != nil {
+		return "", fmt.Errorf("invalid statement URL: %w", err)
+	}
+
+	filename := filepath.Base(ticker) + "_" + path.Base(documentURL.Path)
+	filePath := filepath.Join(dir, filename)
+	if err := os.WriteFile(filePath, document, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write statement: %w", err)
 	}
 
-	return helpers.SearchSecurities(searchText, bluechips, cedears, galpones), nil
+	return filePath, nil
 }
 
 // =============================================================================
@@ -839,14 +2202,23 @@ func (c *client) SearchSecurities(ctx context.Context, searchText string) ([]Sec
 //	cacheInfo = client.GetCacheInfo()
 //	fmt.Printf("Cache performance: %+v\n", cacheInfo)
 func (c *client) GetCacheInfo() map[string]interface{} {
-	if c.cache != nil {
-		return c.cache.GetInfo()
+	if c.cache == nil {
+		return make(map[string]interface{})
+	}
+	info := c.cache.GetInfo()
+	c.settlementMu.Lock()
+	defer c.settlementMu.Unlock()
+	for settlement, store := range c.settlementCaches {
+		for category, entry := range store.GetInfo() {
+			info[category+":"+string(settlement)] = entry
+		}
 	}
-	return make(map[string]interface{})
+	return info
 }
 
 // ClearCache clears all cached data, forcing fresh API calls for subsequent requests.
-// This is useful when you need absolutely fresh data or for testing purposes.
+// This is useful for testing purposes; to get fresh data for a single call without
+// discarding every other category, use WithFreshData instead.
 //
 // Example usage:
 //
@@ -874,10 +2246,9 @@ func (c *client) GetCacheInfo() map[string]interface{} {
 //
 // Use cases:
 //
-//	// 1. Real-time trading applications
+//	// 1. Real-time trading applications: only this call skips the cache
 //	if needRealTimeData {
-//		client.ClearCache()
-//		positions, _ := client.GetMultipleSecurities(ctx, portfolio)
+//		positions, _ := client.GetMultipleSecurities(openbymadata.WithFreshData(ctx), portfolio)
 //	}
 //
 //	// 2. Testing with fresh data
@@ -892,9 +2263,253 @@ func (c *client) GetCacheInfo() map[string]interface{} {
 //		freshData, _ := client.GetSecurity(ctx, "AAPL")
 //	}
 func (c *client) ClearCache() {
+	for _, store := range c.caches() {
+		store.Clear()
+	}
+}
+
+// Flush writes the cached data of every settlement to CacheDir and returns the errors
+// of the writes that failed. Cached data is already written when it is updated, but
+// those writes are best-effort; Close and CloseWithTimeout call Flush, and you can
+// call it at any time, e.g. periodically, to make sure the next client started with the
+// same CacheDir finds it. Each file is replaced atomically, so
+// a write that fails or is interrupted leaves the previous file intact. Without
+// caching or a CacheDir it does nothing.
+//
+// Example usage:
+//
+//	defer func() {
+//		if err := client.Flush(); err != nil {
+//			log.Printf("cache not saved: %v", err)
+//		}
+//	}()
+func (c *client) Flush() error {
+	var errs []error
+	for _, store := range c.caches() {
+		errs = append(errs, store.Flush())
+	}
+	return errors.Join(errs...)
+}
+
+// CacheStats returns cache hit and miss counts since the client was created or the
+// last ResetStats, in total and per category (keyed by the Cache* constants). Every
+// cached lookup counts, including the ones made internally by lookups such as
+// GetSecurity. Without caching, all counts are zero.
+//
+// Example usage:
+//
+//	stats := client.CacheStats()
+//	fmt.Printf("Cache hit rate: %.1f%% (%d hits, %d misses)\n",
+//		stats.HitRate()*100, stats.Hits, stats.Misses)
+//	for category, s := range stats.Categories {
+//		fmt.Printf("  %-18s %d/%d\n", category, s.Hits, s.Hits+s.Misses)
+//	}
+func (c *client) CacheStats() CacheStats {
+	stats := CacheStats{Categories: make(map[string]CacheCategoryStats)}
+	for _, store := range c.caches() {
+		storeStats := store.Stats()
+		stats.Hits += storeStats.Hits
+		stats.Misses += storeStats.Misses
+		for category, s := range storeStats.Categories {
+			total := stats.Categories[category]
+			total.Hits += s.Hits
+			total.Misses += s.Misses
+			stats.Categories[category] = total
+		}
+	}
+	return stats
+}
+
+// RefreshDictionary fetches BYMA's translation dictionary again and installs it, so
+// index and search descriptions use the current translations without creating a new
+// client. The dictionary is otherwise loaded with the session and, with caching, kept
+// for DefaultDictionaryTTL (adjustable with CacheTTLOverrides[CacheDictionary]) and
+// persisted to CacheDir; when it expires or failed to load, it is fetched again before
+// the indices are. Once a dictionary is installed the cached indices, translated with
+// the previous one, are expired in every settlement cache so the next read fetches
+// them again. On failure the current dictionary and indices are kept and the error
+// returned.
+//
+// Example usage:
+//
+//	if err := client.RefreshDictionary(ctx); err != nil {
+//		log.Printf("keeping the current translations: %v", err)
+//	}
+func (c *client) RefreshDictionary(ctx context.Context) error {
+	_, err, _ := c.inflight.Do(CacheDictionary, func() (interface{}, error) {
+		dictionary, err := c.Client.FetchDictionary(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.Client.SetDictionary(dictionary)
+		if c.cache != nil {
+			c.cache.SetDictionary(dictionary)
+		}
+		for _, store := range c.caches() {
+			store.ExpireIndices()
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// maintainDictionary refreshes the translation dictionary when the cached one expired
+// or, without caching, when none was loaded. Failures are logged and leave the current
+// dictionary in place. With SkipSessionInit the dictionary is only loaded on request,
+// by RefreshDictionary.
+func (c *client) maintainDictionary(ctx context.Context) {
+	if c.skipSessionInit {
+		return
+	}
 	if c.cache != nil {
-		c.cache.Clear()
+		if _, state := c.cache.GetDictionary(); state == cache.Fresh {
+			return
+		}
+	} else if c.Client.Dictionary() != nil {
+		return
+	}
+	if err := c.RefreshDictionary(ctx); err != nil {
+		c.logger.Warn("Failed to refresh the translation dictionary",
+			LogField{Key: "error", Value: err.Error()})
+	}
+}
+
+// LastRawResponse returns the body of the last response received from a BYMA endpoint,
+// whether or not debug mode is enabled, so a zero or missing field can be checked
+// against exactly what BYMA returned. Endpoints are named as in the debug logs, e.g.
+// "leading-equity", "cedears", "public-bonds", "options", "index-future" or
+// "index-price". It returns nil when nothing was received from the endpoint yet;
+// responses served from the cache don't replace the recorded body.
+//
+// Example usage:
+//
+//	aapl, _ := client.GetCedear(ctx, "AAPL")
+//	if aapl.Last == 0 {
+//		fmt.Printf("raw cedears response: %s\n", client.LastRawResponse("cedears"))
+//	}
+func (c *client) LastRawResponse(endpoint string) []byte {
+	return c.Client.LastRawResponse(endpoint)
+}
+
+// RetryStats returns how many requests were made, retried and failed since the client
+// was created or the last ResetStats, counting the requests made during session setup.
+// Use it with the OnRetry option to alert on retry spikes or tune RetryAttempts.
+//
+// Example usage:
+//
+//	stats := client.RetryStats()
+//	fmt.Printf("%d requests, %d retries (%.2f per request), %d failures\n",
+//		stats.Requests, stats.Retries, stats.RetryRate(), stats.Failures)
+func (c *client) RetryStats() RetryStats {
+	return c.Client.RetryStats()
+}
+
+// ResetStats sets the cache hit and miss counts and the retry counts back to zero,
+// e.g. to measure the hit or retry rate over a time window
+func (c *client) ResetStats() {
+	for _, store := range c.caches() {
+		store.ResetStats()
+	}
+	c.ResetRetryStats()
+}
+
+// Snapshot stores the current state of every quote collection (equities, CEDEARs,
+// ETFs, bonds, options, futures, indices and the market summary) under name, for
+// GetSnapshot to return later. Snapshots are kept apart from the cache: they never
+// expire, ClearCache leaves them alone and taking one again under the same name
+// replaces it. With CacheDir set they are also saved to its "snapshots"
+// subdirectory, so they outlive the process.
+//
+// Collections are read like the collection getters, so they may come from the cache;
+// pass a context from WithFreshData to capture the market rather than the cache. The
+// snapshot is only stored when every collection is read; otherwise the first error is
+// returned. Names may contain letters, digits, '-', '_' and '.', and must not start
+// with '.'; other names return an INVALID_SNAPSHOT_NAME error.
+//
+// Example usage:
+//
+//	// Capture the close, then compare it with the next day's open
+//	if err := client.Snapshot(openbymadata.WithFreshData(ctx), "close-2024-03-15"); err != nil {
+//		log.Fatal(err)
+//	}
+//	snapshot, err := client.GetSnapshot("close-2024-03-15")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("%d blue chips as of %s\n", len(snapshot.Bluechips), snapshot.Timestamp.Format(time.RFC3339))
+func (c *client) Snapshot(ctx context.Context, name string) error {
+	if !cache.ValidSnapshotName(name) {
+		return invalidSnapshotName(name)
+	}
+
+	snapshot := &MarketSnapshot{Name: name}
+	g, gctx := errgroup.WithContext(ctx)
+	snapshotInto(g, gctx, &snapshot.Bluechips, c.cachedBluechips)
+	snapshotInto(g, gctx, &snapshot.Galpones, c.cachedGalpones)
+	snapshotInto(g, gctx, &snapshot.Cedears, c.cachedCedears)
+	snapshotInto(g, gctx, &snapshot.Etfs, c.cachedEtfs)
+	snapshotInto(g, gctx, &snapshot.Bonds, c.cachedBonds)
+	snapshotInto(g, gctx, &snapshot.ShortTermBonds, c.cachedShortTermBonds)
+	snapshotInto(g, gctx, &snapshot.CorporateBonds, c.cachedCorporateBonds)
+	snapshotInto(g, gctx, &snapshot.Options, c.cachedOptions)
+	snapshotInto(g, gctx, &snapshot.Futures, c.cachedFutures)
+	snapshotInto(g, gctx, &snapshot.Indices, c.cachedIndices)
+	snapshotInto(g, gctx, &snapshot.MarketSummary, c.cachedMarketResume)
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	snapshot.Timestamp = time.Now()
+
+	if err := c.snapshots.Save(snapshot); err != nil {
+		return fmt.Errorf("failed to save snapshot %s: %w", name, err)
+	}
+	return nil
+}
+
+// snapshotInto reads a collection into dst as part of g, copying it so the snapshot
+// doesn't share the cached data
+func snapshotInto[T any](g *errgroup.Group, ctx context.Context, dst *[]T, load func(context.Context) ([]T, error)) {
+	g.Go(func() error {
+		data, err := load(ctx)
+		*dst = slices.Clone(data)
+		return err
+	})
+}
+
+// GetSnapshot returns the snapshot taken by Snapshot under name, including one saved
+// to CacheDir by a previous process. An unknown name returns a SNAPSHOT_NOT_FOUND
+// error. The snapshot is a copy, free to modify.
+//
+// Example usage:
+//
+//	before, _ := client.GetSnapshot("close-2024-03-15")
+//	after, _ := client.GetSnapshot("open-2024-03-18")
+//	for _, quote := range after.Bluechips {
+//		if i := slices.IndexFunc(before.Bluechips, func(s openbymadata.Security) bool {
+//			return s.Symbol == quote.Symbol
+//		}); i >= 0 {
+//			fmt.Printf("%s: $%.2f -> $%.2f\n", quote.Symbol, before.Bluechips[i].Last, quote.Last)
+//		}
+//	}
+func (c *client) GetSnapshot(name string) (*MarketSnapshot, error) {
+	if !cache.ValidSnapshotName(name) {
+		return nil, invalidSnapshotName(name)
+	}
+
+	snapshot, found, err := c.snapshots.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", name, err)
 	}
+	if !found {
+		return nil, NewBYMAError(ErrSnapshotNotFound.Code, fmt.Sprintf("snapshot %s not found", name))
+	}
+	return snapshot.Clone(), nil
+}
+
+// invalidSnapshotName returns the error for a name Snapshot and GetSnapshot refuse
+func invalidSnapshotName(name string) error {
+	return NewBYMAError(ErrInvalidSnapshotName.Code, fmt.Sprintf(
+		"invalid snapshot name %q: use letters, digits, '-', '_' and '.', not starting with '.'", name))
 }
 
 // =============================================================================
@@ -949,17 +2564,182 @@ func (c *client) IsWorkingDay(ctx context.Context) (bool, error) {
 	return c.Client.IsWorkingDay(ctx)
 }
 
+// GetMarketTime returns BYMA's market time: whether today is a working day and, on
+// working days, the regular 11:00 to 17:00 session hours and whether the session is
+// open. BYMA's endpoint reports neither its clock nor the session hours, so ServerTime
+// is the host's clock and IsOpen compares it with the regular session; an early close
+// or a wrong host clock is not detected. Times are in the Buenos Aires time zone of
+// the session. It is never cached.
+//
+// Example usage:
+//
+//	marketTime, err := client.GetMarketTime(ctx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if !marketTime.IsOpen {
+//		fmt.Printf("Market closed at %s\n", marketTime.ServerTime.Format("15:04"))
+//	}
+func (c *client) GetMarketTime(ctx context.Context) (*MarketTime, error) {
+	return c.Client.GetMarketTime(ctx)
+}
+
+// GetMarketSummaryFor returns the market summary entry of an asset type, as reported
+// by MarketResume (e.g. "ACCIONES" or "BONOS"), matched case-insensitively against the
+// entry's asset type or symbol. It reads the cached summary and returns an
+// ErrInvalidTicker error when no entry matches.
+//
+// Example usage:
+//
+//	equities, err := client.GetMarketSummaryFor(ctx, "ACCIONES")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Equities: $%.2f in %d operations\n", equities.TotalNegotiated, equities.Operations)
+func (c *client) GetMarketSummaryFor(ctx context.Context, assetType string) (*MarketSummary, error) {
+	summaries, err := c.cachedMarketResume(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return helpers.FindMarketSummary(assetType, summaries)
+}
+
+// TotalMarketVolume returns the turnover (the sum of TotalNegotiated) and the volume
+// traded across every entry of the cached market summary
+//
+// Example usage:
+//
+//	turnover, volume, err := client.TotalMarketVolume(ctx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Market: $%.2f, %d units\n", turnover, volume)
+func (c *client) TotalMarketVolume(ctx context.Context) (float64, int64, error) {
+	summaries, err := c.cachedMarketResume(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	turnover, volume := helpers.MarketTotals(summaries)
+	return turnover, volume, nil
+}
+
+// marketResumeTolerance is the maximum relative difference accepted by
+// ValidateMarketResume between summary and collection totals
+const marketResumeTolerance = 0.10
+
+// ValidateMarketResume cross-checks the aggregate turnover and volume reported by
+// MarketResume against the totals summed over every instrument collection (equities,
+// CEDEARs, ETFs, bonds, options and futures). Differences above 10% are reported as issues.
+//
+// This is a data-quality signal rather than an error condition: a non-nil report is
+// returned whenever the data could be fetched, and its Consistent field tells whether
+// the totals agree. All inputs come from the cached collections when available.
+//
+// Example usage:
+//
+//	report, err := client.ValidateMarketResume(ctx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if !report.Consistent {
+//		for _, issue := range report.Issues {
+//			log.Printf("⚠️  %s", issue)
+//		}
+//	}
+func (c *client) ValidateMarketResume(ctx context.Context) (*ValidationReport, error) {
+	summaries, err := c.cachedMarketResume(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var securities []Security
+	for _, fetch := range []func(context.Context) ([]Security, error){c.cachedBluechips, c.cachedGalpones, c.cachedCedears, c.cachedEtfs} {
+		data, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		securities = append(securities, data...)
+	}
+
+	var bonds []Bond
+	for _, fetch := range []func(context.Context) ([]Bond, error){c.cachedBonds, c.cachedShortTermBonds, c.cachedCorporateBonds} {
+		data, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		bonds = append(bonds, data...)
+	}
+
+	options, err := c.cachedOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	futures, err := c.cachedFutures(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return helpers.ValidateMarketResume(summaries, securities, bonds, options, futures, marketResumeTolerance), nil
+}
+
+// GetDollarRates returns the MEP and CCL dollar rates implied by the peso and dollar
+// lines of AL30 and GD30: for example MEP = AL30 / AL30D and CCL = AL30 / AL30C. Each
+// rate names the bond lines and prices used; pairs where a line is missing or has no
+// price (e.g. before the first trade of the day, with no close either) are left out,
+// so the result may be empty. The rates are computed from the cached government bond
+// collection, so they refresh together with GetBonds.
+//
+// Example usage:
+//
+//	rates, err := client.GetDollarRates(ctx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, rate := range rates {
+//		fmt.Printf("%s (%s/%s): $%.2f\n", rate.Name, rate.PesoSymbol, rate.DollarSymbol, rate.Rate)
+//	}
+func (c *client) GetDollarRates(ctx context.Context) ([]DollarRate, error) {
+	bonds, err := c.cachedBonds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return helpers.ImpliedDollarRates(bonds), nil
+}
+
 // =============================================================================
-// Historical Data & Charting (delegated methods with examples)
+// Historical Data & Charting
 // =============================================================================
 
 // GetHistory retrieves historical OHLCV data for a symbol within a date range.
-// This is essential for charting and technical analysis.
+// This is essential for charting and technical analysis. Results are cached per
+// symbol, resolution and range, so repeated requests for the same chart are served
+// from memory.
 //
 // Parameters:
-//   - symbol: Security symbol (automatically normalized with "24HS" suffix)
-//   - resolution: "D" (daily), "W" (weekly), "M" (monthly)
-//   - from, to: Date range as time.Time
+//   - symbol: Security symbol. Bare symbols query the 24HS settlement series; to chart
+//     another segment, include its suffix, e.g. "AL30 CI" or "GGAL 48HS".
+//   - resolution: "D" (daily), "W" (weekly), "M" (monthly), or minutes per bar for
+//     intraday candles: "1", "5", "15", "30", "60" (see GetHistoryIntraday). Matching is
+//     case-insensitive and aliases such as "1D", "week" or "monthly" are accepted; any
+//     other value returns an INVALID_RESOLUTION error without contacting the API.
+//   - from, to: Date range as time.Time. Both must be set, from must not be after to,
+//     and the range must not lie entirely in the future, otherwise an
+//     INVALID_DATE_RANGE error is returned; an end in the future is clamped to now.
+//
+// When BYMA has no data for the symbol in the range, a NO_DATA error matching
+// ErrNoHistory is returned, so it can be told apart from a failed request:
+//
+//	data, err := client.GetHistory(ctx, symbol, "D", from, to)
+//	if errors.Is(err, openbymadata.ErrNoHistory) {
+//		// Nothing traded in the range: show an empty chart
+//	}
+//
+// Failed requests return a BYMAError as well: the HTTP status error (e.g.
+// RATE_LIMITED) or TIMEOUT when there is one, API_UNAVAILABLE for other network
+// failures and INVALID_RESPONSE for bodies that can't be parsed, so IsRetryable and
+// errors.Is work as with the collection methods. A cancelled context is returned as
+// is, matching context.Canceled.
 //
 // Example usage:
 //
@@ -985,14 +2765,11 @@ func (c *client) IsWorkingDay(ctx context.Context) (bool, error) {
 //
 // Technical analysis example:
 //
-//	// Calculate simple moving average
-//	if len(weeklyData.Close) >= 10 {
-//		sum := 0.0
-//		for i := len(weeklyData.Close) - 10; i < len(weeklyData.Close); i++ {
-//			sum += weeklyData.Close[i]
-//		}
-//		sma10 := sum / 10
-//		currentPrice := weeklyData.Close[len(weeklyData.Close)-1]
+//	// 10-week simple moving average (see also EMA and RSI)
+//	if sma, err := weeklyData.SMA(10); err == nil {
+//		last := len(sma) - 1
+//		sma10 := sma[last]
+//		currentPrice := weeklyData.Close[last]
 //
 //		fmt.Printf("Current Price: $%.2f\n", currentPrice)
 //		fmt.Printf("10-Week SMA: $%.2f\n", sma10)
@@ -1003,11 +2780,90 @@ func (c *client) IsWorkingDay(ctx context.Context) (bool, error) {
 //		}
 //	}
 func (c *client) GetHistory(ctx context.Context, symbol, resolution string, from, to time.Time) (*OHLCV, error) {
-	return c.Client.GetHistory(ctx, symbol, resolution, from, to)
+	data, err := c.cachedHistory(ctx, symbol, resolution, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return data.Clone(), nil
+}
+
+// marketClock is the clock history caching checks the trading session against
+var marketClock = time.Now
+
+// closedSessionDuration returns how long history fetched now stays unchanged: outside
+// the regular session no bar, including today's, changes before the next session
+// opens. During the session it returns zero, so today's bar expires with the cache TTL.
+func closedSessionDuration() time.Duration {
+	now := marketClock()
+	if api.InRegularSession(now) {
+		return 0
+	}
+	return api.NextSessionOpen(now).Sub(now)
+}
+
+// cachedHistory returns the history of GetHistory shared with the cache, which
+// GetHistory hands out as a copy. History fetched while the session is open follows
+// the cache TTL, as the bar of the day keeps changing; once the session has closed it
+// is final and kept until the next session opens, when that is longer.
+func (c *client) cachedHistory(ctx context.Context, symbol, resolution string, from, to time.Time) (*OHLCV, error) {
+	resolution, err := api.NormalizeResolution(resolution)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.ValidateDateRange(from, to); err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s|%s|%d|%d", api.HistorySymbol(symbol), resolution, from.Unix(), to.Unix())
+	return cachedFetch(ctx, c, CacheHistory+":"+key,
+		func(maxAge time.Duration) (*OHLCV, cache.State) {
+			return c.cache.GetHistory(key, maxAge)
+		},
+		func(ctx context.Context) (*OHLCV, error) {
+			return c.Client.GetHistory(ctx, symbol, resolution, from, to)
+		},
+		func(data *OHLCV) {
+			c.cache.SetHistory(key, data, closedSessionDuration())
+		})
+}
+
+// TotalReturnHistory returns the total-return series of a symbol between from and to:
+// its daily history adjusted for the splits among actions and with every dividend
+// among them reinvested. BYMA's open data API doesn't publish corporate actions, so the
+// caller supplies the dividends and splits of the symbol, as for AdjustForSplits.
+//
+// Dividends are assumed reinvested in the security, free of taxes and fees, at the
+// close of the first candle on or after their ex-date; from that candle on, prices are
+// scaled by 1 + amount / close, compounding across dividends. The first candle keeps
+// its price, so the series shows the growth of one share held over the range with its
+// dividends reinvested. Volumes are only adjusted for splits. Dividends going ex on or
+// before from are not part of the range and are ignored.
+//
+// Example usage:
+//
+//	actions := []openbymadata.CorporateAction{{
+//		Symbol: "GGAL",
+//		Type:   openbymadata.CorporateActionDividend,
+//		ExDate: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+//		Amount: 120.5,
+//	}}
+//	total, err := client.TotalReturnHistory(ctx, "GGAL", from, to, actions)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	last := len(total.Close) - 1
+//	fmt.Printf("Total return: %.2f%%\n", (total.Close[last]/total.Close[0]-1)*100)
+func (c *client) TotalReturnHistory(ctx context.Context, symbol string, from, to time.Time, actions []CorporateAction) (*OHLCV, error) {
+	data, err := c.cachedHistory(ctx, symbol, "D", from, to)
+	if err != nil {
+		return nil, err
+	}
+	return api.TotalReturn(data, actions), nil
 }
 
 // GetHistoryLastDays retrieves historical OHLCV data for the last N days.
-// This is a convenient method for recent historical data.
+// This is a convenient method for recent historical data. The range ends at the close
+// of the current day, so repeated calls during the day share the same cached result.
 //
 // Example usage:
 //
@@ -1059,7 +2915,124 @@ func (c *client) GetHistory(ctx context.Context, symbol, resolution string, from
 //		fmt.Printf("📊 30-Day Volatility: %.2f%% daily\n", volatility*100)
 //	}
 func (c *client) GetHistoryLastDays(ctx context.Context, symbol string, days int) (*OHLCV, error) {
-	return c.Client.GetHistoryLastDays(ctx, symbol, days)
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
+	from := to.AddDate(0, 0, -days)
+
+	return c.GetHistory(ctx, symbol, "D", from, to)
+}
+
+// maxTradingDaysLookback is the widest range, in calendar days, requested by
+// GetHistoryLastTradingDays
+const maxTradingDaysLookback = 20 * 365
+
+// GetHistoryLastTradingDays retrieves the last bars daily candles of a symbol, counting
+// trading sessions rather than calendar days, so weekends and holidays don't shorten
+// the series. It requests a calendar range with room for non-trading days and widens
+// it until enough bars are returned; each request is cached like GetHistory.
+//
+// When BYMA can't supply bars candles (the symbol is recent, or a wider range adds no
+// older data) an ErrNoHistory error is returned. A bars value below 1 returns an
+// ErrInvalidDateRange error.
+//
+// Example usage:
+//
+//	// Exactly 200 sessions to warm up a 200-day moving average
+//	history, err := client.GetHistoryLastTradingDays(ctx, "GGAL", 200)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	sma, _ := history.SMA(200)
+//	fmt.Printf("SMA200: $%.2f\n", sma[len(sma)-1])
+func (c *client) GetHistoryLastTradingDays(ctx context.Context, symbol string, bars int) (*OHLCV, error) {
+	if bars < 1 {
+		return nil, NewBYMAError(ErrInvalidDateRange.Code, fmt.Sprintf("bars must be at least 1, got %d", bars))
+	}
+
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
+	// About 250 sessions a year: 1.5 calendar days per bar, plus room for holidays
+	days := min(bars*3/2+10, maxTradingDaysLookback)
+	available := -1
+	for {
+		data, err := c.cachedHistory(ctx, symbol, "D", to.AddDate(0, 0, -days), to)
+		if err != nil && !errors.Is(err, ErrNoHistory) {
+			return nil, err
+		}
+		length, err := data.Len()
+		if err != nil {
+			return nil, err
+		}
+		if length >= bars {
+			return data.Tail(bars), nil
+		}
+		// A wider range that adds no bars means there is no older history
+		if length == available || days == maxTradingDaysLookback {
+			return nil, NewBYMAError(ErrNoHistory.Code, fmt.Sprintf(
+				"only %d of %d daily bars available for %s", length, bars, symbol))
+		}
+		available = length
+		days = min(days*2, maxTradingDaysLookback)
+	}
+}
+
+// GetFiftyTwoWeekRange returns the lowest low and highest high of a symbol's daily
+// candles over the past year (52 weeks), read through the history cache like
+// GetHistory. Candles without a low or high (zero or below) are ignored; when no
+// candle has one, an ErrNoHistory error is returned.
+//
+// Example usage:
+//
+//	low, high, err := client.GetFiftyTwoWeekRange(ctx, "GGAL")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	ggal, _ := client.GetSecurity(ctx, "GGAL")
+//	fmt.Printf("GGAL $%.2f, %.1f%% below its 52-week high of $%.2f (low $%.2f)\n",
+//		ggal.Last, (1-ggal.Last/high)*100, high, low)
+func (c *client) GetFiftyTwoWeekRange(ctx context.Context, symbol string) (low, high float64, err error) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
+	data, err := c.cachedHistory(ctx, symbol, "D", to.AddDate(0, 0, -7*52), to)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, value := range data.Low {
+		if value > 0 && (low == 0 || value < low) {
+			low = value
+		}
+	}
+	for _, value := range data.High {
+		high = max(high, value)
+	}
+	if low == 0 || high <= 0 {
+		return 0, 0, NewBYMAError(ErrNoHistory.Code, fmt.Sprintf("no 52-week range available for %s", symbol))
+	}
+	return low, high, nil
+}
+
+// GetHistoryIntraday retrieves intraday OHLCV candles of the given number of minutes
+// (1, 5, 15, 30 or 60) within a date range. Other bar sizes return an
+// INVALID_RESOLUTION error.
+//
+// BYMA does not publish how far back intraday data goes and only serves recent
+// sessions at minute granularity, so ranges longer than MaxIntradayRange (30 days)
+// are shortened to the most recent 30 days before the range end.
+//
+// Example usage:
+//
+//	// 5-minute candles for today's session
+//	now := time.Now()
+//	open := time.Date(now.Year(), now.Month(), now.Day(), 11, 0, 0, 0, now.Location())
+//
+//	candles, err := client.GetHistoryIntraday(ctx, "GGAL", 5, open, now)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("%d candles, last close $%.2f\n", len(candles.Close), candles.Close[len(candles.Close)-1])
+func (c *client) GetHistoryIntraday(ctx context.Context, symbol string, minutes int, from, to time.Time) (*OHLCV, error) {
+	return c.GetHistory(ctx, symbol, strconv.Itoa(minutes), from, to)
 }
 
 // ConvertToHistoricalData converts OHLCV slices to structured HistoricalData format.
@@ -1107,3 +3080,41 @@ func (c *client) GetHistoryLastDays(ctx context.Context, symbol string, days int
 func (c *client) ConvertToHistoricalData(slices *OHLCV) ([]HistoricalData, error) {
 	return c.Client.ConvertToHistoricalData(slices)
 }
+
+// =============================================================================
+// Lifecycle
+// =============================================================================
+
+// Close shuts the client down immediately and releases its resources: in-flight
+// requests and background cache refreshes are cancelled, subscription and stream
+// channels are closed, idle HTTP connections are closed and any further request fails
+// with ErrClientClosed. Use CloseWithTimeout to let in-flight requests finish first.
+//
+// With a CacheDir, Close then writes the cache of every settlement to disk like Flush,
+// so writes that failed while the client ran aren't lost; their errors are returned.
+func (c *client) Close() error {
+	return c.shutdown(0)
+}
+
+// CloseWithTimeout stops accepting new requests and waits up to drainTimeout for
+// in-flight requests to complete before cancelling the remaining ones. Subscriptions
+// and streams stop right away, and the cache is written to disk, as with Close. It
+// returns an error if the timeout expired and requests had to be cancelled, or if
+// writing the cache failed.
+//
+// Example usage:
+//
+//	// On server shutdown, give pending BYMA calls up to 5 seconds to finish
+//	if err := client.CloseWithTimeout(5 * time.Second); err != nil {
+//		log.Printf("BYMA client shutdown: %v", err)
+//	}
+func (c *client) CloseWithTimeout(drainTimeout time.Duration) error {
+	return c.shutdown(drainTimeout)
+}
+
+// shutdown stops the client and flushes its caches. The flush runs after the drain,
+// so it also writes the data of the requests that completed during it.
+func (c *client) shutdown(drainTimeout time.Duration) error {
+	err := c.Client.Shutdown(drainTimeout)
+	return errors.Join(err, c.Flush())
+}
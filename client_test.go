@@ -2,12 +2,29 @@ package openbymadata
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/carvalab/openbymadata/internal/helpers"
+	"github.com/carvalab/openbymadata/internal/utils"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -47,6 +64,127 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClientStrict(t *testing.T) {
+	var dictionary atomic.Value
+	dictionary.Store(`{"MERVAL":"S&P Merval"}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) == "es.json" {
+			w.Write([]byte(dictionary.Load().(string)))
+		}
+	}))
+	defer server.Close()
+
+	opts := &ClientOptions{BaseURL: server.URL, RetryAttempts: 1, RetryBaseDelay: time.Millisecond, Logger: &NoOpLogger{}}
+
+	client, err := NewClientStrict(opts)
+	require.NoError(t, err)
+	require.NotNil(t, client)
+	client.Close()
+
+	// An unusable dictionary fails the strict constructor but not NewClient
+	dictionary.Store(`not json`)
+	client, err = NewClientStrict(opts)
+	assert.ErrorContains(t, err, "dictionary")
+	assert.Nil(t, client)
+	assert.NotNil(t, NewClient(opts))
+
+	// So does an unreachable server
+	server.Close()
+	_, err = NewClientStrict(opts)
+	assert.ErrorContains(t, err, "failed to establish session")
+}
+func TestClient_SkipSessionInit(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch path.Base(r.URL.Path) {
+		case "es.json":
+			w.Write([]byte(`{"MERVAL":"S&P Merval"}`))
+		case "index-price":
+			w.Write([]byte(`{"data":[{"symbol":"MERVAL","description":"MERVAL"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClientStrict(&ClientOptions{
+		BaseURL:         server.URL,
+		RetryAttempts:   1,
+		Logger:          &NoOpLogger{},
+		SkipSessionInit: true,
+	})
+	require.NoError(t, err)
+	defer client.Close()
+	assert.Equal(t, int32(0), requests.Load(), "no bootstrap requests")
+
+	// Descriptions pass through untranslated
+	indices, err := client.GetIndices(context.Background())
+	require.NoError(t, err)
+	require.Len(t, indices, 1)
+	assert.Equal(t, "MERVAL", indices[0].Description)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// An unreachable server isn't noticed until the first request
+	server.Close()
+	_, err = NewClientStrict(&ClientOptions{BaseURL: server.URL, SkipSessionInit: true})
+	assert.NoError(t, err)
+}
+
+func TestClient_RefreshDictionary(t *testing.T) {
+	var dictionary atomic.Value
+	var dictionaryRequests atomic.Int32
+	dictionary.Store(`not json`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch path.Base(r.URL.Path) {
+		case "es.json":
+			dictionaryRequests.Add(1)
+			w.Write([]byte(dictionary.Load().(string)))
+		case "index-price":
+			w.Write([]byte(`{"data":[{"symbol":"MERVAL","description":"MERVAL"}]}`))
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	description := func(client Client) string {
+		indices, err := client.GetIndices(WithFreshData(ctx))
+		require.NoError(t, err)
+		require.Len(t, indices, 1)
+		return indices[0].Description
+	}
+
+	// A dictionary that failed to load is fetched again before the indices
+	cacheDir := t.TempDir()
+	client := NewClient(&ClientOptions{BaseURL: server.URL, RetryAttempts: 1, Logger: &NoOpLogger{}, CacheDir: cacheDir})
+	defer client.Close()
+	assert.Equal(t, "MERVAL", description(client))
+	dictionary.Store(`{"MERVAL":"S&P Merval"}`)
+	assert.Equal(t, "S&P Merval", description(client))
+	requests := dictionaryRequests.Load()
+	assert.Equal(t, "S&P Merval", description(client))
+	assert.Equal(t, requests, dictionaryRequests.Load(), "the cached dictionary is reused")
+	assert.Contains(t, client.GetCacheInfo(), CacheDictionary)
+
+	// RefreshDictionary installs the current translations, expiring the cached indices
+	// translated with the previous ones, and keeps them on failure
+	_, err := client.GetIndices(ctx)
+	require.NoError(t, err)
+	dictionary.Store(`{"MERVAL":"S&P Merval Index"}`)
+	require.NoError(t, client.RefreshDictionary(ctx))
+	cached, err := client.GetIndices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "S&P Merval Index", cached[0].Description)
+	assert.Equal(t, "S&P Merval Index", description(client))
+	dictionary.Store(`not json`)
+	assert.Error(t, client.RefreshDictionary(ctx))
+	assert.Equal(t, "S&P Merval Index", description(client))
+
+	// The dictionary persisted to CacheDir is used without fetching it
+	requests = dictionaryRequests.Load()
+	restored := NewClient(&ClientOptions{BaseURL: server.URL, RetryAttempts: 1, Logger: &NoOpLogger{}, CacheDir: cacheDir, SkipSessionInit: true})
+	defer restored.Close()
+	assert.Equal(t, "S&P Merval Index", description(restored))
+	assert.Equal(t, requests, dictionaryRequests.Load())
+}
+
 func TestClient_IsWorkingDay(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -108,6 +246,162 @@ func TestClient_IsWorkingDay(t *testing.T) {
 	}
 }
 
+func TestClient_GetMarketTime(t *testing.T) {
+	var body atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body.Load().(string)))
+	}))
+	defer server.Close()
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	// The regular session hours apply on working days, with the local clock
+	body.Store(`{"isWorkingDay":true}`)
+	marketTime, err := client.GetMarketTime(ctx)
+	require.NoError(t, err)
+	assert.True(t, marketTime.IsWorkingDay)
+	assert.WithinDuration(t, time.Now(), marketTime.ServerTime, time.Minute)
+	_, offset := marketTime.ServerTime.Zone()
+	assert.Equal(t, -3*60*60, offset, "times are in Buenos Aires")
+	year, month, day := marketTime.ServerTime.Date()
+	assert.Equal(t, time.Date(year, month, day, 11, 0, 0, 0, marketTime.ServerTime.Location()), marketTime.SessionOpen)
+	assert.Equal(t, time.Date(year, month, day, 17, 0, 0, 0, marketTime.ServerTime.Location()), marketTime.SessionClose)
+	hour := marketTime.ServerTime.Hour()
+	assert.Equal(t, hour >= 11 && hour < 17, marketTime.IsOpen)
+
+	// Never open on a holiday, whatever the payload carries besides isWorkingDay
+	body.Store(`{"isWorkingDay":false,"serverTime":"2024-03-15T12:00:00"}`)
+	marketTime, err = client.GetMarketTime(ctx)
+	require.NoError(t, err)
+	assert.False(t, marketTime.IsOpen)
+	assert.True(t, marketTime.SessionOpen.IsZero())
+	assert.WithinDuration(t, time.Now(), marketTime.ServerTime, time.Minute)
+
+	body.Store(`not json`)
+	_, err = client.GetMarketTime(ctx)
+	assert.ErrorIs(t, err, ErrInvalidResponse)
+}
+
+func TestClient_TLSVerification(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"isWorkingDay": true}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	t.Run("verifies certificates by default", func(t *testing.T) {
+		client := createTestClient(server.URL)
+		_, err := client.IsWorkingDay(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("custom root CAs", func(t *testing.T) {
+		pool := x509.NewCertPool()
+		pool.AddCert(server.Certificate())
+
+		client := NewClient(&ClientOptions{
+			BaseURL:       server.URL,
+			RetryAttempts: 1,
+			Logger:        &NoOpLogger{},
+			RootCAs:       pool,
+		})
+		working, err := client.IsWorkingDay(ctx)
+		require.NoError(t, err)
+		assert.True(t, working)
+	})
+
+	t.Run("explicit insecure opt-in", func(t *testing.T) {
+		client := NewClient(&ClientOptions{
+			BaseURL:            server.URL,
+			RetryAttempts:      1,
+			Logger:             &NoOpLogger{},
+			InsecureSkipVerify: true,
+		})
+		working, err := client.IsWorkingDay(ctx)
+		require.NoError(t, err)
+		assert.True(t, working)
+	})
+}
+
+// countingTransport counts the requests that go through it
+type countingTransport struct {
+	count int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.count, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClient_CustomHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"isWorkingDay": true}`))
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client := NewClient(&ClientOptions{
+		BaseURL:       server.URL,
+		RetryAttempts: 1,
+		Logger:        &NoOpLogger{},
+		HTTPClient:    &http.Client{Transport: transport},
+	})
+
+	before := atomic.LoadInt32(&transport.count)
+	working, err := client.IsWorkingDay(context.Background())
+	require.NoError(t, err)
+	assert.True(t, working)
+	assert.Equal(t, before+1, atomic.LoadInt32(&transport.count))
+}
+
+// infoTransport records the RequestInfo of every request it sends
+type infoTransport struct {
+	mu    sync.Mutex
+	infos []RequestInfo
+}
+
+func (t *infoTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if info, ok := RequestInfoFrom(req.Context()); ok {
+		t.mu.Lock()
+		t.infos = append(t.infos, info)
+		t.mu.Unlock()
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClient_RequestInfo(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) == "market-time" && requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"isWorkingDay": true}`))
+	}))
+	defer server.Close()
+
+	transport := &infoTransport{}
+	client := NewClient(&ClientOptions{
+		BaseURL:         server.URL,
+		RetryAttempts:   1,
+		RetryBaseDelay:  time.Millisecond,
+		RetryMaxDelay:   time.Millisecond,
+		Logger:          &NoOpLogger{},
+		HTTPClient:      &http.Client{Transport: transport},
+		SkipSessionInit: true,
+	})
+
+	_, err := client.IsWorkingDay(context.Background())
+	require.NoError(t, err)
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	assert.Equal(t, []RequestInfo{{Endpoint: "market-time", Attempt: 0}, {Endpoint: "market-time", Attempt: 1}}, transport.infos)
+
+	_, ok := RequestInfoFrom(context.Background())
+	assert.False(t, ok)
+}
+
 func TestClient_GetIndices(t *testing.T) {
 	mockResponse := map[string]interface{}{
 		"data": []map[string]interface{}{
@@ -207,102 +501,4026 @@ func TestClient_GetBluechips(t *testing.T) {
 	assert.Equal(t, "EQUITY", security.Group)
 }
 
-func TestClient_GetNews(t *testing.T) {
-	mockResponse := map[string]interface{}{
-		"data": []map[string]interface{}{
-			{
-				"fecha":      "2023-12-01T10:00:00Z",
-				"emisor":     "Test News Title",
-				"referencia": "Test news description",
-				"descarga":   "test-file.pdf",
-			},
-		},
+func TestClient_CacheTTL(t *testing.T) {
+	var bluechipRequests, newsRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/leading-equity"):
+			bluechipRequests.Add(1)
+		case strings.HasSuffix(r.URL.Path, "/bnown/byma-ads"):
+			newsRequests.Add(1)
+		}
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{
+		BaseURL:           server.URL,
+		RetryAttempts:     1,
+		Logger:            &NoOpLogger{},
+		CacheTTL:          50 * time.Millisecond,
+		CacheTTLOverrides: map[string]time.Duration{CacheNews: time.Hour},
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetBluechips(ctx)
+		require.NoError(t, err)
+		_, err = client.GetNews(ctx)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), bluechipRequests.Load())
+	assert.Equal(t, int32(1), newsRequests.Load())
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, err := client.GetBluechips(ctx)
+	require.NoError(t, err)
+	_, err = client.GetNews(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), bluechipRequests.Load(), "bluechips should expire after CacheTTL")
+	assert.Equal(t, int32(1), newsRequests.Load(), "news should use its longer override")
+}
+
+func TestClient_GetCacheInfo(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"leading-equity": map[string]interface{}{"data": []map[string]interface{}{{"symbol": "GGAL"}, {"symbol": "YPFD"}}},
+		"public-bonds":   map[string]interface{}{"data": []map[string]interface{}{{"symbol": "AL30"}}},
+	})
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	_, err := client.GetBluechips(ctx)
+	require.NoError(t, err)
+	_, err = client.GetBonds(ctx)
+	require.NoError(t, err)
+	_, err = client.GetOptions(ctx)
+	require.NoError(t, err)
+	_, err = client.GetNews(ctx)
+	require.NoError(t, err)
+	_, err = client.GetIncomeStatement(ctx, "GGAL")
+	require.NoError(t, err)
+
+	info := client.GetCacheInfo()
+	for _, category := range []string{CacheBluechips, CacheBonds, CacheOptions, CacheNews} {
+		require.Contains(t, info, category)
+		entry := info[category].(map[string]interface{})
+		assert.Contains(t, entry, "timestamp")
+		assert.Contains(t, entry, "age")
+		assert.Equal(t, true, entry["fresh"])
 	}
+	assert.Equal(t, 2, info[CacheBluechips].(map[string]interface{})["count"])
+	assert.Equal(t, 1, info[CacheBonds].(map[string]interface{})["count"])
+	assert.NotContains(t, info, CacheFutures)
+
+	statements := info[CacheIncomeStatements].(map[string]interface{})
+	assert.Equal(t, 1, statements["count"])
+	tickers := statements["tickers"].(map[string]interface{})
+	require.Contains(t, tickers, "GGAL")
+	assert.Equal(t, true, tickers["GGAL"].(map[string]interface{})["fresh"])
+}
 
+func TestClient_HistoryCache(t *testing.T) {
+	var requests atomic.Int32
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(mockResponse)
+		if path.Base(r.URL.Path) != "history" {
+			return
+		}
+		requests.Add(1)
+		fmt.Fprint(w, `{"s":"ok","t":[1700000000,1700086400],"o":[1,2],"h":[1,2],"l":[1,2],"c":[1,2],"v":[10,20]}`)
 	}))
 	defer server.Close()
 
 	client := createTestClient(server.URL)
 	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
 
-	news, err := client.GetNews(ctx)
+	for i := 0; i < 3; i++ {
+		data, err := client.GetHistory(ctx, "GGAL", "D", from, to)
+		require.NoError(t, err)
+		assert.Len(t, data.Close, 2)
+	}
+	assert.Equal(t, int32(1), requests.Load())
 
+	// Any change of symbol, resolution or range is a separate entry
+	_, err := client.GetHistory(ctx, "GGAL", "W", from, to)
 	require.NoError(t, err)
-	require.Len(t, news, 1)
+	_, err = client.GetHistory(ctx, "GGAL", "D", from, to.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load())
 
-	newsItem := news[0]
-	assert.Equal(t, "Test News Title", newsItem.Titulo)
-	assert.Equal(t, "Test news description", newsItem.Descripcion)
-	assert.Contains(t, newsItem.Descarga, "test-file.pdf")
-	assert.False(t, newsItem.Fecha.IsZero())
+	// Rolling ranges are normalized to the current day
+	for i := 0; i < 2; i++ {
+		_, err = client.GetHistoryLastDays(ctx, "GGAL", 30)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(4), requests.Load())
+
+	history := client.GetCacheInfo()[CacheHistory].(map[string]interface{})
+	assert.Equal(t, 4, history["count"])
+	keys := history["keys"].(map[string]interface{})
+	key := fmt.Sprintf("GGAL 24HS|D|%d|%d", from.Unix(), to.Unix())
+	require.Contains(t, keys, key)
+	assert.Equal(t, 2, keys[key].(map[string]interface{})["count"])
+
+	client.ClearCache()
+	assert.NotContains(t, client.GetCacheInfo(), CacheHistory)
+	_, err = client.GetHistory(ctx, "GGAL", "D", from, to)
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), requests.Load())
 }
 
-func TestBYMAError(t *testing.T) {
-	tests := []struct {
-		name      string
-		err       *BYMAError
-		wantStr   string
-		retryable bool
-	}{
-		{
-			name:      "simple error",
-			err:       ErrInvalidResponse,
-			wantStr:   "INVALID_RESPONSE: Invalid API response",
-			retryable: false,
-		},
-		{
-			name:      "error with underlying",
-			err:       ErrAPIUnavailable.WithUnderlying(assert.AnError),
-			retryable: true,
+func TestClient_ReturnedDataIsCopied(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"leading-equity": map[string]interface{}{"data": []map[string]interface{}{
+			{"symbol": "GGAL", "settlementPrice": 4500},
+			{"symbol": "YPFD", "settlementPrice": 30000},
+		}},
+		"chart/historical-series/history": map[string]interface{}{
+			"s": "ok", "t": []int64{1700000000, 1700086400},
+			"o": []float64{1, 2}, "h": []float64{1, 2}, "l": []float64{1, 2}, "c": []float64{1, 2},
+			"v": []int64{10, 20},
 		},
-		{
-			name:      "error with status code",
-			err:       ErrTimeout.WithStatusCode(408),
-			retryable: true,
-		},
-	}
+	})
+	defer server.Close()
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if tt.wantStr != "" {
-				assert.Equal(t, tt.wantStr, tt.err.Error())
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	bluechips, err := client.GetBluechips(ctx)
+	require.NoError(t, err)
+	require.Len(t, bluechips, 2)
+	bluechips[0].Last = 0
+	bluechips[1] = Security{Symbol: "XXXX"}
+
+	ggal, err := client.GetSecurity(ctx, "GGAL")
+	require.NoError(t, err)
+	ggal.Last = 1
+
+	batch, err := client.GetMultipleSecurities(ctx, []string{"YPFD"})
+	require.NoError(t, err)
+	batch["YPFD"].Last = 1
+
+	bluechips, err = client.GetBluechips(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "GGAL", bluechips[0].Symbol)
+	assert.Equal(t, 4500.0, bluechips[0].Last)
+	assert.Equal(t, "YPFD", bluechips[1].Symbol)
+	assert.Equal(t, 30000.0, bluechips[1].Last)
+
+	ggal, err = client.GetSecurity(ctx, "GGAL")
+	require.NoError(t, err)
+	assert.Equal(t, 4500.0, ggal.Last)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	history, err := client.GetHistory(ctx, "GGAL", "D", from, to)
+	require.NoError(t, err)
+	history.Close[0] = 100
+	history.Volume = nil
+
+	history, err = client.GetHistory(ctx, "GGAL", "D", from, to)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1, 2}, history.Close)
+	assert.Equal(t, []int64{10, 20}, history.Volume)
+}
+
+func TestClient_WithFreshData(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch path.Base(r.URL.Path) {
+		case "leading-equity":
+			n := requests.Add(1)
+			fmt.Fprintf(w, `{"data":[{"symbol":"GGAL","settlementPrice":%d}]}`, 4500+n)
+		case "cedears", "negociable-obligations", "options":
+			fmt.Fprint(w, `[]`)
+		default:
+			fmt.Fprint(w, `{"data":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	ggal, err := client.GetSecurity(ctx, "GGAL")
+	require.NoError(t, err)
+	assert.Equal(t, 4501.0, ggal.Last)
+	_, err = client.GetBonds(ctx)
+	require.NoError(t, err)
+
+	ggal, err = client.GetSecurity(WithFreshData(ctx), "GGAL")
+	require.NoError(t, err)
+	assert.Equal(t, 4502.0, ggal.Last)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// The fresh data replaced the cached entry and other categories were kept
+	ggal, err = client.GetSecurity(ctx, "GGAL")
+	require.NoError(t, err)
+	assert.Equal(t, 4502.0, ggal.Last)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Contains(t, client.GetCacheInfo(), CacheBonds)
+
+	// Symbols remembered as missing are looked up again
+	client = NewClient(&ClientOptions{
+		BaseURL:          server.URL,
+		RetryAttempts:    1,
+		Logger:           &NoOpLogger{},
+		NegativeCacheTTL: time.Minute,
+	})
+	_, err = client.GetSecurity(ctx, "NOPE")
+	require.ErrorIs(t, err, ErrInvalidTicker)
+	before := requests.Load()
+	_, err = client.GetSecurity(WithFreshData(ctx), "NOPE")
+	require.ErrorIs(t, err, ErrInvalidTicker)
+	assert.Equal(t, before+1, requests.Load())
+}
+
+func TestClient_Settlement(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		switch path.Base(r.URL.Path) {
+		case "leading-equity":
+			requests.Add(1)
+			price := 4500
+			if payload["T0"] == true {
+				price = 4400
 			}
-			assert.Equal(t, tt.retryable, IsRetryable(tt.err))
-		})
-	}
+			fmt.Fprintf(w, `{"data":[{"symbol":"GGAL","settlementPrice":%d}]}`, price)
+		case "public-bonds":
+			assert.Equal(t, true, payload["T2"], "bonds requested with T2")
+			assert.Equal(t, false, payload["T1"])
+			fmt.Fprint(w, `{"data":[{"symbol":"AL30"}]}`)
+		case "cedears", "negociable-obligations", "options":
+			fmt.Fprint(w, `[]`)
+		default:
+			fmt.Fprint(w, `{"data":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+	ci := WithSettlement(ctx, "t0")
+
+	ggal, err := client.GetSecurity(ctx, "GGAL")
+	require.NoError(t, err)
+	assert.Equal(t, 4500.0, ggal.Last)
+	ggal, err = client.GetSecurity(ci, "GGAL")
+	require.NoError(t, err)
+	assert.Equal(t, 4400.0, ggal.Last)
+
+	// Each settlement is cached apart
+	ggal, err = client.GetSecurity(ctx, "GGAL")
+	require.NoError(t, err)
+	assert.Equal(t, 4500.0, ggal.Last)
+	ggal, err = client.GetSecurity(ci, "GGAL")
+	require.NoError(t, err)
+	assert.Equal(t, 4400.0, ggal.Last)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Contains(t, client.GetCacheInfo(), CacheBluechips)
+	assert.Contains(t, client.GetCacheInfo(), CacheBluechips+":T0")
+
+	bonds, err := client.GetBonds(WithSettlement(ctx, SettlementT2))
+	require.NoError(t, err)
+	assert.Len(t, bonds, 1)
+
+	_, err = client.GetBluechips(WithSettlement(ctx, "T3"))
+	require.ErrorIs(t, err, ErrInvalidSettlement)
+	_, err = client.GetSecurity(WithSettlement(ctx, "T3"), "GGAL")
+	require.ErrorIs(t, err, ErrInvalidSettlement)
+
+	client.ClearCache()
+	assert.Empty(t, client.GetCacheInfo())
 }
 
-func TestMapHTTPError(t *testing.T) {
-	tests := []struct {
-		statusCode int
-		wantCode   string
-	}{
-		{http.StatusUnauthorized, "UNAUTHORIZED"},
-		{http.StatusTooManyRequests, "RATE_LIMITED"},
-		{http.StatusInternalServerError, "API_UNAVAILABLE"},
-		{http.StatusRequestTimeout, "TIMEOUT"},
-		{http.StatusNotFound, "HTTP_ERROR"},
-	}
+func TestPortfolio_Value(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"leading-equity": map[string]interface{}{"data": []map[string]interface{}{
+			{"symbol": "GGAL", "settlementPrice": 110, "imbalance": 10},
+		}},
+		"cedears": []map[string]interface{}{
+			{"symbol": "AAPL", "settlementPrice": 200, "imbalance": -20},
+		},
+	})
+	defer server.Close()
+	client := createTestClient(server.URL)
+	ctx := context.Background()
 
-	for _, tt := range tests {
-		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
-			err := MapHTTPError(tt.statusCode)
-			assert.Equal(t, tt.wantCode, err.Code)
-			assert.Equal(t, tt.statusCode, err.StatusCode)
-		})
-	}
+	var portfolio Portfolio
+	valuation, err := portfolio.Value(ctx, client)
+	require.NoError(t, err)
+	assert.Empty(t, valuation.Holdings)
+	assert.True(t, valuation.MarketValue.IsZero())
+
+	portfolio.AddHolding("GGAL", 100)
+	portfolio.AddHolding("aapl", 2)
+	portfolio.AddHolding("XXXX", 10)
+	portfolio.AddHolding(" ggal", 50)
+	assert.Equal(t, []Holding{{"GGAL", 150}, {"AAPL", 2}, {"XXXX", 10}}, portfolio.Holdings())
+
+	valuation, err = portfolio.Value(ctx, client)
+	require.NoError(t, err)
+	require.Len(t, valuation.Holdings, 3)
+	assert.Equal(t, "16500", valuation.Holdings[0].MarketValue.String())
+	assert.Equal(t, "1500", valuation.Holdings[0].DayPnL.String(), "previous close 100")
+	assert.Equal(t, "400", valuation.Holdings[1].MarketValue.String())
+	assert.Equal(t, "-100", valuation.Holdings[1].DayPnL.String(), "previous close 250")
+	assert.Nil(t, valuation.Holdings[2].Security)
+	assert.Equal(t, []string{"XXXX"}, valuation.NotFound)
+	assert.Equal(t, "16900", valuation.MarketValue.String())
+	assert.Equal(t, "1400", valuation.DayPnL.String())
 }
 
-// createTestClient creates a client configured for testing with a test server
-func createTestClient(baseURL string) Client {
-	opts := &ClientOptions{
-		BaseURL:       baseURL,
-		Timeout:       5 * time.Second,
-		RetryAttempts: 1,
-		Logger:        &NoOpLogger{},
-	}
-	return NewClient(opts)
+func TestClient_GetMultipleSecuritiesPartial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch path.Base(r.URL.Path) {
+		case "leading-equity":
+			fmt.Fprint(w, `{"data":[{"symbol":"GGAL","settlementPrice":4500}]}`)
+		case "cedears":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			fmt.Fprint(w, `{"data":[]}`)
+		}
+	}))
+	defer server.Close()
+	client := createTestClient(server.URL)
+
+	quotes, errs := client.GetMultipleSecuritiesPartial(context.Background(), []string{"GGAL", "AAPL"})
+	require.Contains(t, quotes, "GGAL")
+	assert.Equal(t, 4500.0, quotes["GGAL"].Last)
+	assert.NotContains(t, quotes, "AAPL")
+	require.Len(t, errs, 1)
+	assert.Equal(t, CacheCedears, errs[0].Collection)
+	assert.ErrorIs(t, errs[0], ErrAPIUnavailable)
+
+	// Once ctx is done, the cached collections still resolve
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	quotes, errs = client.GetMultipleSecuritiesPartial(ctx, []string{"GGAL"})
+	assert.Contains(t, quotes, "GGAL")
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], context.Canceled)
+}
+
+func TestClient_GetAllSecurities(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"leading-equity": map[string]interface{}{"data": []map[string]interface{}{
+			{"symbol": "GGAL", "settlementPrice": 4500},
+		}},
+		"cedears": []map[string]interface{}{
+			{"symbol": "AAPL", "settlementPrice": 15000},
+		},
+		"general-equity": map[string]interface{}{"data": []map[string]interface{}{
+			{"symbol": "ggal", "settlementPrice": 1},
+			{"symbol": "MOLA", "settlementPrice": 3000},
+		}},
+		"etf": map[string]interface{}{"data": []map[string]interface{}{
+			{"symbol": "SPY", "settlementPrice": 30000},
+		}},
+	})
+	defer server.Close()
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	all, err := client.GetAllSecurities(ctx)
+	require.NoError(t, err)
+	var symbols, collections []string
+	for _, security := range all {
+		symbols = append(symbols, security.Symbol)
+		collections = append(collections, security.Collection)
+	}
+	assert.Equal(t, []string{"GGAL", "AAPL", "MOLA", "SPY"}, symbols)
+	assert.Equal(t, []string{CacheBluechips, CacheCedears, CacheGalpones, CacheEtfs}, collections)
+	assert.Equal(t, 4500.0, all[0].Last, "the first collection wins")
+
+	// The result is the caller's and the collections are cached
+	all[0].Last = 0
+	cedears, err := client.GetCedears(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, CacheCedears, cedears[0].Collection)
+	all, err = client.GetAllSecurities(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4500.0, all[0].Last)
+	assert.Equal(t, int64(5), client.CacheStats().Hits)
+}
+
+func TestClient_IterCollections(t *testing.T) {
+	var requests atomic.Int32
+	mock := newMockServer(map[string]interface{}{
+		"cedears": []map[string]interface{}{
+			{"symbol": "AAPL", "settlementPrice": 15000},
+			{"symbol": "MSFT", "settlementPrice": 20000},
+			{"symbol": "KO", "settlementPrice": 9000},
+		},
+		"public-bonds": map[string]interface{}{"data": []map[string]interface{}{{"symbol": "AL30"}}},
+	})
+	defer mock.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) == "cedears" {
+			requests.Add(1)
+		}
+		if path.Base(r.URL.Path) == "options" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mock.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	var symbols []string
+	for cedear, err := range client.IterCedears(ctx) {
+		require.NoError(t, err)
+		symbols = append(symbols, cedear.Symbol)
+	}
+	assert.Equal(t, []string{"AAPL", "MSFT", "KO"}, symbols)
+
+	// Breaking out stops the iteration; the collection stays cached
+	var seen int
+	for range client.IterCedears(ctx) {
+		seen++
+		break
+	}
+	assert.Equal(t, 1, seen)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// Items are copies: changing them leaves the cache untouched
+	for cedear := range client.IterCedears(ctx) {
+		cedear.Last = 0
+	}
+	cedears, err := client.GetCedears(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 15000.0, cedears[0].Last)
+
+	for bond, err := range client.IterBonds(ctx) {
+		require.NoError(t, err)
+		assert.Equal(t, "AL30", bond.Symbol)
+	}
+
+	// A failed fetch yields a single error
+	var errs []error
+	for _, err := range client.IterOptions(ctx) {
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 1)
+	assert.Error(t, errs[0])
+
+	// So does a context cancelled while iterating
+	cancelled, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errs = nil
+	for _, err := range client.IterCedears(cancelled) {
+		cancel()
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 2)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], context.Canceled)
+}
+
+func TestClient_HistoryResolution(t *testing.T) {
+	var resolutions []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) != "history" {
+			return
+		}
+		mu.Lock()
+		resolutions = append(resolutions, r.URL.Query().Get("resolution"))
+		mu.Unlock()
+		fmt.Fprint(w, `{"s":"ok","t":[],"o":[],"h":[],"l":[],"c":[],"v":[]}`)
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, resolution := range []string{"d", "1D", "Weekly", " month "} {
+		_, err := client.GetHistory(ctx, "GGAL", resolution, from, to)
+		require.NoError(t, err, resolution)
+	}
+	assert.Equal(t, []string{"D", "W", "M"}, resolutions, "aliases share the cache entry of their resolution")
+
+	for _, resolution := range []string{"", "1H", "Y", "dd"} {
+		_, err := client.GetHistory(ctx, "GGAL", resolution, from, to)
+		var bymaErr *BYMAError
+		require.ErrorAs(t, err, &bymaErr, resolution)
+		assert.Equal(t, ErrInvalidResolution.Code, bymaErr.Code)
+	}
+	assert.Len(t, resolutions, 3)
+}
+
+func TestClient_HistoryResponseErrors(t *testing.T) {
+	var response atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) == "history" {
+			fmt.Fprint(w, response.Load())
+		}
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	response.Store(`{"s":"no_data"}`)
+	_, err := client.GetHistory(ctx, "XYZ", "D", from, to)
+	require.ErrorIs(t, err, ErrNoHistory)
+	assert.ErrorContains(t, err, "XYZ 24HS from 2024-01-01 to 2024-01-31")
+
+	// Other statuses are failures, not missing data
+	response.Store(`{"s":"error","errmsg":"unknown symbol"}`)
+	_, err = client.GetHistory(ctx, "ABC", "D", from, to)
+	require.ErrorIs(t, err, ErrInvalidResponse)
+	assert.NotErrorIs(t, err, ErrNoHistory)
+	assert.ErrorContains(t, err, "unknown symbol")
+
+	// Arrays of different lengths are rejected instead of panicking when indexed later
+	response.Store(`{"s":"ok","t":[1704153600,1704240000],"o":[1,2],"h":[1,2],"l":[1,2],"c":[1],"v":[10,20]}`)
+	_, err = client.GetHistory(ctx, "GGAL", "D", from, to)
+	var bymaErr *BYMAError
+	require.ErrorAs(t, err, &bymaErr)
+	assert.Equal(t, ErrInvalidResponse.Code, bymaErr.Code)
+	assert.ErrorContains(t, err, "c=1")
+
+	// Malformed bodies are typed too
+	response.Store(`<html>maintenance</html>`)
+	_, err = client.GetHistory(ctx, "YPFD", "D", from, to)
+	require.ErrorAs(t, err, &bymaErr)
+	assert.Equal(t, ErrInvalidResponse.Code, bymaErr.Code)
+	assert.False(t, IsRetryable(err))
+}
+
+func TestClient_HistoryTransportErrors(t *testing.T) {
+	var status atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) != "history" {
+			return
+		}
+		if code := int(status.Load()); code != 0 {
+			w.WriteHeader(code)
+			return
+		}
+		// Drop the connection without answering
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{
+		BaseURL:        server.URL,
+		RetryAttempts:  1,
+		RetryBaseDelay: time.Millisecond,
+		RetryMaxDelay:  time.Millisecond,
+		Logger:         &NoOpLogger{},
+	})
+	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	_, err := client.GetHistory(ctx, "GGAL", "D", from, to)
+	var bymaErr *BYMAError
+	require.ErrorAs(t, err, &bymaErr)
+	assert.Equal(t, ErrAPIUnavailable.Code, bymaErr.Code)
+	assert.True(t, IsRetryable(err))
+	assert.ErrorContains(t, err, "failed to get history data")
+
+	// HTTP errors keep their own code and status
+	status.Store(http.StatusTooManyRequests)
+	_, err = client.GetHistory(ctx, "GGAL", "D", from, to.AddDate(0, 0, -1))
+	require.ErrorAs(t, err, &bymaErr)
+	assert.Equal(t, ErrRateLimited.Code, bymaErr.Code)
+	assert.Equal(t, http.StatusTooManyRequests, bymaErr.StatusCode)
+
+	// Cancellation is not reported as an outage
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = client.GetHistory(cancelled, "GGAL", "D", from, to.AddDate(0, 0, -2))
+	require.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, ErrAPIUnavailable)
+}
+
+func TestClient_HistoryDateRange(t *testing.T) {
+	var (
+		requests atomic.Int32
+		lastTo   atomic.Int64
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) != "history" {
+			return
+		}
+		requests.Add(1)
+		to, _ := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+		lastTo.Store(to)
+		fmt.Fprint(w, `{"s":"ok","t":[],"o":[],"h":[],"l":[],"c":[],"v":[]}`)
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+	now := time.Now()
+	from := now.AddDate(0, -1, 0)
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+	}{
+		{"zero from", time.Time{}, now},
+		{"zero to", from, time.Time{}},
+		{"swapped", now, from},
+		{"future", now.AddDate(0, 0, 1), now.AddDate(0, 0, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.GetHistory(ctx, "GGAL", "D", tt.from, tt.to)
+			var bymaErr *BYMAError
+			require.ErrorAs(t, err, &bymaErr)
+			assert.Equal(t, ErrInvalidDateRange.Code, bymaErr.Code)
+		})
+	}
+	assert.Zero(t, requests.Load())
+
+	// A range ending in the future is clamped to now
+	_, err := client.GetHistory(ctx, "GGAL", "D", from, now.AddDate(0, 0, 10))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+	assert.LessOrEqual(t, lastTo.Load(), time.Now().Unix())
+}
+
+func TestClient_HistoryCacheSession(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) != "history" {
+			return
+		}
+		requests.Add(1)
+		fmt.Fprint(w, `{"s":"ok","t":[1700000000],"o":[1],"h":[1],"l":[1],"c":[1],"v":[10]}`)
+	}))
+	defer server.Close()
+
+	buenosAires := time.FixedZone("ART", -3*60*60)
+	clock := time.Date(2024, 3, 13, 12, 0, 0, 0, buenosAires) // A Wednesday, session open
+	marketClock = func() time.Time { return clock }
+	t.Cleanup(func() { marketClock = time.Now })
+
+	client := NewClient(&ClientOptions{
+		BaseURL:       server.URL,
+		RetryAttempts: 1,
+		Logger:        &NoOpLogger{},
+		CacheTTL:      20 * time.Millisecond,
+	})
+	ctx := context.Background()
+	fetch := func() {
+		_, err := client.GetHistoryLastDays(ctx, "GGAL", 30)
+		require.NoError(t, err)
+	}
+
+	// While the session is open, today's bar is refreshed with the cache TTL
+	fetch()
+	time.Sleep(30 * time.Millisecond)
+	fetch()
+	assert.Equal(t, int32(2), requests.Load())
+
+	// After the close the bar is final and served from cache past the TTL
+	clock = time.Date(2024, 3, 13, 18, 0, 0, 0, buenosAires)
+	client.ClearCache()
+	fetch()
+	time.Sleep(30 * time.Millisecond)
+	fetch()
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestClient_GetHistoryIntraday(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		queries []url.Values
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) != "history" {
+			return
+		}
+		mu.Lock()
+		queries = append(queries, r.URL.Query())
+		mu.Unlock()
+		fmt.Fprint(w, `{"s":"ok","t":[1700000000],"o":[1],"h":[1],"l":[1],"c":[1],"v":[10]}`)
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+	to := time.Now().Add(-time.Hour)
+
+	_, err := client.GetHistoryIntraday(ctx, "GGAL", 5, to.Add(-6*time.Hour), to)
+	require.NoError(t, err)
+	_, err = client.GetHistory(ctx, "GGAL", " 60 ", to.Add(-6*time.Hour), to)
+	require.NoError(t, err)
+
+	// Intraday ranges are limited to MaxIntradayRange
+	_, err = client.GetHistoryIntraday(ctx, "GGAL", 1, to.AddDate(-1, 0, 0), to)
+	require.NoError(t, err)
+
+	_, err = client.GetHistoryIntraday(ctx, "GGAL", 7, to.Add(-time.Hour), to)
+	var bymaErr *BYMAError
+	require.ErrorAs(t, err, &bymaErr)
+	assert.Equal(t, ErrInvalidResolution.Code, bymaErr.Code)
+
+	require.Len(t, queries, 3)
+	assert.Equal(t, "5", queries[0].Get("resolution"))
+	assert.Equal(t, "60", queries[1].Get("resolution"))
+	assert.Equal(t, "1", queries[2].Get("resolution"))
+	from, _ := strconv.ParseInt(queries[2].Get("from"), 10, 64)
+	assert.Equal(t, to.Add(-MaxIntradayRange).Unix(), from)
+}
+
+func TestClient_GetHistoryLastTradingDays(t *testing.T) {
+	// Sessions only on Mondays, Wednesdays and Fridays, and none before firstSession,
+	// so the first calendar range falls short and has to be widened
+	firstSession := time.Now().AddDate(0, 0, -200)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) != "history" {
+			return
+		}
+		requests.Add(1)
+		from, _ := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+		to, _ := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+		var times []int64
+		for day := time.Unix(from, 0); !day.After(time.Unix(to, 0)); day = day.AddDate(0, 0, 1) {
+			switch day.Weekday() {
+			case time.Monday, time.Wednesday, time.Friday:
+				if day.After(firstSession) {
+					times = append(times, day.Unix())
+				}
+			}
+		}
+		prices := make([]float64, len(times))
+		volumes := make([]int64, len(times))
+		for i := range times {
+			prices[i] = float64(i)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"s": "ok", "t": times, "o": prices, "h": prices, "l": prices, "c": prices, "v": volumes,
+		})
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	history, err := client.GetHistoryLastTradingDays(ctx, "GGAL", 30)
+	require.NoError(t, err)
+	assert.Len(t, history.Close, 30)
+	assert.Len(t, history.Time, 30)
+	assert.WithinDuration(t, time.Now(), history.Time[29], 4*24*time.Hour, "the most recent bars are kept")
+	assert.Equal(t, int32(2), requests.Load(), "the range is widened once")
+
+	requests.Store(0)
+	_, err = client.GetHistoryLastTradingDays(ctx, "GGAL", 200)
+	assert.ErrorIs(t, err, ErrNoHistory, "a wider range adds no older bars")
+	assert.Equal(t, int32(2), requests.Load())
+
+	_, err = client.GetHistoryLastTradingDays(ctx, "GGAL", 0)
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+}
+
+func TestClient_HistorySettlement(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		symbols []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) != "history" {
+			return
+		}
+		mu.Lock()
+		symbols = append(symbols, r.URL.Query().Get("symbol"))
+		mu.Unlock()
+		fmt.Fprint(w, `{"s":"ok","t":[],"o":[],"h":[],"l":[],"c":[],"v":[]}`)
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, symbol := range []string{"GGAL", "GGAL 24HS", "AL30 CI", " AL30  48HS "} {
+		_, err := client.GetHistory(ctx, symbol, "D", from, to)
+		require.NoError(t, err, symbol)
+	}
+	assert.Equal(t, []string{"GGAL 24HS", "AL30 CI", "AL30 48HS"}, symbols)
+}
+
+func TestClient_GetDollarRates(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"public-bonds": map[string]interface{}{"data": []map[string]interface{}{
+			{"symbol": "AL30", "settlementPrice": 60000},
+			{"symbol": "AL30D", "settlementPrice": 50},
+			{"symbol": "AL30C", "settlementPrice": 48},
+			{"symbol": "GD30", "settlementPrice": 62000},
+			{"symbol": "GD30D", "settlementPrice": 0, "closingPrice": 50}, // not traded yet today
+			{"symbol": "GD30C", "settlementPrice": 0},                     // no price at all
+		}},
+	})
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	rates, err := client.GetDollarRates(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []DollarRate{
+		{Name: "MEP", PesoSymbol: "AL30", DollarSymbol: "AL30D", PesoPrice: 60000, DollarPrice: 50, Rate: 1200},
+		{Name: "MEP", PesoSymbol: "GD30", DollarSymbol: "GD30D", PesoPrice: 62000, DollarPrice: 50, Rate: 1240},
+		{Name: "CCL", PesoSymbol: "AL30", DollarSymbol: "AL30C", PesoPrice: 60000, DollarPrice: 48, Rate: 1250},
+	}, rates)
+}
+
+func TestClient_TradeDate(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"cedears": []map[string]interface{}{
+			// Friday's close fetched after the weekend
+			{"symbol": "AAPL", "tradeHour": "16:59:30", "tradeDate": "2024-03-01"},
+			{"symbol": "MSFT", "tradeHour": "16:58", "date": "01/03/2024"},
+			{"symbol": "KO", "tradeHour": "2024-02-29T19:57:00Z"},
+			{"symbol": "PEP", "tradeHour": "16:56:00"},
+		},
+	})
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	cedears, err := client.GetCedears(context.Background())
+	require.NoError(t, err)
+	require.Len(t, cedears, 4)
+
+	dates := make(map[string]string, len(cedears))
+	for _, cedear := range cedears {
+		dates[cedear.Symbol] = cedear.DateTime.Format("2006-01-02 15:04:05")
+	}
+	assert.Equal(t, "2024-03-01 16:59:30", dates["AAPL"])
+	assert.Equal(t, "2024-03-01 16:58:00", dates["MSFT"])
+	assert.Equal(t, "2024-02-29 16:57:00", dates["KO"])
+	today := time.Now().In(cedears[0].DateTime.Location()).Format("2006-01-02")
+	assert.Equal(t, today+" 16:56:00", dates["PEP"], "no trading date falls back to today")
+
+	// Session times are in Buenos Aires (UTC-3), whatever the local zone
+	for _, cedear := range cedears {
+		_, offset := cedear.DateTime.Zone()
+		assert.Equal(t, -3*60*60, offset, cedear.Symbol)
+	}
+	assert.Equal(t, 19, cedears[0].DateTime.UTC().Hour())
+}
+
+func TestClient_StrictParsing(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"cedears": []map[string]interface{}{
+			{"symbol": "AAPL", "bidPrice": "n/a", "offerPrice": "1.234,5"},
+			{"settlementPrice": 10},
+			{"symbol": "KO", "volume": true, "settlementPrice": nil},
+			{"symbol": "MSFT", "settlementPrice": 100},
+		},
+	})
+	defer server.Close()
+	ctx := context.Background()
+
+	// Lenient by default: malformed fields read as zero
+	cedears, err := createTestClient(server.URL).GetCedears(ctx)
+	require.NoError(t, err)
+	assert.Len(t, cedears, 4)
+
+	strict := NewClient(&ClientOptions{
+		BaseURL:       server.URL,
+		RetryAttempts: 1,
+		Logger:        &NoOpLogger{},
+		StrictParsing: true,
+	})
+	_, err = strict.GetCedears(ctx)
+	var bymaErr *BYMAError
+	require.ErrorAs(t, err, &bymaErr)
+	assert.Equal(t, ErrInvalidResponse.Code, bymaErr.Code)
+	assert.Contains(t, err.Error(), `item 0 (AAPL): field "bidPrice" is string n/a, expected a number`)
+	assert.Contains(t, err.Error(), `item 1 (): field "symbol" is missing`)
+	assert.Contains(t, err.Error(), `item 2 (KO): field "volume" is bool true, expected a number`)
+	assert.NotContains(t, err.Error(), "offerPrice")
+	assert.NotContains(t, err.Error(), "settlementPrice", "null optional fields are accepted")
+
+	value, err := utils.ParseFloat64(map[string]interface{}{"price": "1.234,5"}, "price")
+	require.NoError(t, err)
+	assert.Equal(t, 1234.5, value)
+	_, err = utils.ParseFloat64(map[string]interface{}{}, "price")
+	var fieldErr *utils.FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.True(t, fieldErr.Missing)
+}
+
+func TestClient_StaleWhileRevalidate(t *testing.T) {
+	var (
+		requests atomic.Int32
+		failing  atomic.Bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) != "cedears" {
+			return
+		}
+		n := requests.Add(1)
+		if n > 1 {
+			time.Sleep(100 * time.Millisecond) // make refreshes slow
+		}
+		if failing.Load() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `[{"symbol":"AAPL","settlementPrice":%d}]`, 99+n)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{
+		BaseURL:              server.URL,
+		RetryAttempts:        1,
+		Logger:               &NoOpLogger{},
+		CacheTTL:             50 * time.Millisecond,
+		StaleWhileRevalidate: 10 * time.Second,
+	})
+	ctx := context.Background()
+
+	lastPrice := func() float64 {
+		cedears, err := client.GetCedears(ctx)
+		require.NoError(t, err)
+		require.Len(t, cedears, 1)
+		return cedears[0].Last
+	}
+
+	assert.Equal(t, 100.0, lastPrice())
+	time.Sleep(60 * time.Millisecond)
+
+	// Expired data is served immediately while a single refresh runs in the background
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, 100.0, lastPrice())
+	}
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	assert.Eventually(t, func() bool { return lastPrice() == 101 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// A failed refresh keeps serving the cached data
+	failing.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, 101.0, lastPrice())
+	assert.Eventually(t, func() bool { return requests.Load() == 3 }, time.Second, 10*time.Millisecond)
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, 101.0, lastPrice())
+}
+
+func TestClient_NegativeCache(t *testing.T) {
+	var (
+		requests atomic.Int32
+		listed   atomic.Bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch path.Base(r.URL.Path) {
+		case "cedears":
+			if listed.Load() {
+				fmt.Fprint(w, `[{"symbol":"AAPL"},{"symbol":"NEWCO"}]`)
+				return
+			}
+			fmt.Fprint(w, `[{"symbol":"AAPL"}]`)
+		default:
+			fmt.Fprint(w, `{"data":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{
+		BaseURL:          server.URL,
+		RetryAttempts:    1,
+		Logger:           &NoOpLogger{},
+		CacheTTL:         50 * time.Millisecond,
+		NegativeCacheTTL: time.Minute,
+	})
+	ctx := context.Background()
+
+	assertNotFound := func(symbol string) {
+		_, err := client.GetSecurity(ctx, symbol)
+		var bymaErr *BYMAError
+		require.ErrorAs(t, err, &bymaErr)
+		assert.Equal(t, "INVALID_TICKER", bymaErr.Code)
+	}
+
+	assertNotFound("NEWCO")
+	fetched := requests.Load()
+	require.Positive(t, fetched)
+
+	// Remembered even after the collections expire
+	time.Sleep(60 * time.Millisecond)
+	assertNotFound(" newco ")
+	assert.Equal(t, fetched, requests.Load())
+
+	// Clearing the cache forgets the negative entry
+	client.ClearCache()
+	assertNotFound("NEWCO")
+	assert.Greater(t, requests.Load(), fetched)
+
+	// So does refreshing a collection, which picks up the new listing
+	listed.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	_, err := client.GetCedears(ctx)
+	require.NoError(t, err)
+	security, err := client.GetSecurity(ctx, "NEWCO")
+	require.NoError(t, err)
+	assert.Equal(t, "NEWCO", security.Symbol)
+}
+
+func TestClient_CacheStats(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"cedears": []map[string]interface{}{{"symbol": "AAPL"}},
+	})
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := client.GetCedears(ctx)
+		require.NoError(t, err)
+	}
+	_, err := client.GetNews(ctx)
+	require.NoError(t, err)
+
+	stats := client.CacheStats()
+	assert.Equal(t, CacheCategoryStats{Hits: 2, Misses: 1}, stats.Categories[CacheCedears])
+	assert.Equal(t, CacheCategoryStats{Hits: 0, Misses: 1}, stats.Categories[CacheNews])
+	assert.Equal(t, int64(2), stats.Hits)
+	assert.Equal(t, int64(2), stats.Misses)
+	assert.InDelta(t, 0.5, stats.HitRate(), 1e-9)
+
+	client.ResetStats()
+	stats = client.CacheStats()
+	assert.Zero(t, stats.Hits)
+	assert.Zero(t, stats.Misses)
+	assert.Zero(t, stats.HitRate())
+
+	_, err = client.GetCedears(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, CacheCategoryStats{Hits: 1}, client.CacheStats().Categories[CacheCedears])
+}
+
+func TestClient_CacheDir(t *testing.T) {
+	var cedearRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) == "cedears" {
+			cedearRequests.Add(1)
+			w.Write([]byte(`[{"symbol":"AAPL","settlementPrice":15000}]`))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	newClient := func(ttl time.Duration) Client {
+		return NewClient(&ClientOptions{
+			BaseURL:       server.URL,
+			RetryAttempts: 1,
+			Logger:        &NoOpLogger{},
+			CacheDir:      dir,
+			CacheTTL:      ttl,
+		})
+	}
+	ctx := context.Background()
+
+	_, err := newClient(time.Minute).GetCedears(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), cedearRequests.Load())
+
+	// A new client (e.g. the next CLI run) starts warm from disk
+	cedears, err := newClient(time.Minute).GetCedears(ctx)
+	require.NoError(t, err)
+	require.Len(t, cedears, 1)
+	assert.Equal(t, "AAPL", cedears[0].Symbol)
+	assert.Equal(t, 15000.0, cedears[0].Last)
+	assert.Equal(t, int32(1), cedearRequests.Load())
+
+	// Files older than the TTL are ignored
+	time.Sleep(20 * time.Millisecond)
+	_, err = newClient(10 * time.Millisecond).GetCedears(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), cedearRequests.Load())
+
+	// Corrupt files are treated as a miss
+	require.NoError(t, os.WriteFile(filepath.Join(dir, CacheCedears+".json"), []byte(`{"timestamp":`), 0o644))
+	_, err = newClient(time.Minute).GetCedears(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), cedearRequests.Load())
+}
+
+func TestClient_Flush(t *testing.T) {
+	var cedearRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) == "cedears" {
+			cedearRequests.Add(1)
+			w.Write([]byte(`[{"symbol":"AAPL","settlementPrice":15000}]`))
+		}
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "cache")
+	newClient := func() Client {
+		return NewClient(&ClientOptions{
+			BaseURL:       server.URL,
+			RetryAttempts: 1,
+			Logger:        &NoOpLogger{},
+			CacheDir:      dir,
+		})
+	}
+	ctx := context.Background()
+	file := filepath.Join(dir, CacheCedears+".json")
+
+	// A flushed cache is readable by a new client, even if an earlier write was lost
+	client := newClient()
+	_, err := client.GetCedears(ctx)
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(file))
+	require.NoError(t, client.Flush())
+
+	cedears, err := newClient().GetCedears(ctx)
+	require.NoError(t, err)
+	require.Len(t, cedears, 1)
+	assert.Equal(t, 15000.0, cedears[0].Last)
+	assert.Equal(t, int32(1), cedearRequests.Load())
+
+	// A failed write leaves the previous file intact and no temporary file behind
+	previous, err := os.ReadFile(file)
+	require.NoError(t, err)
+	t.Run("failed write", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("root can write to read-only directories")
+		}
+		require.NoError(t, os.Chmod(dir, 0o500))
+		defer os.Chmod(dir, 0o755)
+
+		err := client.Flush()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), CacheCedears)
+		current, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, previous, current)
+		temps, err := filepath.Glob(file + ".tmp*")
+		require.NoError(t, err)
+		assert.Empty(t, temps)
+	})
+
+	// Temporary files left by interrupted writes are removed when the cache loads,
+	// unless they are recent enough to belong to a write in progress
+	stale, recent := file+".tmp123", file+".tmp456"
+	require.NoError(t, os.WriteFile(stale, previous[:len(previous)/2], 0o644))
+	require.NoError(t, os.Chtimes(stale, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)))
+	require.NoError(t, os.WriteFile(recent, previous[:len(previous)/2], 0o644))
+	cedears, err = newClient().GetCedears(ctx)
+	require.NoError(t, err)
+	assert.Len(t, cedears, 1)
+	assert.Equal(t, int32(1), cedearRequests.Load())
+	assert.NoFileExists(t, stale)
+	assert.FileExists(t, recent)
+
+	// Close writes the cache too, and reports the writes that failed
+	require.NoError(t, os.Remove(file))
+	require.NoError(t, client.Close())
+	assert.FileExists(t, file)
+
+	require.NoError(t, os.RemoveAll(dir))
+	require.NoError(t, os.WriteFile(dir, nil, 0o644))
+	err = client.Flush()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), CacheCedears)
+	assert.ErrorContains(t, client.Close(), CacheCedears)
+
+	// Without a cache directory there is nothing to write
+	assert.NoError(t, createTestClient(server.URL).Flush())
+}
+
+func TestClient_Snapshot(t *testing.T) {
+	var price atomic.Int32
+	price.Store(4500)
+	mock := newMockServer(map[string]interface{}{
+		"public-bonds": map[string]interface{}{"data": []map[string]interface{}{{"symbol": "AL30"}}},
+		"index-price":  map[string]interface{}{"data": []map[string]interface{}{{"symbol": "M"}}},
+	})
+	defer mock.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) == "leading-equity" {
+			fmt.Fprintf(w, `{"data":[{"symbol":"GGAL","settlementPrice":%d}]}`, price.Load())
+			return
+		}
+		mock.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	newClient := func() Client {
+		return NewClient(&ClientOptions{
+			BaseURL:       server.URL,
+			RetryAttempts: 1,
+			Logger:        &NoOpLogger{},
+			CacheDir:      dir,
+		})
+	}
+	client := newClient()
+	ctx := context.Background()
+
+	require.NoError(t, client.Snapshot(ctx, "close"))
+	price.Store(4600)
+	require.NoError(t, client.Snapshot(WithFreshData(ctx), "open"))
+
+	before, err := client.GetSnapshot("close")
+	require.NoError(t, err)
+	after, err := client.GetSnapshot("open")
+	require.NoError(t, err)
+	assert.Equal(t, "close", before.Name)
+	assert.False(t, before.Timestamp.IsZero())
+	require.Len(t, before.Bluechips, 1)
+	assert.Equal(t, 4500.0, before.Bluechips[0].Last)
+	assert.Equal(t, 4600.0, after.Bluechips[0].Last)
+	require.Len(t, before.Bonds, 1)
+	assert.Equal(t, "AL30", before.Bonds[0].Symbol)
+	require.Len(t, before.Indices, 1)
+	assert.Empty(t, before.Options)
+
+	// Snapshots are copies and survive ClearCache
+	before.Bluechips[0].Last = 0
+	client.ClearCache()
+	before, err = client.GetSnapshot("close")
+	require.NoError(t, err)
+	assert.Equal(t, 4500.0, before.Bluechips[0].Last)
+
+	// A new client reads the snapshots saved to CacheDir
+	restored, err := newClient().GetSnapshot("close")
+	require.NoError(t, err)
+	assert.Equal(t, before.Bluechips, restored.Bluechips)
+	assert.True(t, before.Timestamp.Equal(restored.Timestamp))
+
+	_, err = client.GetSnapshot("missing")
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+	for _, name := range []string{"", ".hidden", "../close", "a/b"} {
+		assert.ErrorIs(t, client.Snapshot(ctx, name), ErrInvalidSnapshotName, name)
+		_, err = client.GetSnapshot(name)
+		assert.ErrorIs(t, err, ErrInvalidSnapshotName, name)
+	}
+
+	// A collection that fails leaves no snapshot behind
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) == "options" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer failing.Close()
+	client = createTestClient(failing.URL)
+	assert.ErrorIs(t, client.Snapshot(ctx, "partial"), ErrAPIUnavailable)
+	_, err = client.GetSnapshot("partial")
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+}
+
+func TestClient_GetMultipleSecuritiesMaxAge(t *testing.T) {
+	var mu sync.Mutex
+	var failing atomic.Bool
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint := path.Base(r.URL.Path)
+		mu.Lock()
+		requests[endpoint]++
+		mu.Unlock()
+
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch endpoint {
+		case "leading-equity":
+			w.Write([]byte(`{"data":[{"symbol":"GGAL"}]}`))
+		case "cedears":
+			w.Write([]byte(`[{"symbol":"AAPL"}]`))
+		default:
+			w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	_, err := client.GetBluechips(ctx)
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	_, err = client.GetCedears(ctx)
+	require.NoError(t, err)
+	_, err = client.GetGalpones(ctx)
+	require.NoError(t, err)
+
+	// Blue chips are older than maxAge and get refreshed, the rest is reused
+	quotes, err := client.GetMultipleSecuritiesMaxAge(ctx, []string{"GGAL", "AAPL"}, 50*time.Millisecond)
+	require.NoError(t, err)
+	assert.Len(t, quotes, 2)
+
+	// Other calls are still served from the cache
+	_, err = client.GetCedears(ctx)
+	require.NoError(t, err)
+
+	mu.Lock()
+	assert.Equal(t, 2, requests["leading-equity"])
+	assert.Equal(t, 1, requests["cedears"])
+	assert.Equal(t, 1, requests["general-equity"])
+	mu.Unlock()
+
+	// A failed refetch leaves the cached data in place
+	failing.Store(true)
+	time.Sleep(10 * time.Millisecond)
+	_, err = client.GetMultipleSecuritiesMaxAge(ctx, []string{"GGAL"}, time.Millisecond)
+	assert.Error(t, err)
+
+	bluechips, err := client.GetBluechips(ctx)
+	require.NoError(t, err)
+	assert.Len(t, bluechips, 1)
+}
+
+func TestClient_SymbolLookupNormalization(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"cedears": []map[string]interface{}{
+			{"symbol": "AAPL"},
+		},
+		"leading-equity": map[string]interface{}{
+			"data": []map[string]interface{}{
+				{"symbol": "GGAL "},
+			},
+		},
+		"public-bonds": map[string]interface{}{
+			"data": []map[string]interface{}{
+				{"symbol": "AL30"},
+			},
+		},
+	})
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		symbol string
+	}{
+		{"lowercase", "aapl"},
+		{"mixed case", "AaPl"},
+		{"padded", "  AAPL\t"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cedear, err := client.GetCedear(ctx, tt.symbol)
+			require.NoError(t, err)
+			assert.Equal(t, "AAPL", cedear.Symbol)
+
+			security, err := client.GetSecurity(ctx, tt.symbol)
+			require.NoError(t, err)
+			assert.Equal(t, "AAPL", security.Symbol)
+		})
+	}
+
+	bond, err := client.GetBond(ctx, " al30")
+	require.NoError(t, err)
+	assert.Equal(t, "AL30", bond.Symbol)
+
+	quotes, err := client.GetMultipleSecurities(ctx, []string{"ggal", " aapl ", "MISSING"})
+	require.NoError(t, err)
+	require.Len(t, quotes, 2)
+	assert.Equal(t, "GGAL ", quotes["ggal"].Symbol)
+	assert.Equal(t, "AAPL", quotes[" aapl "].Symbol)
+}
+
+func TestClient_GetMultipleDerivativesAndBonds(t *testing.T) {
+	responses := map[string]interface{}{
+		"public-bonds":           map[string]interface{}{"data": []map[string]interface{}{{"symbol": "AL30"}, {"symbol": "GD30"}}},
+		"lebacs":                 map[string]interface{}{"data": []map[string]interface{}{{"symbol": "S31O5"}}},
+		"negociable-obligations": []map[string]interface{}{{"symbol": "YCA6O"}, {"symbol": "AL30"}},
+		"options":                []map[string]interface{}{{"symbol": "GFGC3000OC"}, {"symbol": "GFGV3000OC"}},
+		"index-future":           []map[string]interface{}{{"symbol": "DLR/OCT25"}},
+	}
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint := path.Base(r.URL.Path)
+		mu.Lock()
+		requests[endpoint]++
+		mu.Unlock()
+		if response, ok := responses[endpoint]; ok {
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	bonds, err := client.GetMultipleBonds(ctx, []string{"al30", "S31O5", " YCA6O", "MISSING"})
+	require.NoError(t, err)
+	require.Len(t, bonds, 3)
+	assert.Equal(t, "AL30", bonds["al30"].Symbol)
+	assert.Equal(t, "S31O5", bonds["S31O5"].Symbol)
+	assert.Equal(t, "YCA6O", bonds[" YCA6O"].Symbol)
+
+	options, err := client.GetMultipleOptions(ctx, []string{"GFGC3000OC", "gfgv3000oc", "GFGC9999OC"})
+	require.NoError(t, err)
+	require.Len(t, options, 2)
+	assert.Equal(t, "GFGV3000OC", options["gfgv3000oc"].Symbol)
+
+	futures, err := client.GetMultipleFutures(ctx, []string{"DLR/OCT25", "DLR/DIC25"})
+	require.NoError(t, err)
+	require.Len(t, futures, 1)
+	assert.Equal(t, "DLR/OCT25", futures["DLR/OCT25"].Symbol)
+
+	// Each collection is fetched once however many symbols are requested
+	_, err = client.GetMultipleBonds(ctx, []string{"GD30"})
+	require.NoError(t, err)
+	mu.Lock()
+	defer mu.Unlock()
+	for _, endpoint := range []string{"public-bonds", "lebacs", "negociable-obligations", "options", "index-future"} {
+		assert.Equal(t, 1, requests[endpoint], endpoint)
+	}
+}
+
+func TestClient_ConcurrentCollectionLoading(t *testing.T) {
+	const delay = 100 * time.Millisecond
+	mock := newMockServer(map[string]interface{}{
+		"leading-equity": map[string]interface{}{"data": []map[string]interface{}{{"symbol": "GGAL"}}},
+		"cedears":        []map[string]interface{}{{"symbol": "AAPL"}},
+		"general-equity": map[string]interface{}{"data": []map[string]interface{}{{"symbol": "MOLA"}}},
+		"etf":            map[string]interface{}{"data": []map[string]interface{}{{"symbol": "SPY"}}},
+	})
+	defer mock.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/bymadata/free/") {
+			time.Sleep(delay)
+		}
+		mock.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	// Each cold call would take at least three or four delays if fetched one by one
+	start := time.Now()
+	quotes, err := createTestClient(server.URL).GetMultipleSecurities(ctx, []string{"GGAL", "AAPL", "MOLA"})
+	elapsed := time.Since(start)
+	require.NoError(t, err)
+	assert.Len(t, quotes, 3)
+	assert.Less(t, elapsed, 2*delay, "collections should be fetched concurrently")
+
+	start = time.Now()
+	security, err := createTestClient(server.URL).GetSecurity(ctx, "SPY")
+	elapsed = time.Since(start)
+	require.NoError(t, err)
+	assert.Equal(t, "SPY", security.Symbol)
+	assert.Less(t, elapsed, 2*delay, "collections should be fetched concurrently")
+
+	start = time.Now()
+	results, err := createTestClient(server.URL).SearchSecurities(ctx, "A")
+	elapsed = time.Since(start)
+	require.NoError(t, err)
+	assert.NotEmpty(t, results)
+	assert.Less(t, elapsed, 2*delay, "collections should be fetched concurrently")
+
+	// The first failure is returned instead of a partial result
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/cedears") {
+			w.Write([]byte("not json"))
+			return
+		}
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer failing.Close()
+	_, err = createTestClient(failing.URL).GetMultipleSecurities(ctx, []string{"GGAL"})
+	assert.Error(t, err)
+}
+
+func TestClient_GetMultipleSecuritiesDetailed(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"cedears": []map[string]interface{}{
+			{"symbol": "AAPL"},
+		},
+		"leading-equity": map[string]interface{}{
+			"data": []map[string]interface{}{
+				{"symbol": "GGAL"},
+			},
+		},
+		"etf": map[string]interface{}{
+			"data": []map[string]interface{}{
+				{"symbol": "SPY"},
+			},
+		},
+	})
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	quotes, notFound, err := client.GetMultipleSecuritiesDetailed(ctx, []string{"XXXX", "AAPL", "GGAL", "SPY", "YYYY"})
+	require.NoError(t, err)
+	assert.Len(t, quotes, 3)
+	assert.Contains(t, quotes, "AAPL")
+	assert.Contains(t, quotes, "GGAL")
+	assert.Contains(t, quotes, "SPY")
+	assert.Equal(t, []string{"XXXX", "YYYY"}, notFound)
+
+	_, notFound, err = client.GetMultipleSecuritiesDetailed(ctx, []string{"AAPL"})
+	require.NoError(t, err)
+	assert.Empty(t, notFound)
+}
+
+func TestClient_SubscribeSecurities(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch path.Base(r.URL.Path) {
+		case "cedears":
+			// The price changes on every other poll
+			price := 100 + int(polls.Add(1))/2
+			fmt.Fprintf(w, `[{"symbol":"AAPL","settlementPrice":%d}]`, price)
+		default:
+			w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+
+	_, err := client.SubscribeSecurities(context.Background(), nil, time.Second)
+	assert.Error(t, err)
+	_, err = client.SubscribeSecurities(context.Background(), []string{"AAPL"}, 0)
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := client.SubscribeSecurities(ctx, []string{"AAPL", "MISSING"}, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	first := <-updates
+	assert.Equal(t, "AAPL", first.Symbol)
+	assert.Equal(t, 100.0, first.Security.Last)
+	assert.Nil(t, first.Previous)
+
+	second := <-updates
+	assert.Equal(t, "AAPL", second.Symbol)
+	require.NotNil(t, second.Previous)
+	assert.Equal(t, 100.0, second.Previous.Last)
+	assert.Equal(t, 101.0, second.Security.Last)
+
+	cancel()
+	closed := make(chan struct{})
+	go func() {
+		for range updates {
+		}
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("updates channel was not closed after cancellation")
+	}
+}
+
+func TestClient_StreamIndices(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) != "index-price" {
+			return
+		}
+		n := polls.Add(1)
+		switch {
+		case n == 2 || n == 3: // The second poll fails, including its retry
+			w.WriteHeader(http.StatusInternalServerError)
+		case n == 1:
+			w.Write([]byte(`{"data":[{"symbol":"M","price":100},{"symbol":"S","price":50}]}`))
+		default:
+			w.Write([]byte(`{"data":[{"symbol":"M","price":101},{"symbol":"S","price":50}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{
+		BaseURL:             server.URL,
+		RetryAttempts:       1,
+		RetryBaseDelay:      5 * time.Millisecond,
+		RetryMaxDelay:       20 * time.Millisecond,
+		Logger:              &NoOpLogger{},
+		IndexStreamInterval: 10 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	indices, err := client.StreamIndices(ctx)
+	require.NoError(t, err)
+
+	first := map[string]float64{}
+	for i := 0; i < 2; i++ {
+		index := <-indices
+		first[index.Symbol] = index.Last
+	}
+	assert.Equal(t, map[string]float64{"M": 100, "S": 50}, first)
+
+	// After the failed poll the stream reconnects and only emits the index that changed
+	changed := <-indices
+	assert.Equal(t, "M", changed.Symbol)
+	assert.Equal(t, 101.0, changed.Last)
+	assert.GreaterOrEqual(t, polls.Load(), int32(4))
+
+	cancel()
+	closed := make(chan struct{})
+	go func() {
+		for index := range indices {
+			assert.NotEqual(t, "S", index.Symbol)
+		}
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("indices channel was not closed after cancellation")
+	}
+
+	// The stream never drops the cached indices other calls are served from
+	before := polls.Load()
+	_, err = client.GetIndices(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, before, polls.Load())
+
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	_, err = client.StreamIndices(cancelled)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClient_GetEtfs(t *testing.T) {
+	var etfRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch path.Base(r.URL.Path) {
+		case "etf":
+			etfRequests.Add(1)
+			w.Write([]byte(`{"data":[{"symbol":"SPY","settlementPrice":42000},{"symbol":"QQQ","settlementPrice":35000}]}`))
+		case "cedears":
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	etfs, err := client.GetEtfs(ctx)
+	require.NoError(t, err)
+	assert.Len(t, etfs, 2)
+
+	spy, err := client.GetEtf(ctx, "SPY")
+	require.NoError(t, err)
+	assert.Equal(t, 42000.0, spy.Last)
+
+	_, err = client.GetEtf(ctx, "GGAL")
+	assert.Error(t, err)
+
+	// Universal search includes ETFs
+	qqq, err := client.GetSecurity(ctx, "qqq")
+	require.NoError(t, err)
+	assert.Equal(t, "QQQ", qqq.Symbol)
+
+	assert.Equal(t, int32(1), etfRequests.Load(), "ETFs should be served from cache")
+}
+
+func TestClient_GetAnySecurity(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"leading-equity":         map[string]interface{}{"data": []map[string]interface{}{{"symbol": "GGAL"}}},
+		"cedears":                []map[string]interface{}{{"symbol": "AAPL"}},
+		"general-equity":         map[string]interface{}{"data": []map[string]interface{}{{"symbol": "MOLA"}}},
+		"etf":                    map[string]interface{}{"data": []map[string]interface{}{{"symbol": "SPY"}}},
+		"public-bonds":           map[string]interface{}{"data": []map[string]interface{}{{"symbol": "AL30"}}},
+		"lebacs":                 map[string]interface{}{"data": []map[string]interface{}{{"symbol": "S31O4"}}},
+		"negociable-obligations": []map[string]interface{}{{"symbol": "YCA6O"}},
+		"index-price":            map[string]interface{}{"data": []map[string]interface{}{{"symbol": "M", "price": 1500000.0}}},
+	})
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	tests := []struct {
+		symbol string
+		want   AssetClass
+	}{
+		{"GGAL", AssetClassEquity},
+		{"AAPL", AssetClassCedear},
+		{"MOLA", AssetClassEquity},
+		{"SPY", AssetClassETF},
+		{"AL30", AssetClassBond},
+		{"S31O4", AssetClassShortTermBond},
+		{"ycA6o", AssetClassCorporateBond},
+		{"M", AssetClassIndex},
+	}
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			match, err := client.GetAnySecurity(ctx, tt.symbol)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, match.AssetClass)
+			assert.True(t, strings.EqualFold(tt.symbol, match.Symbol))
+
+			switch tt.want {
+			case AssetClassBond, AssetClassShortTermBond, AssetClassCorporateBond:
+				require.NotNil(t, match.Bond)
+				assert.Nil(t, match.Security)
+			case AssetClassIndex:
+				require.NotNil(t, match.Index)
+				assert.Equal(t, 1500000.0, match.Index.Last)
+			default:
+				require.NotNil(t, match.Security)
+				assert.Nil(t, match.Bond)
+			}
+		})
+	}
+
+	_, err := client.GetAnySecurity(ctx, "UNKNOWN")
+	assert.Error(t, err)
+}
+
+func TestClient_GetSecurityType(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"leading-equity":         map[string]interface{}{"data": []map[string]interface{}{{"symbol": "GGAL"}}},
+		"cedears":                []map[string]interface{}{{"symbol": "AAPL"}},
+		"general-equity":         map[string]interface{}{"data": []map[string]interface{}{{"symbol": "MOLA"}}},
+		"etf":                    map[string]interface{}{"data": []map[string]interface{}{{"symbol": "SPY"}}},
+		"public-bonds":           map[string]interface{}{"data": []map[string]interface{}{{"symbol": "AL30"}}},
+		"lebacs":                 map[string]interface{}{"data": []map[string]interface{}{{"symbol": "S31O4"}}},
+		"negociable-obligations": []map[string]interface{}{{"symbol": "YCA6O"}},
+		"index-price":            map[string]interface{}{"data": []map[string]interface{}{{"symbol": "M"}}},
+		"options":                []map[string]interface{}{{"symbol": "GFGC3000OC"}},
+		"index-future":           []map[string]interface{}{{"symbol": "DLR/OCT25"}},
+	})
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	tests := []struct {
+		symbol string
+		want   AssetClass
+	}{
+		{"GGAL", AssetClassBluechip},
+		{"aapl", AssetClassCedear},
+		{"MOLA", AssetClassGeneralEquity},
+		{"SPY", AssetClassETF},
+		{"AL30", AssetClassBond},
+		{"S31O4", AssetClassShortTermBond},
+		{"YCA6O", AssetClassCorporateBond},
+		{"M", AssetClassIndex},
+		{"GFGC3000OC", AssetClassOption},
+		{"DLR/OCT25", AssetClassFuture},
+		{"UNKNOWN", AssetClassNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			class, err := client.GetSecurityType(ctx, tt.symbol)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, class)
+		})
+	}
+
+	// A collection that cannot be fetched is an error, not "not found"
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not json"))
+	}))
+	defer failing.Close()
+	class, err := createTestClient(failing.URL).GetSecurityType(ctx, "GGAL")
+	assert.Error(t, err)
+	assert.Empty(t, class)
+}
+
+func TestClient_QuoteAge(t *testing.T) {
+	tradedAt := time.Now().Add(-10 * time.Minute).UTC().Format(time.RFC3339)
+	server := newMockServer(map[string]interface{}{
+		"leading-equity": map[string]interface{}{"data": []map[string]interface{}{
+			{"symbol": "GGAL", "tradeHour": tradedAt},
+			{"symbol": "YPFD", "tradeHour": "not a time"},
+			{"symbol": "PAMP"},
+		}},
+		"index-price":  map[string]interface{}{"data": []map[string]interface{}{{"symbol": "M"}}},
+		"index-future": []map[string]interface{}{{"symbol": "DLR/OCT25", "tradeHour": tradedAt}},
+	})
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	for _, symbol := range []string{"GGAL", "DLR/OCT25"} {
+		age, err := client.QuoteAge(ctx, symbol)
+		require.NoError(t, err, symbol)
+		assert.InDelta(t, float64(10*time.Minute), float64(age), float64(time.Minute), symbol)
+	}
+
+	// Unknown trade times are reported rather than passed off as fresh quotes
+	for _, symbol := range []string{"YPFD", "PAMP", "M"} {
+		_, err := client.QuoteAge(ctx, symbol)
+		assert.ErrorIs(t, err, ErrNoTradeTime, symbol)
+	}
+	security, err := client.GetBluechip(ctx, "YPFD")
+	require.NoError(t, err)
+	assert.True(t, security.DateTime.IsZero())
+
+	_, err = client.QuoteAge(ctx, "UNKNOWN")
+	assert.ErrorIs(t, err, ErrInvalidTicker)
+}
+
+func TestClient_LookupInvalidTicker(t *testing.T) {
+	server := newMockServer(map[string]interface{}{})
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	lookups := map[string]func() error{
+		"GetSecurity":    func() error { _, err := client.GetSecurity(ctx, "NOPE"); return err },
+		"GetBluechip":    func() error { _, err := client.GetBluechip(ctx, "NOPE"); return err },
+		"GetCedear":      func() error { _, err := client.GetCedear(ctx, "NOPE"); return err },
+		"GetGalpone":     func() error { _, err := client.GetGalpone(ctx, "NOPE"); return err },
+		"GetEtf":         func() error { _, err := client.GetEtf(ctx, "NOPE"); return err },
+		"GetBond":        func() error { _, err := client.GetBond(ctx, "NOPE"); return err },
+		"GetOption":      func() error { _, err := client.GetOption(ctx, "NOPE"); return err },
+		"GetFuture":      func() error { _, err := client.GetFuture(ctx, "NOPE"); return err },
+		"GetAnySecurity": func() error { _, err := client.GetAnySecurity(ctx, "NOPE"); return err },
+	}
+
+	for name, lookup := range lookups {
+		t.Run(name, func(t *testing.T) {
+			err := lookup()
+			require.Error(t, err)
+
+			bymaErr, ok := err.(*BYMAError)
+			require.True(t, ok, "expected *BYMAError, got %T", err)
+			assert.Equal(t, "INVALID_TICKER", bymaErr.Code)
+			assert.Contains(t, bymaErr.Message, "NOPE")
+			assert.False(t, IsRetryable(err))
+		})
+	}
+}
+
+func TestClient_ConcurrentCacheMisses(t *testing.T) {
+	var cedearRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) == "cedears" {
+			cedearRequests.Add(1)
+			time.Sleep(100 * time.Millisecond) // keep the request in flight while others miss
+			w.Write([]byte(`[{"symbol":"AAPL"}]`))
+		}
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	const callers = 50
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make(chan error, callers)
+	)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			cedears, err := client.GetCedears(ctx)
+			if err == nil && len(cedears) != 1 {
+				err = fmt.Errorf("got %d cedears", len(cedears))
+			}
+			errs <- err
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), cedearRequests.Load())
+}
+
+func TestClient_ConcurrentCacheMissesCallerCancels(t *testing.T) {
+	var cedearRequests atomic.Int32
+	arrived, release := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) == "cedears" {
+			if cedearRequests.Add(1) == 1 {
+				close(arrived)
+			}
+			<-release
+			w.Write([]byte(`[{"symbol":"AAPL"}]`))
+		}
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+
+	// The caller that started the fetch gives up while it is in flight
+	first, cancel := context.WithCancel(context.Background())
+	failed := make(chan error, 1)
+	go func() {
+		_, err := client.GetCedears(first)
+		failed <- err
+	}()
+	<-arrived
+	cancel()
+	assert.ErrorIs(t, <-failed, context.Canceled)
+
+	// A caller joining the same fetch still gets the data
+	done := make(chan struct{})
+	var cedears []Security
+	var err error
+	go func() {
+		defer close(done)
+		cedears, err = client.GetCedears(context.Background())
+	}()
+	time.Sleep(20 * time.Millisecond) // let the second caller join the fetch
+	close(release)
+	<-done
+	require.NoError(t, err)
+	assert.Len(t, cedears, 1)
+	assert.Equal(t, int32(1), cedearRequests.Load())
+
+	// And the data was cached for later calls
+	_, err = client.GetCedears(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), cedearRequests.Load())
+}
+
+func TestClient_GetSecuritiesByBoard(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"leading-equity": map[string]interface{}{
+			"data": []map[string]interface{}{
+				{"symbol": "GGAL", "panel": "General"},
+				{"symbol": "YPFD"},
+			},
+		},
+		"general-equity": map[string]interface{}{
+			"data": []map[string]interface{}{
+				{"symbol": "ROSE", "panel": "PYME"},
+				{"symbol": "MOLA", "panel": "General"},
+			},
+		},
+		"etf": map[string]interface{}{
+			"data": []map[string]interface{}{{"symbol": "SPY", "panel": "General"}},
+		},
+	})
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	ypfd, err := client.GetBluechip(ctx, "YPFD")
+	require.NoError(t, err)
+	assert.Empty(t, ypfd.Panel)
+
+	general, err := client.GetSecuritiesByBoard(ctx, "general")
+	require.NoError(t, err)
+	require.Len(t, general, 3)
+	assert.Equal(t, "GGAL", general[0].Symbol)
+	assert.Equal(t, "General", general[0].Panel)
+	assert.Equal(t, "MOLA", general[1].Symbol)
+	assert.Equal(t, "SPY", general[2].Symbol, "ETFs are listed on boards too")
+
+	sme, err := client.GetSecuritiesByBoard(ctx, "PYME")
+	require.NoError(t, err)
+	require.Len(t, sme, 1)
+	assert.Equal(t, "ROSE", sme[0].Symbol)
+
+	none, err := client.GetSecuritiesByBoard(ctx, "UNKNOWN")
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestClient_GetNews(t *testing.T) {
+	mockResponse := map[string]interface{}{
+		"data": []map[string]interface{}{
+			{
+				"fecha":      "2023-12-01T10:00:00Z",
+				"emisor":     "Test News Title",
+				"referencia": "Test news description",
+				"descarga":   "test-file.pdf",
+			},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(mockResponse)
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	news, err := client.GetNews(ctx)
+
+	require.NoError(t, err)
+	require.Len(t, news, 1)
+
+	newsItem := news[0]
+	assert.Equal(t, "Test News Title", newsItem.Titulo)
+	assert.Equal(t, "Test news description", newsItem.Descripcion)
+	assert.Contains(t, newsItem.Descarga, "test-file.pdf")
+	assert.False(t, newsItem.Fecha.IsZero())
+}
+
+func TestClient_OptionChain(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"options": []map[string]interface{}{
+			{"symbol": "GFGC3000OC", "underlyingSymbol": "GGAL", "maturityDate": "2025-10-17"},
+			{"symbol": "GFGV3000OC", "underlyingSymbol": "GGAL", "maturityDate": "2025-10-17"},
+			{"symbol": "GFGC2800OC", "underlyingSymbol": "GGAL", "maturityDate": "2025-10-17"},
+			{"symbol": "GFGC3000DI", "underlyingSymbol": "GGAL", "maturityDate": "2025-12-19"},
+			{"symbol": "YPFC40000OC", "underlyingSymbol": "YPFD", "maturityDate": "2025-10-17"},
+		},
+	})
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	symbols := func(options []Option) []string {
+		out := make([]string, len(options))
+		for i, option := range options {
+			out[i] = option.Symbol
+		}
+		return out
+	}
+
+	ggal, err := client.GetOptionsForUnderlying(ctx, "ggal")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GFGC3000OC", "GFGV3000OC", "GFGC2800OC", "GFGC3000DI"}, symbols(ggal))
+	byRoot, err := client.GetOptionsForUnderlying(ctx, "GFG")
+	require.NoError(t, err)
+	assert.Equal(t, ggal, byRoot)
+	none, err := client.GetOptionsForUnderlying(ctx, "ALUA")
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	october := FilterOptionsByExpiration(ggal,
+		time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 10, 17, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, []string{"GFGC3000OC", "GFGV3000OC", "GFGC2800OC"}, symbols(october))
+	fromNovember := FilterOptionsByExpiration(ggal, time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+	assert.Equal(t, []string{"GFGC3000DI"}, symbols(fromNovember))
+	assert.Len(t, FilterOptionsByExpiration(ggal, time.Time{}, time.Time{}), 4)
+
+	assert.Equal(t, []string{"GFGV3000OC"}, symbols(FilterOptionsByKind(ggal, OptionPut)))
+	assert.Len(t, FilterOptionsByKind(ggal, "call"), 3)
+
+	chain := BuildOptionChain(october)
+	require.Len(t, chain, 2)
+	assert.Equal(t, 2800.0, chain[0].Strike)
+	assert.Equal(t, []string{"GFGC2800OC"}, symbols(chain[0].Calls))
+	assert.Empty(t, chain[0].Puts)
+	assert.Equal(t, 3000.0, chain[1].Strike)
+	assert.Equal(t, []string{"GFGC3000OC"}, symbols(chain[1].Calls))
+	assert.Equal(t, []string{"GFGV3000OC"}, symbols(chain[1].Puts))
+}
+
+func TestParseOptionSymbol(t *testing.T) {
+	tests := []struct {
+		symbol string
+		kind   string
+		strike float64
+		ok     bool
+	}{
+		{"GFGC3463OC", OptionCall, 3463, true},
+		{"GFGV2870DI", OptionPut, 2870, true},
+		{"GFGC1234.", OptionCall, 1234, true},
+		{"YPFC44000FE", OptionCall, 44000, true},
+		{"PAMV2600AG", OptionPut, 2600, true},
+		{"COMC85.0JU", OptionCall, 85, true},
+		{"ALUC1.10AB", OptionCall, 1.1, true},
+		{"gfgc3000oc", OptionCall, 3000, true},
+		{"AL30", "", 0, false},
+		{"GGAL", "", 0, false},
+		{"GFGX3000OC", "", 0, false},
+		{"GFGCOC", "", 0, false},
+		{"GFGC3000O1", "", 0, false},
+		{"", "", 0, false},
+	}
+	for _, tt := range tests {
+		kind, strike, ok := ParseOptionSymbol(tt.symbol)
+		assert.Equal(t, tt.ok, ok, tt.symbol)
+		assert.Equal(t, tt.kind, kind, tt.symbol)
+		assert.Equal(t, tt.strike, strike, tt.symbol)
+	}
+
+	// GetOptions fills Strike and Kind, leaving them empty for unparseable symbols
+	server := newMockServer(map[string]interface{}{
+		"options": []map[string]interface{}{
+			{"symbol": "GFGV2870DI", "underlyingSymbol": "GGAL"},
+			{"symbol": "ODD-SYMBOL", "underlyingSymbol": "GGAL"},
+		},
+	})
+	defer server.Close()
+
+	options, err := createTestClient(server.URL).GetOptions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, options, 2)
+	assert.Equal(t, OptionPut, options[0].Kind)
+	assert.Equal(t, 2870.0, options[0].Strike)
+	assert.Empty(t, options[1].Kind)
+	assert.Zero(t, options[1].Strike)
+	assert.Empty(t, BuildOptionChain(options[1:]))
+}
+
+func TestClient_GetCedearsPage(t *testing.T) {
+	var requests atomic.Int64
+	mock := newMockServer(map[string]interface{}{
+		"cedears": []map[string]interface{}{
+			{"symbol": "AAPL"}, {"symbol": "MSFT"}, {"symbol": "GOOGL"}, {"symbol": "TSLA"}, {"symbol": "KO"},
+		},
+	})
+	defer mock.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/cedears") {
+			requests.Add(1)
+		}
+		mock.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	symbols := func(page []Security) []string {
+		out := make([]string, len(page))
+		for i, security := range page {
+			out[i] = security.Symbol
+		}
+		return out
+	}
+
+	tests := []struct {
+		offset, limit int
+		want          []string
+	}{
+		{0, 2, []string{"AAPL", "MSFT"}},
+		{2, 2, []string{"GOOGL", "TSLA"}},
+		{4, 2, []string{"KO"}},
+		{5, 2, []string{}},
+		{1, 0, []string{"MSFT", "GOOGL", "TSLA", "KO"}},
+	}
+	for _, tt := range tests {
+		page, total, err := client.GetCedearsPage(ctx, tt.offset, tt.limit)
+		require.NoError(t, err)
+		assert.Equal(t, 5, total)
+		assert.Equal(t, tt.want, symbols(page), "offset=%d limit=%d", tt.offset, tt.limit)
+	}
+	assert.Equal(t, int64(1), requests.Load(), "pages are served from the cached collection")
+
+	// Pages are copies of the cached collection
+	page, _, err := client.GetCedearsPage(ctx, 0, 1)
+	require.NoError(t, err)
+	page[0].Symbol = "CHANGED"
+	page, _, err = client.GetCedearsPage(ctx, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "AAPL", page[0].Symbol)
+
+	_, _, err = client.GetCedearsPage(ctx, -1, 10)
+	assert.ErrorIs(t, err, ErrInvalidPage)
+	_, _, err = client.GetCedearsPage(ctx, 0, -10)
+	assert.ErrorIs(t, err, ErrInvalidPage)
+
+	// Paginate works on any collection
+	bonds := []Bond{{Symbol: "AL30"}, {Symbol: "GD30"}, {Symbol: "AE38"}}
+	bondPage, total, err := Paginate(bonds, 1, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []Bond{{Symbol: "GD30"}, {Symbol: "AE38"}}, bondPage)
+}
+
+func TestClient_GetCedearWithUSD(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"cedears": []map[string]interface{}{
+			{"symbol": "AAPL", "settlementPrice": 15000.0},
+		},
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+
+	t.Run("fixed CCL", func(t *testing.T) {
+		client := NewClient(&ClientOptions{
+			BaseURL:       server.URL,
+			RetryAttempts: 1,
+			Logger:        &NoOpLogger{},
+			CCLSource:     FixedCCL(1200),
+			CedearRatios:  map[string]float64{"AAPL": 20},
+		})
+
+		quote, err := client.GetCedearWithUSD(ctx, "AAPL")
+		require.NoError(t, err)
+		assert.Equal(t, "AAPL", quote.Symbol)
+		assert.Equal(t, 15000.0, quote.LastARS)
+		assert.Equal(t, 1200.0, quote.CCL)
+		assert.InDelta(t, 12.5, quote.LastUSD, 1e-9)
+		assert.True(t, quote.USDAvailable)
+		assert.Equal(t, 20.0, quote.Ratio)
+		assert.InDelta(t, 250.0, quote.UnderlyingUSD, 1e-9)
+	})
+
+	t.Run("unknown ratio", func(t *testing.T) {
+		client := NewClient(&ClientOptions{
+			BaseURL:       server.URL,
+			RetryAttempts: 1,
+			Logger:        &NoOpLogger{},
+			CCLSource:     FixedCCL(1200),
+		})
+
+		quote, err := client.GetCedearWithUSD(ctx, "AAPL")
+		require.NoError(t, err)
+		assert.True(t, quote.USDAvailable)
+		assert.Zero(t, quote.Ratio)
+		assert.Zero(t, quote.UnderlyingUSD)
+	})
+
+	t.Run("missing CCL", func(t *testing.T) {
+		client := createTestClient(server.URL)
+
+		quote, err := client.GetCedearWithUSD(ctx, "AAPL")
+		require.NoError(t, err)
+		assert.Equal(t, 15000.0, quote.LastARS)
+		assert.Zero(t, quote.LastUSD)
+		assert.False(t, quote.USDAvailable)
+	})
+
+	t.Run("invalid CCL", func(t *testing.T) {
+		client := NewClient(&ClientOptions{
+			BaseURL:       server.URL,
+			RetryAttempts: 1,
+			Logger:        &NoOpLogger{},
+			CCLSource:     FixedCCL(0),
+		})
+
+		quote, err := client.GetCedearWithUSD(ctx, "AAPL")
+		require.NoError(t, err)
+		assert.False(t, quote.USDAvailable)
+	})
+}
+
+func TestClient_GetMultipleSecuritiesFields(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"leading-equity": map[string]interface{}{
+			"data": []map[string]interface{}{
+				{
+					"symbol":          "GGAL",
+					"settlementType":  "48hs",
+					"bidPrice":        150.50,
+					"offerPrice":      151.00,
+					"settlementPrice": 150.75,
+					"imbalance":       0.75,
+					"volume":          10000,
+					"numberOfOrders":  50,
+					"tradeHour":       "16:00:00",
+					"securityType":    "EQUITY",
+				},
+			},
+		},
+	})
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	quotes, err := client.GetMultipleSecuritiesFields(ctx, []string{"GGAL"}, FieldLast, FieldVolume)
+	require.NoError(t, err)
+	require.Contains(t, quotes, "GGAL")
+
+	quote := quotes["GGAL"]
+	assert.Equal(t, Security{Symbol: "GGAL", Last: 150.75, Volume: 10000}, *quote)
+
+	// The cached collection must not be affected by the projection
+	full, err := client.GetBluechip(ctx, "GGAL")
+	require.NoError(t, err)
+	assert.Equal(t, 150.50, full.Bid)
+	assert.Equal(t, "EQUITY", full.Group)
+}
+
+func TestClient_SearchSecurities(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("genuine empty result", func(t *testing.T) {
+		server := newMockServer(map[string]interface{}{
+			"cedears": []map[string]interface{}{{"symbol": "AAPL"}},
+		})
+		defer server.Close()
+
+		results, err := createTestClient(server.URL).SearchSecurities(ctx, "ZZZ")
+		require.NoError(t, err)
+		assert.NotNil(t, results)
+		assert.Empty(t, results)
+	})
+
+	t.Run("all fetches failed", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("not json"))
+		}))
+		defer server.Close()
+
+		results, err := createTestClient(server.URL).SearchSecurities(ctx, "AAPL")
+		assert.Error(t, err)
+		assert.Nil(t, results)
+	})
+
+	t.Run("partial failure with matches", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/cedears") {
+				json.NewEncoder(w).Encode([]map[string]interface{}{{"symbol": "AAPL"}})
+				return
+			}
+			w.Write([]byte("not json"))
+		}))
+		defer server.Close()
+
+		results, err := createTestClient(server.URL).SearchSecurities(ctx, "aap")
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "AAPL", results[0].Symbol)
+	})
+
+	t.Run("ETFs", func(t *testing.T) {
+		server := newMockServer(map[string]interface{}{
+			"cedears": []map[string]interface{}{{"symbol": "AAPL"}},
+			"etf":     map[string]interface{}{"data": []map[string]interface{}{{"symbol": "SPY"}}},
+		})
+		defer server.Close()
+
+		results, err := createTestClient(server.URL).SearchSecurities(ctx, "sp")
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "SPY", results[0].Symbol)
+	})
+
+	t.Run("multi-byte symbols", func(t *testing.T) {
+		server := newMockServer(map[string]interface{}{
+			"cedears": []map[string]interface{}{{"symbol": "ÑUBE"}, {"symbol": "ÉCO"}},
+		})
+		defer server.Close()
+		client := createTestClient(server.URL)
+
+		// Lowering each byte left "Ñ" and "É" untouched, so lowercase text never matched
+		results, err := client.SearchSecurities(ctx, "ñub")
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "ÑUBE", results[0].Symbol)
+
+		results, err = client.SearchSecurities(ctx, "éco", SearchOptions{Mode: SearchExact})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "ÉCO", results[0].Symbol)
+	})
+
+	t.Run("modes, fields and limit", func(t *testing.T) {
+		server := newMockServer(map[string]interface{}{
+			"/assets/api/langs/es.json": map[string]string{"AAPL": "Apple Inc.", "MELI": "MercadoLibre"},
+			"leading-equity": map[string]interface{}{"data": []map[string]interface{}{
+				{"symbol": "GGAL"}, {"symbol": "BMA"},
+			}},
+			"cedears": []map[string]interface{}{
+				{"symbol": "AAPL"}, {"symbol": "MELI"}, {"symbol": "MA"}, {"symbol": "AMAT"},
+			},
+		})
+		defer server.Close()
+		client := createTestClient(server.URL)
+
+		symbols := func(opts ...SearchOptions) []string {
+			t.Helper()
+			results, err := client.SearchSecurities(ctx, "ma", opts...)
+			require.NoError(t, err)
+			symbols := []string{}
+			for _, security := range results {
+				symbols = append(symbols, security.Symbol)
+			}
+			return symbols
+		}
+
+		// Exact matches come first, then prefixes, then substrings
+		assert.Equal(t, []string{"MA", "BMA", "AMAT"}, symbols())
+		assert.Equal(t, []string{"MA"}, symbols(SearchOptions{Mode: SearchExact}))
+		assert.Equal(t, []string{"MA"}, symbols(SearchOptions{Mode: SearchPrefix}))
+		assert.Equal(t, []string{"MA", "BMA"}, symbols(SearchOptions{Limit: 2}))
+
+		results, err := client.SearchSecurities(ctx, "apple", SearchOptions{
+			Mode:   SearchPrefix,
+			Fields: []SearchField{SearchBySymbol, SearchByDescription},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "AAPL", results[0].Symbol)
+
+		results, err = client.SearchSecurities(ctx, "mercado", SearchOptions{Fields: []SearchField{SearchBySymbol}})
+		require.NoError(t, err)
+		assert.Empty(t, results)
+
+		_, err = client.SearchSecurities(ctx, "ma", SearchOptions{Mode: "fuzzy"})
+		var bymaErr *BYMAError
+		require.ErrorAs(t, err, &bymaErr)
+		assert.Equal(t, ErrInvalidSearch.Code, bymaErr.Code)
+		_, err = client.SearchSecurities(ctx, "ma", SearchOptions{Fields: []SearchField{"name"}})
+		assert.ErrorAs(t, err, &bymaErr)
+	})
+}
+
+func TestClient_DownloadLatestStatements(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/bnown/seriesHistoricas/balances"):
+			var body struct {
+				Symbol string `json:"symbol"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+
+			statements := []map[string]interface{}{}
+			if body.Symbol == "GGAL" {
+				statements = append(statements,
+					map[string]interface{}{"symbol": "GGAL", "fechaCierre": "2023-06-30", "balancesArchivo": "ggal-2023q2.pdf"},
+					map[string]interface{}{"symbol": "GGAL", "fechaCierre": "2023-12-31", "balancesArchivo": "ggal-2023q4.pdf"},
+				)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": statements})
+		case strings.Contains(r.URL.Path, "/sba/download/"):
+			w.Write([]byte("document:" + path.Base(r.URL.Path)))
+		}
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	dir := t.TempDir()
+
+	paths, err := client.DownloadLatestStatements(context.Background(), []string{"GGAL", "NONE"}, dir)
+
+	var tickerErrs TickerErrors
+	require.ErrorAs(t, err, &tickerErrs)
+	assert.Len(t, tickerErrs, 1)
+	assert.Contains(t, tickerErrs, "NONE")
+
+	require.Contains(t, paths, "GGAL")
+	content, err := os.ReadFile(paths["GGAL"])
+	require.NoError(t, err)
+	assert.Equal(t, "document:ggal-2023q4.pdf", string(content))
+
+	t.Run("single statement", func(t *testing.T) {
+		statements, err := client.GetIncomeStatement(context.Background(), "GGAL")
+		require.NoError(t, err)
+		require.Len(t, statements, 2)
+
+		document, err := client.DownloadIncomeStatement(context.Background(), statements[0].BalancesArchivo)
+		require.NoError(t, err)
+		assert.Equal(t, "document:ggal-2023q2.pdf", string(document))
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		paths, err := client.DownloadLatestStatements(ctx, []string{"GGAL"}, dir)
+		require.ErrorAs(t, err, &tickerErrs)
+		assert.ErrorIs(t, tickerErrs["GGAL"], context.Canceled)
+		assert.Empty(t, paths)
+	})
+}
+
+func TestClient_DownloadAttachment(t *testing.T) {
+	var downloads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/bnown/byma-ads"):
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": []map[string]interface{}{{"emisor": "GGAL", "descarga": "hecho-relevante.pdf"}},
+			})
+		case strings.Contains(r.URL.Path, "/sba/download/"):
+			downloads.Add(1)
+			w.Write([]byte("%PDF-1.7\n"))
+		}
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	news, err := client.GetNews(ctx)
+	require.NoError(t, err)
+	require.Len(t, news, 1)
+
+	data, contentType, err := client.DownloadAttachment(ctx, news[0].Descarga)
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF-1.7\n", string(data))
+	assert.Equal(t, "application/pdf", contentType)
+
+	downloadURL := server.URL + "/vanoms-be-core/rest/api/bymadata/free/sba/download/"
+	for _, documentURL := range []string{
+		"http://169.254.169.254/latest/meta-data/",
+		strings.Replace(downloadURL, "http://", "https://", 1) + "file.pdf",
+		server.URL + "/vanoms-be-core/rest/api/bymadata/free/leading-equity",
+		downloadURL,
+		downloadURL + "../leading-equity",
+		strings.Replace(downloadURL, "http://", "http://user@", 1) + "file.pdf",
+		"::not a url",
+	} {
+		_, _, err := client.DownloadAttachment(ctx, documentURL)
+		assert.ErrorIs(t, err, ErrInvalidDocumentURL, documentURL)
+		_, err = client.DownloadIncomeStatement(ctx, documentURL)
+		assert.ErrorIs(t, err, ErrInvalidDocumentURL, documentURL)
+	}
+	assert.Equal(t, int32(1), downloads.Load(), "rejected URLs are not requested")
+}
+
+func TestClient_GetNewsFor(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/bnown/byma-ads") {
+			return
+		}
+		requests.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []map[string]interface{}{
+				{"emisor": "GGAL", "referencia": "early", "fecha": "2024-03-01T10:00:00Z"},
+				{"emisor": "ggal ", "referencia": "inside", "fecha": "2024-03-10T10:00:00Z"},
+				{"emisor": "YPFD", "referencia": "other issuer", "fecha": "2024-03-10T11:00:00Z"},
+				{"emisor": "GGAL", "referencia": "undated", "fecha": "not a date"},
+				{"emisor": "GGAL", "referencia": "late", "fecha": "2024-03-20T10:00:00Z"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+	from := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	references := func(news []News) []string {
+		refs := []string{}
+		for _, item := range news {
+			refs = append(refs, item.Descripcion)
+		}
+		return refs
+	}
+
+	news, err := client.GetNewsFor(ctx, "GGAL", from, to)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"inside"}, references(news))
+
+	news, err = client.GetNewsFor(ctx, "ggal", from, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"inside", "late"}, references(news))
+
+	news, err = client.GetNewsFor(ctx, "GGAL", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"early", "inside", "undated", "late"}, references(news), "undated news are kept without bounds")
+
+	news, err = client.GetNewsFor(ctx, "", from, to)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"inside", "other issuer"}, references(news))
+
+	_, err = client.GetNewsFor(ctx, "GGAL", to, from)
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+
+	assert.Equal(t, int32(1), requests.Load(), "filters share the cached feed")
+}
+
+func TestClient_ValidateMarketResume(t *testing.T) {
+	tests := []struct {
+		name           string
+		resumeTurnover float64
+		resumeVolume   int64
+		wantConsistent bool
+		wantIssues     int
+	}{
+		{name: "consistent", resumeTurnover: 1520, resumeVolume: 152, wantConsistent: true},
+		{name: "turnover mismatch", resumeTurnover: 5000, resumeVolume: 150, wantIssues: 1},
+		{name: "turnover and volume mismatch", resumeTurnover: 100, resumeVolume: 10, wantIssues: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMockServer(map[string]interface{}{
+				"total-negotiated": map[string]interface{}{
+					"data": []map[string]interface{}{
+						{"assetType": "ACCIONES", "totalNegotiated": tt.resumeTurnover * 0.6, "volume": tt.resumeVolume / 2},
+						{"assetType": "BONOS", "totalNegotiated": tt.resumeTurnover * 0.4, "volume": tt.resumeVolume - tt.resumeVolume/2},
+					},
+				},
+				"leading-equity": map[string]interface{}{
+					"data": []map[string]interface{}{{"symbol": "GGAL", "volumeAmount": 1000.0, "volume": 100}},
+				},
+				"public-bonds": map[string]interface{}{
+					"data": []map[string]interface{}{{"symbol": "AL30", "volumeAmount": 500.0, "volume": 50}},
+				},
+				"etf": map[string]interface{}{
+					"data": []map[string]interface{}{{"symbol": "SPY", "volumeAmount": 20.0, "volume": 2}},
+				},
+			})
+			defer server.Close()
+
+			report, err := createTestClient(server.URL).ValidateMarketResume(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, 1520.0, report.CollectionsTurnover, "ETFs are counted")
+			assert.Equal(t, int64(152), report.CollectionsVolume)
+			assert.Equal(t, tt.wantConsistent, report.Consistent)
+			assert.Len(t, report.Issues, tt.wantIssues)
+		})
+	}
+}
+
+func TestClient_MarketSummaryFor(t *testing.T) {
+	var requests atomic.Int32
+	mock := newMockServer(map[string]interface{}{
+		"total-negotiated": map[string]interface{}{
+			"data": []map[string]interface{}{
+				{"symbol": "ACC", "assetType": "ACCIONES", "totalNegotiated": 1500.5, "volume": 300, "operations": 40},
+				{"symbol": "BON", "assetType": "BONOS", "totalNegotiated": 2500.25, "volume": 700, "operations": 60},
+			},
+		},
+	})
+	defer mock.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/total-negotiated") {
+			requests.Add(1)
+		}
+		mock.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	equities, err := client.GetMarketSummaryFor(ctx, "acciones")
+	require.NoError(t, err)
+	assert.Equal(t, "ACCIONES", equities.AssetType)
+	assert.Equal(t, 1500.5, equities.TotalNegotiated)
+
+	bonds, err := client.GetMarketSummaryFor(ctx, "BON")
+	require.NoError(t, err)
+	assert.Equal(t, "BONOS", bonds.AssetType, "entries also match by symbol")
+
+	_, err = client.GetMarketSummaryFor(ctx, "CEDEARS")
+	assert.ErrorIs(t, err, ErrInvalidTicker)
+
+	turnover, volume, err := client.TotalMarketVolume(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, 4000.75, turnover, 1e-9)
+	assert.Equal(t, int64(1000), volume)
+
+	assert.Equal(t, int32(1), requests.Load(), "lookups and totals share the cached summary")
+}
+
+func TestSecurityJSONRoundTrip(t *testing.T) {
+	buenosAires := time.FixedZone("ART", -3*60*60)
+	original := Security{
+		Symbol:        "GGAL",
+		Settlement:    "24hs",
+		BidSize:       100,
+		Bid:           4500.5,
+		Ask:           4510,
+		AskSize:       200,
+		Last:          4505.25,
+		Close:         4490,
+		Change:        0.34,
+		Open:          4480,
+		High:          4520,
+		Low:           4470,
+		PreviousClose: 4490,
+		Turnover:      123456789.5,
+		Volume:        27400,
+		Operations:    812,
+		DateTime:      time.Date(2024, 3, 15, 17, 0, 0, 0, buenosAires),
+		Group:         "Acciones",
+		Panel:         "General",
+	}
+
+	data, err := json.Marshal(original)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"datetime":"2024-03-15T20:00:00Z"`)
+	assert.Contains(t, string(data), `"previous_close":4490`)
+
+	parsed, err := ParseSecurity(data)
+	require.NoError(t, err)
+
+	// DateTime comes back in the time zone of the BYMA session, like fetched quotes
+	assert.True(t, parsed.DateTime.Equal(original.DateTime))
+	_, offset := parsed.DateTime.Zone()
+	assert.Equal(t, -3*60*60, offset)
+	assert.Equal(t, 17, parsed.DateTime.Hour())
+
+	expected := original
+	expected.DateTime = parsed.DateTime
+	assert.Equal(t, expected, parsed)
+	assert.True(t, SecurityApproxEqual(original, parsed, 0))
+
+	_, err = ParseSecurity(json.RawMessage(`{"symbol": 42}`))
+	assert.Error(t, err)
+}
+
+func TestJSONTimeFormat(t *testing.T) {
+	buenosAires := time.FixedZone("ART", -3*60*60)
+	tradeTime := time.Date(2024, 3, 15, 17, 0, 0, 0, buenosAires)
+	expiration := time.Date(2024, 6, 28, 0, 0, 0, 0, time.UTC)
+	const wantTrade = `"2024-03-15T20:00:00Z"`
+
+	values := map[string]interface{}{
+		"bond":   Bond{Symbol: "AL30", DateTime: tradeTime, Expiration: expiration},
+		"option": Option{Symbol: "GFGC3000OC", DateTime: tradeTime, Expiration: expiration},
+		"future": &Future{Symbol: "DLR/JUN24", DateTime: tradeTime, Expiration: expiration},
+	}
+	for name, value := range values {
+		data, err := json.Marshal(value)
+		require.NoError(t, err, name)
+		assert.Contains(t, string(data), `"datetime":`+wantTrade, name)
+		assert.Contains(t, string(data), `"expiration":"2024-06-28T00:00:00Z"`, name)
+	}
+
+	// History timestamps use the same representation as quotes
+	point := HistoricalData{Time: tradeTime, Close: 4505.25}
+	data, err := json.Marshal(point)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"time":`+wantTrade)
+
+	series := &OHLCV{
+		Time:   []time.Time{tradeTime},
+		Open:   []float64{1},
+		High:   []float64{1},
+		Low:    []float64{1},
+		Close:  []float64{1},
+		Volume: []int64{1},
+	}
+	data, err = json.Marshal(series)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"time":[`+wantTrade+`]`)
+	assert.Equal(t, tradeTime, series.Time[0], "marshalling must not modify the series")
+
+	var restored OHLCV
+	require.NoError(t, json.Unmarshal(data, &restored))
+	assert.True(t, restored.Time[0].Equal(tradeTime))
+	assert.Equal(t, series.Close, restored.Close)
+}
+
+func TestOHLCVWriteCSV(t *testing.T) {
+	data := &OHLCV{
+		Time: []time.Time{
+			time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+		},
+		Open:   []float64{100, 102.5},
+		High:   []float64{105, 104},
+		Low:    []float64{99.25, 101},
+		Close:  []float64{102.5, 103.75},
+		Volume: []int64{1500, 900},
+	}
+
+	var buf strings.Builder
+	require.NoError(t, data.WriteCSV(&buf))
+	assert.Equal(t, "time,open,high,low,close,volume\n"+
+		"2024-03-01T00:00:00Z,100,105,99.25,102.5,1500\n"+
+		"2024-03-04T00:00:00Z,102.5,104,101,103.75,900\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, (&OHLCV{}).WriteCSV(&buf))
+	assert.Equal(t, "time,open,high,low,close,volume\n", buf.String())
+
+	data.Volume = data.Volume[:1]
+	assert.Error(t, data.WriteCSV(io.Discard))
+}
+
+func TestDecimalPrices(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"leading-equity": map[string]interface{}{
+			"data": []map[string]interface{}{
+				{"symbol": "AAA", "settlementPrice": 0.1, "bidPrice": "4505.25", "volumeAmount": 123456789.01},
+				{"symbol": "BBB", "settlementPrice": 0.2},
+			},
+		},
+	})
+	defer server.Close()
+
+	securities, err := createTestClient(server.URL).GetBluechips(context.Background())
+	require.NoError(t, err)
+	require.Len(t, securities, 2)
+
+	assert.NotEqual(t, 0.3, securities[0].Last+securities[1].Last, "floats accumulate rounding errors")
+	total := securities[0].LastDecimal().Add(securities[1].LastDecimal())
+	assert.True(t, total.Equal(decimal.RequireFromString("0.3")), "got %s", total)
+
+	assert.Equal(t, "4505.25", securities[0].BidDecimal().String())
+	assert.Equal(t, "123456789.01", securities[0].TurnoverDecimal().String())
+	assert.True(t, Decimal(math.NaN()).IsZero())
+	assert.Equal(t, "-1.5", Bond{Last: -1.5}.LastDecimal().String())
+}
+
+func TestAdjustForSplits(t *testing.T) {
+	buenosAires := time.FixedZone("ART", -3*60*60)
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, buenosAires) }
+	history := &OHLCV{
+		Time:   []time.Time{day(1), day(2), day(3), day(6)},
+		Open:   []float64{1000, 1010, 505, 250},
+		High:   []float64{1020, 1030, 510, 260},
+		Low:    []float64{990, 1000, 500, 240},
+		Close:  []float64{1010, 1020, 508, 255},
+		Volume: []int64{100, 200, 400, 1000},
+	}
+	actions := []CorporateAction{
+		{Symbol: "GGAL", Type: CorporateActionSplit, ExDate: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC), Ratio: 2},
+		{Symbol: "GGAL", Type: CorporateActionDividend, ExDate: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), Amount: 50},
+		{Symbol: "GGAL", Type: CorporateActionSplit, ExDate: time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), Ratio: 2},
+	}
+
+	adjusted := AdjustForSplits(history, actions)
+	require.NotNil(t, adjusted)
+	assert.Equal(t, []float64{250, 252.5, 252.5, 250}, adjusted.Open)
+	assert.Equal(t, []float64{252.5, 255, 254, 255}, adjusted.Close)
+	assert.Equal(t, []int64{400, 800, 800, 1000}, adjusted.Volume)
+	assert.Equal(t, history.Time, adjusted.Time)
+	assert.Equal(t, 1000.0, history.Open[0], "the input series must not be modified")
+
+	assert.Equal(t, history.Close, AdjustForSplits(history, nil).Close)
+	assert.Nil(t, AdjustForSplits(nil, actions))
+}
+
+func TestClient_TotalReturnHistory(t *testing.T) {
+	buenosAires := time.FixedZone("ART", -3*60*60)
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, buenosAires) }
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) != "history" {
+			return
+		}
+		fmt.Fprintf(w, `{"s":"ok","t":[%d,%d,%d,%d],"o":[100,100,100,100],"h":[100,100,100,100],"l":[100,100,100,100],"c":[100,100,100,200],"v":[10,10,10,10]}`,
+			day(1).Unix(), day(2).Unix(), day(3).Unix(), day(6).Unix())
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+	actions := []CorporateAction{
+		{Symbol: "GGAL", Type: CorporateActionDividend, ExDate: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), Amount: 10},
+		{Symbol: "GGAL", Type: CorporateActionDividend, ExDate: time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC), Amount: 20},
+		{Symbol: "GGAL", Type: CorporateActionSplit, ExDate: time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), Ratio: 2},
+	}
+
+	total, err := client.TotalReturnHistory(ctx, "GGAL", day(1), day(7), actions)
+	require.NoError(t, err)
+
+	// Both dividends are scaled by the later split. The first is reinvested at its
+	// ex-date close (5 on 50, ×1.1); the second goes ex on a weekend and is reinvested
+	// at the next close (10 on 200, ×1.05)
+	require.Len(t, total.Close, 4)
+	assert.InDeltaSlice(t, []float64{50, 55, 55, 231}, total.Close, 1e-9)
+	assert.Equal(t, []int64{20, 20, 20, 10}, total.Volume)
+
+	raw, err := client.GetHistory(ctx, "GGAL", "D", day(1), day(7))
+	require.NoError(t, err)
+	assert.Greater(t, total.Close[3], raw.Close[3], "total return ends above the raw close")
+	assert.Equal(t, []float64{100, 100, 100, 200}, raw.Close, "the cached history is not modified")
+
+	// Without actions it is the unadjusted history
+	total, err = client.TotalReturnHistory(ctx, "GGAL", day(1), day(7), nil)
+	require.NoError(t, err)
+	assert.Equal(t, raw.Close, total.Close)
+}
+
+func TestOHLCVIndicators(t *testing.T) {
+	closes := []float64{1, 2, 3, 4, 5, 4}
+	data := &OHLCV{
+		Time:   make([]time.Time, len(closes)),
+		Open:   closes,
+		High:   closes,
+		Low:    closes,
+		Close:  closes,
+		Volume: make([]int64, len(closes)),
+	}
+
+	sma, err := data.SMA(3)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0, 0, 2, 3, 4, 13.0 / 3}, sma, 1e-9)
+
+	ema, err := data.EMA(3)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0, 0, 2, 3, 4, 4}, ema, 1e-9)
+
+	// Gains of 1 then a loss of 1: avg gain 2/3, avg loss 1/3 on the last point
+	rsi, err := data.RSI(3)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0, 0, 0, 100, 100, 200.0 / 3}, rsi, 1e-9)
+
+	_, err = data.SMA(0)
+	assert.Error(t, err)
+	_, err = data.EMA(7)
+	assert.Error(t, err)
+	_, err = data.RSI(6)
+	assert.Error(t, err, "RSI needs period+1 points")
+}
+
+func TestResample(t *testing.T) {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	// Thu 28 Mar, Fri 29 Mar (holiday Mon 1 Apr), Tue 2 Apr, Wed 3 Apr, then a missing week
+	// and Mon 15 Apr
+	daily := &OHLCV{
+		Time:   []time.Time{day(3, 28), day(3, 29), day(4, 2), day(4, 3), day(4, 15)},
+		Open:   []float64{10, 11, 12, 13, 20},
+		High:   []float64{11, 14, 13, 15, 21},
+		Low:    []float64{9, 10, 11, 12, 19},
+		Close:  []float64{11, 12, 13, 14, 20.5},
+		Volume: []int64{100, 200, 300, 400, 500},
+	}
+
+	weekly, err := Resample(daily, "W")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{day(3, 25), day(4, 1), day(4, 15)}, weekly.Time)
+	assert.Equal(t, []float64{10, 12, 20}, weekly.Open)
+	assert.Equal(t, []float64{14, 15, 21}, weekly.High)
+	assert.Equal(t, []float64{9, 11, 19}, weekly.Low)
+	assert.Equal(t, []float64{12, 14, 20.5}, weekly.Close)
+	assert.Equal(t, []int64{300, 700, 500}, weekly.Volume)
+
+	monthly, err := Resample(daily, "monthly")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{day(3, 1), day(4, 1)}, monthly.Time)
+	assert.Equal(t, []float64{10, 12}, monthly.Open)
+	assert.Equal(t, []float64{14, 21}, monthly.High)
+	assert.Equal(t, []float64{9, 11}, monthly.Low)
+	assert.Equal(t, []float64{12, 20.5}, monthly.Close)
+	assert.Equal(t, []int64{300, 1200}, monthly.Volume)
+
+	empty, err := Resample(&OHLCV{}, "W")
+	require.NoError(t, err)
+	assert.Empty(t, empty.Time)
+
+	_, err = Resample(daily, "5")
+	var bymaErr *BYMAError
+	require.ErrorAs(t, err, &bymaErr)
+	assert.Equal(t, ErrInvalidResolution.Code, bymaErr.Code)
+
+	daily.Time[1], daily.Time[2] = daily.Time[2], daily.Time[1]
+	_, err = Resample(daily, "W")
+	assert.Error(t, err)
+}
+
+func TestNewSlogLogger(t *testing.T) {
+	var buf strings.Builder
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := NewSlogLogger(slog.New(handler))
+
+	logger.Debug("hidden")
+	logger.Warn("Request failed", LogField{Key: "attempt", Value: 2}, LogField{Key: "url", Value: "/index-price"})
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(buf.String()), &entry), "only the warning is logged")
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "Request failed", entry["msg"])
+	assert.Equal(t, 2.0, entry["attempt"])
+	assert.Equal(t, "/index-price", entry["url"])
+
+	assert.NotNil(t, NewSlogLogger(nil))
+}
+
+func TestNumericStrings(t *testing.T) {
+	tests := []struct {
+		value     interface{}
+		wantFloat float64
+		wantInt   int64
+	}{
+		{"1.234,56", 1234.56, 1234},
+		{"1234.56", 1234.56, 1234},
+		{"1,234", 1234, 1234},
+		{"12,5", 12.5, 12},
+		{"1.234.567", 1234567, 1234567},
+		{"1,234,567.89", 1234567.89, 1234567},
+		{" -0,75 ", -0.75, 0},
+		{"42", 42, 42},
+		{"", 0, 0},
+		{"N/A", 0, 0},
+		{1500.5, 1500.5, 1500},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.value), func(t *testing.T) {
+			raw := map[string]interface{}{"v": tt.value}
+			assert.InDelta(t, tt.wantFloat, utils.GetFloat64(raw, "v"), 1e-9)
+			assert.Equal(t, tt.wantInt, utils.GetInt64(raw, "v"))
+		})
+	}
+}
+
+func TestQuoteSpread(t *testing.T) {
+	type quote interface {
+		Spread() (float64, bool)
+		MidPrice() (float64, bool)
+		SpreadPercent() (float64, bool)
+	}
+
+	tests := []struct {
+		name        string
+		quote       quote
+		wantOK      bool
+		wantSpread  float64
+		wantMid     float64
+		wantPercent float64
+	}{
+		{"security", Security{Bid: 99, Ask: 101}, true, 2, 100, 2},
+		{"bond", Bond{Bid: 79.5, Ask: 80.5}, true, 1, 80, 1.25},
+		{"option", Option{Bid: 10, Ask: 10}, true, 0, 10, 0},
+		{"future", Future{Bid: 1190, Ask: 1210}, true, 20, 1200, 20.0 / 1200 * 100},
+		{"no bid", Security{Ask: 101}, false, 0, 0, 0},
+		{"no ask", Bond{Bid: 99}, false, 0, 0, 0},
+		{"no quote", Future{}, false, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spread, ok := tt.quote.Spread()
+			assert.Equal(t, tt.wantOK, ok)
+			assert.InDelta(t, tt.wantSpread, spread, 1e-9)
+
+			mid, ok := tt.quote.MidPrice()
+			assert.Equal(t, tt.wantOK, ok)
+			assert.InDelta(t, tt.wantMid, mid, 1e-9)
+
+			percent, ok := tt.quote.SpreadPercent()
+			assert.Equal(t, tt.wantOK, ok)
+			assert.InDelta(t, tt.wantPercent, percent, 1e-9)
+		})
+	}
+}
+
+func TestSecurity_PositionInDayRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		security Security
+		want     float64
+		wantOK   bool
+	}{
+		{"mid range", Security{Last: 105, Low: 100, High: 120}, 0.25, true},
+		{"at the high", Security{Last: 120, Low: 100, High: 120}, 1, true},
+		{"below a stale low", Security{Last: 95, Low: 100, High: 120}, 0, true},
+		{"no low", Security{Last: 105, High: 120}, 0, false},
+		{"no high", Security{Last: 105, Low: 100}, 0, false},
+		{"no last", Security{Low: 100, High: 120}, 0, false},
+		{"flat range", Security{Last: 100, Low: 100, High: 100}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position, ok := tt.security.PositionInDayRange()
+			assert.Equal(t, tt.wantOK, ok)
+			assert.InDelta(t, tt.want, position, 1e-9)
+		})
+	}
+}
+
+func TestClient_GetFiftyTwoWeekRange(t *testing.T) {
+	var requestedDays atomic.Int64
+	var body atomic.Value
+	body.Store(`{"s":"ok","t":[1700000000,1700086400,1700172800],"o":[1,2,3],"h":[110,0,130],"l":[90,0,95],"c":[1,2,3],"v":[1,2,3]}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) != "history" {
+			return
+		}
+		from, _ := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+		to, _ := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+		requestedDays.Store((to - from) / 86400)
+		w.Write([]byte(body.Load().(string)))
+	}))
+	defer server.Close()
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	low, high, err := client.GetFiftyTwoWeekRange(ctx, "GGAL")
+	require.NoError(t, err)
+	assert.Equal(t, 90.0, low, "missing lows are ignored")
+	assert.Equal(t, 130.0, high)
+	assert.InDelta(t, 7*52, requestedDays.Load(), 1, "52 weeks of daily candles")
+
+	body.Store(`{"s":"ok","t":[1700000000],"o":[1],"h":[0],"l":[0],"c":[1],"v":[1]}`)
+	_, _, err = client.GetFiftyTwoWeekRange(ctx, "YPFD")
+	assert.ErrorIs(t, err, ErrNoHistory)
+}
+
+func TestSecurityApproxEqual(t *testing.T) {
+	base := Security{Symbol: "GGAL", Last: 100, Bid: 99.5, Volume: 1000}
+
+	tests := []struct {
+		name    string
+		modify  func(s *Security)
+		epsilon float64
+		want    bool
+	}{
+		{name: "identical", modify: func(s *Security) {}, epsilon: 0, want: true},
+		{name: "within epsilon", modify: func(s *Security) { s.Last = 100.25 }, epsilon: 0.5, want: true},
+		{name: "at epsilon boundary", modify: func(s *Security) { s.Last = 100.5 }, epsilon: 0.5, want: true},
+		{name: "beyond epsilon", modify: func(s *Security) { s.Last = 100.5001 }, epsilon: 0.5, want: false},
+		{name: "negative difference beyond epsilon", modify: func(s *Security) { s.Bid = 98.9 }, epsilon: 0.5, want: false},
+		{name: "different symbol", modify: func(s *Security) { s.Symbol = "YPF" }, epsilon: 1, want: false},
+		{name: "volume must match exactly", modify: func(s *Security) { s.Volume = 1001 }, epsilon: 10, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := base
+			tt.modify(&other)
+			assert.Equal(t, tt.want, SecurityApproxEqual(base, other, tt.epsilon))
+			assert.Equal(t, tt.want, SecurityApproxEqual(other, base, tt.epsilon))
+		})
+	}
+}
+
+func TestClient_CloseWithTimeout(t *testing.T) {
+	newSlowServer := func(delay time.Duration, started chan<- struct{}) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/market-time") {
+				// Consume the body so the server notices when the client cancels
+				io.Copy(io.Discard, r.Body)
+				started <- struct{}{}
+				select {
+				case <-time.After(delay):
+				case <-r.Context().Done():
+				}
+			}
+			w.Write([]byte(`{"isWorkingDay": true}`))
+		}))
+	}
+
+	t.Run("drain allows in-flight request to complete", func(t *testing.T) {
+		started := make(chan struct{}, 1)
+		server := newSlowServer(200*time.Millisecond, started)
+		defer server.Close()
+
+		client := createTestClient(server.URL)
+		result := make(chan error, 1)
+		go func() {
+			_, err := client.IsWorkingDay(context.Background())
+			result <- err
+		}()
+
+		<-started
+		require.NoError(t, client.CloseWithTimeout(2*time.Second))
+		assert.NoError(t, <-result)
+
+		_, err := client.IsWorkingDay(context.Background())
+		assert.ErrorIs(t, err, ErrClientClosed)
+	})
+
+	t.Run("drain timeout cancels in-flight request", func(t *testing.T) {
+		started := make(chan struct{}, 1)
+		server := newSlowServer(2*time.Second, started)
+		defer server.Close()
+
+		client := createTestClient(server.URL)
+		result := make(chan error, 1)
+		go func() {
+			_, err := client.IsWorkingDay(context.Background())
+			result <- err
+		}()
+
+		<-started
+		start := time.Now()
+		assert.Error(t, client.CloseWithTimeout(50*time.Millisecond))
+		assert.Error(t, <-result)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+func TestClient_CloseReleasesResources(t *testing.T) {
+	mock := newMockServer(map[string]interface{}{
+		"leading-equity": map[string]interface{}{"data": []map[string]interface{}{{"symbol": "GGAL", "last": 4500}}},
+		"index-price":    map[string]interface{}{"data": []map[string]interface{}{{"symbol": "M", "price": 1000}}},
+	})
+	defer mock.Close()
+	var closedConns atomic.Int64
+	server := httptest.NewUnstartedServer(mock.Config.Handler)
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			closedConns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	// Long intervals: only Close can end these before the test does
+	updates, err := client.SubscribeSecurities(ctx, []string{"GGAL"}, time.Hour)
+	require.NoError(t, err)
+	<-updates
+	indices, err := client.StreamIndices(ctx)
+	require.NoError(t, err)
+	<-indices
+
+	require.NoError(t, client.Close())
+
+	for name, closed := range map[string]func() bool{
+		"subscription": func() bool { _, ok := <-updates; return !ok },
+		"stream":       func() bool { _, ok := <-indices; return !ok },
+	} {
+		done := make(chan bool, 1)
+		go func() { done <- closed() }()
+		select {
+		case ok := <-done:
+			assert.True(t, ok, name)
+		case <-time.After(time.Second):
+			t.Fatalf("%s channel was not closed by Close", name)
+		}
+	}
+
+	assert.Eventually(t, func() bool { return closedConns.Load() > 0 },
+		time.Second, 10*time.Millisecond, "idle connections should be closed")
+
+	_, err = client.GetBonds(ctx)
+	assert.ErrorIs(t, err, ErrClientClosed)
+}
+
+func TestClient_ContextDeadlineOverridesTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(150 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte(`{"isWorkingDay":true}`))
+	}))
+	defer server.Close()
+
+	newClient := func(timeout time.Duration) Client {
+		return NewClient(&ClientOptions{
+			BaseURL:        server.URL,
+			Timeout:        timeout,
+			RetryAttempts:  1,
+			RetryBaseDelay: time.Millisecond,
+			Logger:         &NoOpLogger{},
+		})
+	}
+
+	// Without a deadline the client timeout applies to each attempt
+	_, err := newClient(50 * time.Millisecond).IsWorkingDay(context.Background())
+	var bymaErr *BYMAError
+	require.ErrorAs(t, err, &bymaErr)
+	assert.Equal(t, ErrTimeout.Code, bymaErr.Code)
+
+	// A longer context deadline wins over the shorter client timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	working, err := newClient(50 * time.Millisecond).IsWorkingDay(ctx)
+	require.NoError(t, err)
+	assert.True(t, working)
+
+	// And a shorter one cuts a call below the client timeout
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = newClient(5 * time.Second).IsWorkingDay(ctx)
+	require.ErrorAs(t, err, &bymaErr)
+	assert.Equal(t, ErrTimeout.Code, bymaErr.Code)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestClient_ContextDeadline(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "deadline during in-flight request",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(io.Discard, r.Body)
+				select {
+				case <-time.After(5 * time.Second):
+				case <-r.Context().Done():
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/market-time") {
+					tt.handler(w, r)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(&ClientOptions{
+				BaseURL:       server.URL,
+				RetryAttempts: 3,
+				Logger:        &NoOpLogger{},
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			_, err := client.IsWorkingDay(ctx)
+			elapsed := time.Since(start)
+
+			var bymaErr *BYMAError
+			require.ErrorAs(t, err, &bymaErr)
+			assert.Equal(t, "TIMEOUT", bymaErr.Code)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.Less(t, elapsed, time.Second)
+		})
+	}
+}
+
+func TestClient_BackoffExceedsDeadline(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/market-time") {
+			requests.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{
+		BaseURL:       server.URL,
+		RetryAttempts: 3,
+		Logger:        &NoOpLogger{},
+	})
+
+	// The first backoff is at least RetryBaseDelay (1s), well beyond the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.IsWorkingDay(ctx)
+	elapsed := time.Since(start)
+
+	var bymaErr *BYMAError
+	require.ErrorAs(t, err, &bymaErr)
+	assert.Equal(t, http.StatusInternalServerError, bymaErr.StatusCode)
+	assert.NotErrorIs(t, err, context.DeadlineExceeded)
+	assert.NoError(t, ctx.Err(), "should return before the deadline")
+	assert.Less(t, elapsed, 250*time.Millisecond)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestClient_RetryBackoff(t *testing.T) {
+	const (
+		baseDelay = 20 * time.Millisecond
+		maxDelay  = 50 * time.Millisecond
+		slack     = 30 * time.Millisecond
+	)
+
+	var (
+		mu       sync.Mutex
+		attempts []time.Time
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/market-time") {
+			mu.Lock()
+			attempts = append(attempts, time.Now())
+			mu.Unlock()
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{
+		BaseURL:        server.URL,
+		RetryAttempts:  4,
+		RetryBaseDelay: baseDelay,
+		RetryMaxDelay:  maxDelay,
+		Logger:         &NoOpLogger{},
+	})
+
+	_, err := client.IsWorkingDay(context.Background())
+	require.Error(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, attempts, 5, "RetryAttempts retries after the first attempt")
+
+	// The ceiling doubles each retry (40ms, then capped at 50ms)
+	ceilings := []time.Duration{2 * baseDelay, maxDelay, maxDelay, maxDelay}
+	for i, ceiling := range ceilings {
+		wait := attempts[i+1].Sub(attempts[i])
+		assert.GreaterOrEqual(t, wait, baseDelay, "retry %d waited less than the base delay", i+1)
+		assert.LessOrEqual(t, wait, ceiling+slack, "retry %d exceeded its cap", i+1)
+	}
+}
+
+func TestClient_RetryAfter(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/market-time") {
+			return
+		}
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"isWorkingDay": true}`)
+	}))
+	defer server.Close()
+
+	var delays []time.Duration
+	client := NewClient(&ClientOptions{
+		BaseURL:        server.URL,
+		RetryAttempts:  1,
+		RetryBaseDelay: time.Millisecond,
+		RetryMaxDelay:  time.Millisecond,
+		Logger:         &NoOpLogger{},
+		OnRetry: func(attempt int, err error, nextDelay time.Duration) {
+			delays = append(delays, nextDelay)
+		},
+	})
+
+	t.Run("waits as asked", func(t *testing.T) {
+		start := time.Now()
+		_, err := client.IsWorkingDay(context.Background())
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), time.Second)
+		assert.Equal(t, []time.Duration{time.Second}, delays)
+	})
+
+	t.Run("gives up when the wait exceeds the deadline", func(t *testing.T) {
+		attempts.Store(0)
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := client.IsWorkingDay(ctx)
+		var bymaErr *BYMAError
+		require.ErrorAs(t, err, &bymaErr)
+		assert.Equal(t, ErrRateLimited.Code, bymaErr.Code)
+		assert.Equal(t, time.Second, bymaErr.RetryAfter)
+		assert.Less(t, time.Since(start), 200*time.Millisecond, "should fail without waiting")
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+}
+
+func TestClient_RetryableErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		wantCode     string
+		wantRequests int32
+	}{
+		{"not found is not retried", http.StatusNotFound, "HTTP_ERROR", 1},
+		{"bad request is not retried", http.StatusBadRequest, "HTTP_ERROR", 1},
+		{"unauthorized is not retried", http.StatusUnauthorized, "UNAUTHORIZED", 1},
+		{"rate limited is retried", http.StatusTooManyRequests, "RATE_LIMITED", 4},
+		{"server error is retried", http.StatusServiceUnavailable, "API_UNAVAILABLE", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/market-time") {
+					requests.Add(1)
+					w.WriteHeader(tt.status)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(&ClientOptions{
+				BaseURL:        server.URL,
+				RetryAttempts:  3,
+				RetryBaseDelay: time.Millisecond,
+				RetryMaxDelay:  time.Millisecond,
+				Logger:         &NoOpLogger{},
+			})
+
+			_, err := client.IsWorkingDay(context.Background())
+
+			var bymaErr *BYMAError
+			require.ErrorAs(t, err, &bymaErr)
+			assert.Equal(t, tt.wantCode, bymaErr.Code)
+			assert.Equal(t, tt.status, bymaErr.StatusCode)
+			assert.Equal(t, tt.wantRequests, requests.Load())
+			assert.Contains(t, err.Error(), fmt.Sprintf("after %d attempts", tt.wantRequests),
+				"the error reports the attempts actually made")
+		})
+	}
+
+	t.Run("network errors are retried", func(t *testing.T) {
+		transport := &countingTransport{}
+		client := NewClient(&ClientOptions{
+			BaseURL:        "http://127.0.0.1:1",
+			RetryAttempts:  2,
+			RetryBaseDelay: time.Millisecond,
+			RetryMaxDelay:  time.Millisecond,
+			Logger:         &NoOpLogger{},
+			HTTPClient:     &http.Client{Transport: transport},
+		})
+
+		before := atomic.LoadInt32(&transport.count)
+		_, err := client.IsWorkingDay(context.Background())
+		require.Error(t, err)
+		assert.Equal(t, before+3, atomic.LoadInt32(&transport.count))
+	})
+}
+
+func TestBYMAError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       *BYMAError
+		wantStr   string
+		retryable bool
+	}{
+		{
+			name:      "simple error",
+			err:       ErrInvalidResponse,
+			wantStr:   "INVALID_RESPONSE: Invalid API response",
+			retryable: false,
+		},
+		{
+			name:      "error with underlying",
+			err:       ErrAPIUnavailable.WithUnderlying(assert.AnError),
+			retryable: true,
+		},
+		{
+			name:      "error with status code",
+			err:       ErrTimeout.WithStatusCode(408),
+			retryable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantStr != "" {
+				assert.Equal(t, tt.wantStr, tt.err.Error())
+			}
+			assert.Equal(t, tt.retryable, IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestMapHTTPError(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		wantCode   string
+	}{
+		{http.StatusUnauthorized, "UNAUTHORIZED"},
+		{http.StatusTooManyRequests, "RATE_LIMITED"},
+		{http.StatusInternalServerError, "API_UNAVAILABLE"},
+		{http.StatusRequestTimeout, "TIMEOUT"},
+		{http.StatusNotFound, "HTTP_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			err := MapHTTPError(tt.statusCode)
+			assert.Equal(t, tt.wantCode, err.Code)
+			assert.Equal(t, tt.statusCode, err.StatusCode)
+		})
+	}
+}
+
+// newMockServer creates a test server that serves the given responses keyed by endpoint
+// (e.g. "cedears"). Other endpoints get an empty collection in the shape BYMA uses for them.
+func newMockServer(responses map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		endpoint := strings.TrimPrefix(r.URL.Path, "/vanoms-be-core/rest/api/bymadata/free/")
+		if response, ok := responses[endpoint]; ok {
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		switch endpoint {
+		case "cedears", "negociable-obligations", "options":
+			// These endpoints return data directly (not wrapped)
+			json.NewEncoder(w).Encode([]interface{}{})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": []interface{}{}})
+		}
+	}))
+}
+
+// createTestClient creates a client configured for testing with a test server
+func createTestClient(baseURL string) Client {
+	opts := &ClientOptions{
+		BaseURL:       baseURL,
+		Timeout:       5 * time.Second,
+		RetryAttempts: 1,
+		Logger:        &NoOpLogger{},
+	}
+	return NewClient(opts)
+}
+
+func TestClient_RetryStats(t *testing.T) {
+	var failuresLeft atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" && path.Base(r.URL.Path) != "es.json" && failuresLeft.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"isWorkingDay":true}`))
+	}))
+	defer server.Close()
+
+	type retry struct {
+		attempt int
+		err     error
+		delay   time.Duration
+	}
+	var mu sync.Mutex
+	var retries []retry
+	client := NewClient(&ClientOptions{
+		BaseURL:        server.URL,
+		RetryAttempts:  3,
+		RetryBaseDelay: time.Millisecond,
+		RetryMaxDelay:  time.Millisecond,
+		Logger:         &NoOpLogger{},
+		OnRetry: func(attempt int, err error, nextDelay time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			retries = append(retries, retry{attempt, err, nextDelay})
+		},
+	})
+	defer client.Close()
+	client.ResetStats()
+
+	// Two failed attempts are retried before the call succeeds
+	failuresLeft.Store(2)
+	working, err := client.IsWorkingDay(context.Background())
+	require.NoError(t, err)
+	assert.True(t, working)
+	assert.Equal(t, RetryStats{Requests: 1, Retries: 2}, client.RetryStats())
+	require.Len(t, retries, 2)
+	for i, r := range retries {
+		assert.Equal(t, i+1, r.attempt)
+		assert.Error(t, r.err)
+		assert.Equal(t, time.Millisecond, r.delay)
+	}
+
+	// A call that keeps failing uses up its retries and counts as a failure
+	failuresLeft.Store(10)
+	_, err = client.IsWorkingDay(context.Background())
+	require.Error(t, err)
+	stats := client.RetryStats()
+	assert.Equal(t, RetryStats{Requests: 2, Retries: 5, Failures: 1}, stats)
+	assert.InDelta(t, 2.5, stats.RetryRate(), 1e-9)
+
+	client.ResetStats()
+	assert.Equal(t, RetryStats{}, client.RetryStats())
+}
+
+func TestClient_RequestsPerSecond(t *testing.T) {
+	server := newMockServer(map[string]interface{}{})
+	defer server.Close()
+	ctx := context.Background()
+
+	newLimitedClient := func(rps float64) Client {
+		return NewClient(&ClientOptions{
+			BaseURL:           server.URL,
+			Timeout:           5 * time.Second,
+			RetryAttempts:     1,
+			Logger:            &NoOpLogger{},
+			RequestsPerSecond: rps,
+		})
+	}
+	fetchAll := func(client Client) time.Duration {
+		start := time.Now()
+		_, err := client.GetBluechips(ctx)
+		require.NoError(t, err)
+		_, err = client.GetCedears(ctx)
+		require.NoError(t, err)
+		_, err = client.GetGalpones(ctx)
+		require.NoError(t, err)
+		_, err = client.GetEtfs(ctx)
+		require.NoError(t, err)
+		return time.Since(start)
+	}
+
+	// Four requests at 20 per second are spaced by 50ms each
+	assert.GreaterOrEqual(t, fetchAll(newLimitedClient(20)), 150*time.Millisecond)
+	assert.Less(t, fetchAll(createTestClient(server.URL)), 100*time.Millisecond, "no limit by default")
+
+	// Waiting for a turn respects the caller's deadline
+	slow := newLimitedClient(2)
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := slow.GetBluechips(ctx)
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestClient_CustomHeaders(t *testing.T) {
+	var mu sync.Mutex
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = r.Header.Clone()
+		mu.Unlock()
+		w.Write([]byte(`{"isWorkingDay":true}`))
+	}))
+	defer server.Close()
+
+	lastHeaders := func(opts *ClientOptions) http.Header {
+		opts.BaseURL = server.URL
+		opts.Logger = &NoOpLogger{}
+		client := NewClient(opts)
+		defer client.Close()
+		_, err := client.IsWorkingDay(context.Background())
+		require.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+
+	// Defaults are kept when nothing is configured
+	headers := lastHeaders(&ClientOptions{})
+	assert.Contains(t, headers.Get("User-Agent"), "Chrome/96")
+	assert.Equal(t, "application/json, text/plain, */*", headers.Get("Accept"))
+
+	headers = lastHeaders(&ClientOptions{
+		UserAgent: "openbymadata-test/1.0",
+		ExtraHeaders: map[string]string{
+			"X-Api-Key":  "secret",
+			"accept":     "application/json",
+			"User-Agent": "ignored",
+		},
+	})
+	assert.Equal(t, "openbymadata-test/1.0", headers.Get("User-Agent"))
+	assert.Equal(t, "secret", headers.Get("X-Api-Key"))
+	assert.Equal(t, []string{"application/json"}, headers.Values("Accept"))
+	assert.Equal(t, "cors", headers.Get("Sec-Fetch-Mode"))
+}
+
+func TestClient_SetHeader(t *testing.T) {
+	var mu sync.Mutex
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = r.Header.Clone()
+		mu.Unlock()
+		w.Write([]byte(`{"isWorkingDay":true}`))
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	defer client.Close()
+	ctx := context.Background()
+	lastHeaders := func() http.Header {
+		_, err := client.IsWorkingDay(ctx)
+		require.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+
+	client.SetHeader("Authorization", "Bearer first")
+	assert.Equal(t, "Bearer first", lastHeaders().Get("Authorization"))
+
+	// Names are matched regardless of case, so a rotated token replaces the old one
+	client.SetHeader("authorization", "Bearer second")
+	assert.Equal(t, []string{"Bearer second"}, lastHeaders().Values("Authorization"))
+
+	client.RemoveHeader("AUTHORIZATION")
+	client.RemoveHeader("sec-fetch-mode")
+	headers := lastHeaders()
+	assert.Empty(t, headers.Values("Authorization"))
+	assert.Empty(t, headers.Values("Sec-Fetch-Mode"))
+	assert.Equal(t, "application/json, text/plain, */*", headers.Get("Accept"))
+
+	// Updates are safe while requests are running
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			client.SetHeader("X-Request", strconv.Itoa(i))
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := client.IsWorkingDay(ctx)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestClient_IndexedLookups(t *testing.T) {
+	var mu sync.Mutex
+	bonds := []map[string]interface{}{{"symbol": "AL30", "closingPrice": 100.0}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch path.Base(r.URL.Path) {
+		case "public-bonds":
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": bonds})
+		case "cedears":
+			json.NewEncoder(w).Encode([]map[string]interface{}{{"symbol": "AAPL"}, {"symbol": "MSFT"}})
+		case "negociable-obligations":
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ctx := context.Background()
+
+	bond, err := client.GetBond(ctx, "al30")
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, bond.Close)
+	_, err = client.GetBond(ctx, "GD30")
+	assert.Error(t, err)
+
+	security, err := client.GetSecurity(ctx, " msft")
+	require.NoError(t, err)
+	assert.Equal(t, "MSFT", security.Symbol)
+	cedear, err := client.GetCedear(ctx, "AAPL")
+	require.NoError(t, err)
+	assert.Equal(t, "AAPL", cedear.Symbol)
+
+	// A refreshed collection is looked up through a new index, never the old one
+	mu.Lock()
+	bonds = []map[string]interface{}{{"symbol": "GD30", "closingPrice": 70.0}, {"symbol": "AL30", "closingPrice": 101.0}}
+	mu.Unlock()
+	client.ClearCache()
+
+	bond, err = client.GetBond(ctx, "AL30")
+	require.NoError(t, err)
+	assert.Equal(t, 101.0, bond.Close)
+	bond, err = client.GetBond(ctx, "gd30")
+	require.NoError(t, err)
+	assert.Equal(t, "GD30", bond.Symbol)
+
+	// Without a cache, lookups scan the fetched collections
+	uncached := NewClient(&ClientOptions{BaseURL: server.URL, RetryAttempts: 1, Logger: &NoOpLogger{}, EnableCache: false})
+	defer uncached.Close()
+	bond, err = uncached.GetBond(ctx, "AL30")
+	require.NoError(t, err)
+	assert.Equal(t, 101.0, bond.Close)
+}
+
+func BenchmarkSecurityLookup(b *testing.B) {
+	securities := make([]Security, 500)
+	for i := range securities {
+		securities[i].Symbol = fmt.Sprintf("SYM%03d", i)
+	}
+	index := helpers.IndexSecurities(securities)
+	// Symbols spread over the collection, including one that is not listed
+	symbols := []string{"SYM000", "SYM125", "SYM250", "SYM375", "SYM499", "MISSING"}
+
+	b.Run("linear", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			helpers.FindSecurityInCollection(symbols[i%len(symbols)], securities)
+		}
+	})
+
+	b.Run("indexed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			helpers.FindSecurityIndexed(symbols[i%len(symbols)], securities, index)
+		}
+	})
+}
+
+func TestClient_DebugAndLastRawResponse(t *testing.T) {
+	t.Setenv("DEBUG", "false")
+	body := `[{"symbol":"AAPL","trade":0}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) == "cedears" {
+			w.Write([]byte(body))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	newClient := func(debug bool) (Client, *strings.Builder) {
+		var buf strings.Builder
+		logger := NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		return NewClient(&ClientOptions{BaseURL: server.URL, RetryAttempts: 1, Logger: logger, Debug: debug}), &buf
+	}
+
+	// Raw responses are recorded even without debug mode, which only controls logging
+	client, logs := newClient(false)
+	defer client.Close()
+	assert.Nil(t, client.LastRawResponse("cedears"))
+	_, err := client.GetCedears(context.Background())
+	require.NoError(t, err)
+	raw := client.LastRawResponse("cedears")
+	assert.Equal(t, body, string(raw))
+	assert.NotContains(t, logs.String(), "Raw API Response")
+
+	raw[0] = 'x'
+	assert.Equal(t, body, string(client.LastRawResponse("cedears")), "callers get a copy")
+	assert.Nil(t, client.LastRawResponse("public-bonds"))
+
+	debugClient, logs := newClient(true)
+	defer debugClient.Close()
+	_, err = debugClient.GetCedears(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, logs.String(), `"msg":"Raw API Response","endpoint":"cedears"`)
+}
+
+func TestBYMAErrorIs(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		is   *BYMAError
+	}{
+		{"with status code", ErrRateLimited.WithStatusCode(http.StatusTooManyRequests), ErrRateLimited},
+		{"with underlying", ErrTimeout.WithUnderlying(context.DeadlineExceeded), ErrTimeout},
+		{"new error with the same code", NewBYMAError(ErrInvalidTicker.Code, "security XXXX not found"), ErrInvalidTicker},
+		{"wrapped", fmt.Errorf("request failed after 3 attempts: %w", ErrAPIUnavailable.WithStatusCode(http.StatusServiceUnavailable)), ErrAPIUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.err, tt.is)
+			assert.NotErrorIs(t, tt.err, ErrUnauthorized)
+		})
+	}
+
+	// The underlying error is still reachable
+	assert.ErrorIs(t, ErrTimeout.WithUnderlying(context.DeadlineExceeded), context.DeadlineExceeded)
+	assert.NotErrorIs(t, ErrTimeout, (*BYMAError)(nil))
+
+	// Errors returned by the client match the sentinels
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) == "cedears" {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL, RetryAttempts: 1, RetryBaseDelay: time.Millisecond, Logger: &NoOpLogger{}})
+	defer client.Close()
+	_, err := client.GetCedears(context.Background())
+	assert.ErrorIs(t, err, ErrRateLimited)
+}
+
+func TestClient_FuturesMultiplier(t *testing.T) {
+	server := newMockServer(map[string]interface{}{
+		"index-future": []map[string]interface{}{
+			{
+				"symbol": "DLR/OCT25", "settlementPrice": 1.45, "volume": 120, "numberOfOrders": 7,
+				"bidPrice": 1.44, "offerPrice": 1.46, "closingPrice": 1.43, "openingPrice": 1.42,
+				"tradingHighPrice": 1.47, "tradingLowPrice": 1.41, "previousClosingPrice": 1.4,
+				"volumeAmount": 174, "openInterest": 900,
+			},
+			{"symbol": "ORO/DIC25", "settlementPrice": 2.5, "volume": 3},
+		},
+	})
+	defer server.Close()
+	ctx := context.Background()
+
+	// Prices use the default multiplier, volume and operations are never scaled
+	futures, err := createTestClient(server.URL).GetFutures(ctx)
+	require.NoError(t, err)
+	require.Len(t, futures, 2)
+	dollar := futures[0]
+	assert.InDelta(t, 1450.0, dollar.Last, 1e-9)
+	assert.InDelta(t, 1440.0, dollar.Bid, 1e-9)
+	assert.InDelta(t, 1460.0, dollar.Ask, 1e-9)
+	assert.InDelta(t, 1430.0, dollar.Close, 1e-9)
+	assert.InDelta(t, 1420.0, dollar.Open, 1e-9)
+	assert.InDelta(t, 1470.0, dollar.High, 1e-9)
+	assert.InDelta(t, 1410.0, dollar.Low, 1e-9)
+	assert.InDelta(t, 1400.0, dollar.PreviousClose, 1e-9)
+	assert.InDelta(t, 174000.0, dollar.Turnover, 1e-9)
+	assert.Equal(t, int64(120), dollar.Volume)
+	assert.Equal(t, int64(7), dollar.Operations)
+	assert.Equal(t, int64(900), dollar.OpenInterest)
+	assert.Equal(t, DefaultFuturesMultiplier, dollar.Multiplier)
+	assert.InDelta(t, 1.45, dollar.QuotedPrice(dollar.Last), 1e-9)
+
+	// A multiplier configured for an underlying only applies to its contracts
+	client := NewClient(&ClientOptions{
+		BaseURL:            server.URL,
+		RetryAttempts:      1,
+		Logger:             &NoOpLogger{},
+		FuturesMultipliers: map[string]float64{"dlr": 1},
+	})
+	defer client.Close()
+	futures, err = client.GetFutures(ctx)
+	require.NoError(t, err)
+	require.Len(t, futures, 2)
+	assert.InDelta(t, 1.45, futures[0].Last, 1e-9)
+	assert.Equal(t, 1.0, futures[0].Multiplier)
+	assert.InDelta(t, 2500.0, futures[1].Last, 1e-9)
+	assert.Equal(t, int64(3), futures[1].Volume)
 }
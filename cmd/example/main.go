@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/carvalab/openbymadata"
+	"github.com/shopspring/decimal"
 )
 
 func main() {
@@ -124,7 +125,7 @@ func main() {
 		fmt.Printf("💼 Portfolio (%d/%d securities) [%v]:\n",
 			len(securities), len(watchlist), duration)
 
-		totalValue := 0.0
+		totalValue := decimal.Zero // Sum exact decimals, not floats
 		for _, symbol := range watchlist {
 			if security, found := securities[symbol]; found {
 				changeIcon := "🟢"
@@ -133,12 +134,12 @@ func main() {
 				}
 				fmt.Printf("   %s %-6s: $%-10.2f %+6.2f%%\n",
 					changeIcon, symbol, security.Last, security.Change)
-				totalValue += security.Last
+				totalValue = totalValue.Add(security.LastDecimal())
 			} else {
 				fmt.Printf("   ❌ %-6s: Not found\n", symbol)
 			}
 		}
-		fmt.Printf("   💰 Total Portfolio Value: $%.2f\n", totalValue)
+		fmt.Printf("   💰 Total Portfolio Value: $%s\n", totalValue.StringFixed(2))
 	}
 
 	// =============================================================================
@@ -3,6 +3,7 @@ package openbymadata_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -316,9 +317,8 @@ func _ExampleClient_caching() {
 	// Second call was cached: true
 }
 
-// TODO: Fix error handling example
 // ExampleBYMAError demonstrates error handling.
-func _ExampleBYMAError() {
+func ExampleBYMAError() {
 	// Create a test server that returns an error
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusTooManyRequests)
@@ -327,21 +327,24 @@ func _ExampleBYMAError() {
 	defer server.Close()
 
 	client := openbymadata.NewClient(&openbymadata.ClientOptions{
-		BaseURL: server.URL,
+		BaseURL:        server.URL,
+		RetryAttempts:  1,
+		RetryBaseDelay: 10 * time.Millisecond,
 	})
 
 	ctx := context.Background()
 	_, err := client.GetCedear(ctx, "AAPL")
 
-	if err != nil {
-		if bymaErr, ok := err.(*openbymadata.BYMAError); ok {
-			fmt.Printf("Error Code: %s\n", bymaErr.Code)
-			fmt.Printf("Is Retryable: %v\n", openbymadata.IsRetryable(err))
-		}
+	var bymaErr *openbymadata.BYMAError
+	if errors.As(err, &bymaErr) {
+		fmt.Printf("Error Code: %s\n", bymaErr.Code)
+		fmt.Printf("Status Code: %d\n", bymaErr.StatusCode)
+		fmt.Printf("Is Retryable: %v\n", openbymadata.IsRetryable(err))
 	}
 
 	// Output:
 	// Error Code: RATE_LIMITED
+	// Status Code: 429
 	// Is Retryable: true
 }
 
@@ -25,11 +25,13 @@ func (c *Client) GetCorporateBonds(ctx context.Context) ([]Bond, error) {
 
 // getFixedIncome is a helper function to retrieve bonds from different endpoints
 func (c *Client) getFixedIncome(ctx context.Context, endpoint string) ([]Bond, error) {
-	// Use standard payload for fixed income data
-	data := []byte(`{"excludeZeroPxAndQty":false,"T2":false,"T1":true,"T0":false,"Content-Type":"application/json"}`)
+	data, err := quotePayload(ctx)
+	if err != nil {
+		return nil, err
+	}
 	url := c.buildURL(endpoint)
 
-	respData, err := c.post(url, data)
+	respData, err := c.post(ctx, url, data)
 	if err != nil {
 		return nil, err
 	}
@@ -50,30 +52,39 @@ func (c *Client) getFixedIncome(ctx context.Context, endpoint string) ([]Bond, e
 	}
 
 	bonds := make([]Bond, 0, len(rawBonds))
-	for _, raw := range rawBonds {
+	var parseErrs []error
+	for i, raw := range rawBonds {
+		r := utils.NewReader(raw, c.strict)
 		bond := Bond{
-			Symbol:        utils.GetString(raw, "symbol"),
-			Settlement:    utils.GetString(raw, "settlementType"),
-			BidSize:       utils.GetInt64(raw, "quantityBid"),
-			Bid:           utils.GetFloat64(raw, "bidPrice"),
-			Ask:           utils.GetFloat64(raw, "offerPrice"),
-			AskSize:       utils.GetInt64(raw, "quantityOffer"),
-			Last:          utils.GetFloat64(raw, "settlementPrice"),
-			Close:         utils.GetFloat64(raw, "closingPrice"),
-			Change:        utils.GetFloat64(raw, "imbalance"),
-			Open:          utils.GetFloat64(raw, "openingPrice"),
-			High:          utils.GetFloat64(raw, "tradingHighPrice"),
-			Low:           utils.GetFloat64(raw, "tradingLowPrice"),
-			PreviousClose: utils.GetFloat64(raw, "previousClosingPrice"),
-			Turnover:      utils.GetFloat64(raw, "volumeAmount"),
-			Volume:        utils.GetInt64(raw, "volume"),
-			Operations:    utils.GetInt64(raw, "numberOfOrders"),
-			DateTime:      utils.ParseTradeTime(utils.GetString(raw, "tradeHour")),
-			Group:         utils.GetString(raw, "securityType"),
+			Symbol:        r.RequiredString("symbol"),
+			Settlement:    r.String("settlementType"),
+			BidSize:       r.Int64("quantityBid"),
+			Bid:           r.Float64("bidPrice"),
+			Ask:           r.Float64("offerPrice"),
+			AskSize:       r.Int64("quantityOffer"),
+			Last:          r.Float64("settlementPrice"),
+			Close:         r.Float64("closingPrice"),
+			Change:        r.Float64("imbalance"),
+			Open:          r.Float64("openingPrice"),
+			High:          r.Float64("tradingHighPrice"),
+			Low:           r.Float64("tradingLowPrice"),
+			PreviousClose: r.Float64("previousClosingPrice"),
+			Turnover:      r.Float64("volumeAmount"),
+			Volume:        r.Int64("volume"),
+			Operations:    r.Int64("numberOfOrders"),
+			DateTime:      utils.ParseTradeTime(raw),
+			Group:         r.String("securityType"),
 			Expiration:    utils.GetTime(raw, "maturityDate"),
 		}
+		if err := r.Err(); err != nil {
+			parseErrs = append(parseErrs, fmt.Errorf("item %d (%s): %w", i, bond.Symbol, err))
+			continue
+		}
 		bonds = append(bonds, bond)
 	}
+	if err := c.parseErrors(parseErrs); err != nil {
+		return nil, err
+	}
 
 	return bonds, nil
 }
@@ -2,17 +2,24 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
+	"net"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/joho/godotenv"
+	"golang.org/x/time/rate"
 )
 
 // LogField represents a structured log field
@@ -31,12 +38,33 @@ type Logger interface {
 
 // ClientOptions represents configuration options for the client
 type ClientOptions struct {
-	BaseURL       string
-	Timeout       time.Duration
-	RetryAttempts int
-	Logger        Logger
+	BaseURL            string
+	Timeout            time.Duration
+	RetryAttempts      int
+	RetryBaseDelay     time.Duration
+	RetryMaxDelay      time.Duration
+	Logger             Logger
+	InsecureSkipVerify bool
+	RootCAs            *x509.CertPool
+	HTTPClient         *http.Client
+	StrictParsing      bool
+	OnRetry            RetryFunc
+	OnRequest          RequestFunc
+	OnError            ErrorFunc
+	UserAgent          string
+	ExtraHeaders       map[string]string
+	Debug              bool
+	FuturesMultipliers map[string]float64
+	RequestsPerSecond  float64
+	SkipSessionInit    bool
 }
 
+// Default retry backoff bounds, used when the options leave them unset
+const (
+	DefaultRetryBaseDelay = time.Second
+	DefaultRetryMaxDelay  = 30 * time.Second
+)
+
 // Client implements the openbymadata.Client interface
 type Client struct {
 	httpClient    *http.Client
@@ -45,9 +73,38 @@ type Client struct {
 	dictionary    map[string]string
 	timeout       time.Duration
 	retryAttempts int
+	retryBase     time.Duration
+	retryMax      time.Duration
 	logger        Logger
 	mu            sync.RWMutex
 	debugMode     bool
+	strict        bool  // Fail parses on missing or malformed fields
+	initErr       error // Session initialization failure, see InitError
+	onRetry       RetryFunc
+	onRequest     RequestFunc
+	onError       ErrorFunc
+	retryCounts   retryCounters
+
+	// Futures price multipliers per underlying, see futuresMultiplier
+	futuresMultipliers map[string]float64
+
+	// Client-side rate limit applied to every attempt, nil when unlimited
+	limiter *rate.Limiter
+
+	// Last raw response body per endpoint, see LastRawResponse
+	rawMu        sync.Mutex
+	rawResponses map[string][]byte
+
+	// Lifecycle: shutdownCtx is cancelled to abort in-flight requests on close
+	shutdownCtx    context.Context
+	cancelRequests context.CancelFunc
+	lifecycleMu    sync.Mutex
+	closed         bool
+	inFlight       sync.WaitGroup
+
+	// lifetimeCtx is cancelled as soon as closing starts, see Lifetime
+	lifetimeCtx context.Context
+	endLifetime context.CancelFunc
 }
 
 // New creates a new BYMA data client with the provided options.
@@ -55,23 +112,55 @@ func New(opts *ClientOptions) *Client {
 	// Load .env file if it exists
 	_ = godotenv.Load()
 
-	// Check debug mode from environment
-	debugMode := strings.ToLower(os.Getenv("DEBUG")) == "true"
+	// Debug mode is enabled by the option or the environment
+	debugMode := opts.Debug || strings.ToLower(os.Getenv("DEBUG")) == "true"
+
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		// No http.Client.Timeout: it would cap every call, even those whose context
+		// allows longer. makeRequest applies opts.Timeout per attempt instead.
+		httpClient = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: opts.InsecureSkipVerify,
+					RootCAs:            opts.RootCAs,
+				},
+			},
+		}
+	}
 
-	httpClient := &http.Client{
-		Timeout: opts.Timeout,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
+	retryBase := opts.RetryBaseDelay
+	if retryBase <= 0 {
+		retryBase = DefaultRetryBaseDelay
+	}
+	retryMax := opts.RetryMaxDelay
+	if retryMax <= 0 {
+		retryMax = DefaultRetryMaxDelay
 	}
+	if retryMax < retryBase {
+		retryMax = retryBase
+	}
+
+	shutdownCtx, cancelRequests := context.WithCancel(context.Background())
+	lifetimeCtx, endLifetime := context.WithCancel(context.Background())
 
 	client := &Client{
-		httpClient:    httpClient,
-		baseURL:       opts.BaseURL,
-		timeout:       opts.Timeout,
-		retryAttempts: opts.RetryAttempts,
-		logger:        opts.Logger,
-		debugMode:     debugMode,
+		shutdownCtx:    shutdownCtx,
+		cancelRequests: cancelRequests,
+		lifetimeCtx:    lifetimeCtx,
+		endLifetime:    endLifetime,
+		httpClient:     httpClient,
+		baseURL:        opts.BaseURL,
+		timeout:        opts.Timeout,
+		retryAttempts:  opts.RetryAttempts,
+		retryBase:      retryBase,
+		retryMax:       retryMax,
+		logger:         opts.Logger,
+		debugMode:      debugMode,
+		strict:         opts.StrictParsing,
+		onRetry:        opts.OnRetry,
+		onRequest:      opts.OnRequest,
+		onError:        opts.OnError,
 		headers: map[string]string{
 			"Connection":         "keep-alive",
 			"sec-ch-ua":          `" Not A;Brand";v="99", "Chromium";v="96", "Google Chrome";v="96"`,
@@ -89,20 +178,61 @@ func New(opts *ClientOptions) *Client {
 		},
 	}
 
+	client.futuresMultipliers = make(map[string]float64, len(opts.FuturesMultipliers))
+	for underlying, multiplier := range opts.FuturesMultipliers {
+		if multiplier > 0 {
+			client.futuresMultipliers[strings.ToUpper(strings.TrimSpace(underlying))] = multiplier
+		}
+	}
+
+	if opts.RequestsPerSecond > 0 {
+		// A burst of one spaces requests evenly instead of letting a second's worth out at once
+		client.limiter = rate.NewLimiter(rate.Limit(opts.RequestsPerSecond), 1)
+	}
+
+	for key, value := range opts.ExtraHeaders {
+		client.setHeader(key, value)
+	}
+	if opts.UserAgent != "" {
+		client.setHeader("User-Agent", opts.UserAgent)
+	}
+
 	if debugMode && client.logger != nil {
 		client.logger.Info("Debug mode enabled - will log raw API responses", LogField{Key: "debug", Value: true})
 	}
 
 	// Initialize session and load dictionary
-	if err := client.initializeSession(); err != nil && client.logger != nil {
-		client.logger.Error("Failed to initialize session", LogField{Key: "error", Value: err.Error()})
+	if opts.SkipSessionInit {
+		return client
+	}
+	if err := client.initializeSession(); err != nil {
+		client.initErr = err
+		if client.logger != nil {
+			client.logger.Error("Failed to initialize session", LogField{Key: "error", Value: err.Error()})
+		}
 	}
 
 	return client
 }
 
-// debugLogResponse logs raw API responses when debug mode is enabled
+// LastRawResponse returns a copy of the last raw response body received from the
+// given endpoint (e.g. "cedears" or "public-bonds"), or nil if none was received yet
+func (c *Client) LastRawResponse(endpoint string) []byte {
+	c.rawMu.Lock()
+	defer c.rawMu.Unlock()
+	return bytes.Clone(c.rawResponses[endpoint])
+}
+
+// debugLogResponse records the raw API response for LastRawResponse and logs it when
+// debug mode is enabled
 func (c *Client) debugLogResponse(endpoint string, rawData []byte) {
+	c.rawMu.Lock()
+	if c.rawResponses == nil {
+		c.rawResponses = make(map[string][]byte)
+	}
+	c.rawResponses[endpoint] = rawData
+	c.rawMu.Unlock()
+
 	if !c.debugMode || c.logger == nil {
 		return
 	}
@@ -158,58 +288,165 @@ func (c *Client) debugLogResponse(endpoint string, rawData []byte) {
 	}
 }
 
-// initializeSession initializes the HTTP session and fetches the dictionary
+// InitError returns the error that occurred while initializing the session in New,
+// either establishing it or loading the translation dictionary, or nil on success or
+// when the initialization was skipped
+func (c *Client) InitError() error {
+	return c.initErr
+}
+
+// initializeSession initializes the HTTP session and fetches the dictionary. A missing
+// or malformed dictionary leaves it unloaded, so descriptions are not translated, and
+// is reported as an error too.
 func (c *Client) initializeSession() error {
 	// Visit dashboard to establish session
-	_, err := c.get(c.baseURL + "/#/dashboard")
+	ctx := context.Background()
+	_, err := c.get(ctx, c.baseURL+"/#/dashboard")
 	if err != nil {
 		return fmt.Errorf("failed to establish session: %w", err)
 	}
 
 	// Fetch dictionary for translations
-	dictResp, err := c.get(c.baseURL + "/assets/api/langs/es.json")
+	dictionary, err := c.FetchDictionary(ctx)
 	if err != nil {
-		c.logger.Warn("Failed to fetch dictionary", LogField{Key: "error", Value: err})
-		c.dictionary = make(map[string]string)
-		return nil
+		return err
 	}
+	c.SetDictionary(dictionary)
+	return nil
+}
 
-	if err := json.Unmarshal(dictResp, &c.dictionary); err != nil {
-		c.logger.Warn("Failed to parse dictionary", LogField{Key: "error", Value: err})
-		c.dictionary = make(map[string]string)
+// FetchDictionary fetches the translation dictionary from BYMA without installing it,
+// see SetDictionary
+func (c *Client) FetchDictionary(ctx context.Context) (map[string]string, error) {
+	dictResp, err := c.get(ctx, c.baseURL+"/assets/api/langs/es.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dictionary: %w", err)
 	}
 
-	return nil
+	var dictionary map[string]string
+	if err := json.Unmarshal(dictResp, &dictionary); err != nil {
+		return nil, fmt.Errorf("failed to parse dictionary: %w", err)
+	}
+	if dictionary == nil {
+		dictionary = make(map[string]string)
+	}
+	return dictionary, nil
+}
+
+// SetDictionary installs the translation dictionary used by applyDictionary and
+// Describe, replacing the current one. The map must not be modified afterwards.
+func (c *Client) SetDictionary(dictionary map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dictionary = dictionary
+}
+
+// Dictionary returns the installed translation dictionary, or nil when none was
+// loaded. The map is shared and must not be modified.
+func (c *Client) Dictionary() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.dictionary
 }
 
 // get performs a GET request with retries
-func (c *Client) get(url string) ([]byte, error) {
-	return c.doRequest("GET", url, nil)
+func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	return c.doRequest(ctx, "GET", url, nil)
 }
 
 // post performs a POST request with retries
-func (c *Client) post(url string, data []byte) ([]byte, error) {
-	return c.doRequest("POST", url, data)
+func (c *Client) post(ctx context.Context, url string, data []byte) ([]byte, error) {
+	return c.doRequest(ctx, "POST", url, data)
 }
 
-// doRequest performs an HTTP request with retries and proper error handling
-func (c *Client) doRequest(method, url string, data []byte) ([]byte, error) {
+// doRequest performs an HTTP request with retries and proper error handling.
+// The request and any retry backoff are aborted when ctx is done or the client is closed,
+// and no retry is attempted when its backoff would not finish before ctx's deadline.
+func (c *Client) doRequest(ctx context.Context, method, url string, data []byte) ([]byte, error) {
+	if !c.beginRequest() {
+		return nil, ErrClientClosed
+	}
+	defer c.inFlight.Done()
+
+	c.retryCounts.requests.Add(1)
+	resp, err := c.attemptRequest(ctx, method, url, data)
+	if err != nil {
+		c.retryCounts.failures.Add(1)
+		if c.onError != nil {
+			c.onError(endpointOf(url), err)
+		}
+	}
+	return resp, err
+}
+
+// attemptRequest runs doRequest's attempts and backoffs
+func (c *Client) attemptRequest(ctx context.Context, method, url string, data []byte) ([]byte, error) {
+	// Cancel the request when either the caller's context or the client shuts down
+	reqCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(c.shutdownCtx, cancel)
+	defer stop()
+
 	var lastErr error
+	attempts := 0
 
 	for attempt := 0; attempt <= c.retryAttempts; attempt++ {
 		if attempt > 0 {
-			// Exponential backoff
-			waitTime := time.Duration(attempt) * time.Second
+			// Wait as long as BYMA asked, e.g. when rate limited, instead of backing off
+			waitTime := c.backoff(attempt)
+			var bymaErr *BYMAError
+			if errors.As(lastErr, &bymaErr) && bymaErr.RetryAfter > 0 {
+				waitTime = bymaErr.RetryAfter
+			}
+
+			// Don't sleep through a backoff that would outlive the caller's deadline
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < waitTime {
+				c.logger.Debug("Skipping retry, backoff exceeds context deadline",
+					LogField{Key: "attempt", Value: attempt},
+					LogField{Key: "wait_time", Value: waitTime},
+					LogField{Key: "url", Value: url})
+				return nil, fmt.Errorf("request failed after %d attempts (next retry would exceed context deadline): %w", attempt, lastErr)
+			}
+
 			c.logger.Debug("Retrying request",
 				LogField{Key: "attempt", Value: attempt},
 				LogField{Key: "wait_time", Value: waitTime},
 				LogField{Key: "url", Value: url})
-			time.Sleep(waitTime)
+
+			c.retryCounts.retries.Add(1)
+			if c.onRetry != nil {
+				c.onRetry(attempt, lastErr, waitTime)
+			}
+
+			timer := time.NewTimer(waitTime)
+			select {
+			case <-timer.C:
+			case <-reqCtx.Done():
+				timer.Stop()
+				return nil, c.contextError(ctx, lastErr)
+			}
 		}
 
-		resp, err := c.makeRequest(method, url, data)
+		if err := c.waitForRateLimit(reqCtx); err != nil {
+			if reqCtx.Err() != nil {
+				return nil, c.contextError(ctx, err)
+			}
+			// The limiter refuses waits that would outlive the context deadline
+			return nil, ErrTimeout.WithUnderlying(err)
+		}
+
+		attempts++
+		start := time.Now()
+		attemptCtx := withRequestInfo(reqCtx, RequestInfo{Endpoint: endpointOf(url), Attempt: attempt})
+		resp, err := c.makeRequest(attemptCtx, method, url, data)
+		if c.onRequest != nil {
+			c.onRequest(endpointOf(url), time.Since(start), err)
+		}
 		if err != nil {
 			lastErr = err
+			if reqCtx.Err() != nil {
+				return nil, c.contextError(ctx, lastErr)
+			}
 			if !isRetryable(err) {
 				break
 			}
@@ -219,17 +456,86 @@ func (c *Client) doRequest(method, url string, data []byte) ([]byte, error) {
 		return resp, nil
 	}
 
-	return nil, fmt.Errorf("request failed after %d attempts: %w", c.retryAttempts+1, lastErr)
+	return nil, fmt.Errorf("request failed after %d attempts: %w", attempts, lastErr)
+}
+
+// waitForRateLimit blocks until the rate limiter lets the next attempt through, if
+// RequestsPerSecond was set
+func (c *Client) waitForRateLimit(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+	return c.limiter.Wait(ctx)
+}
+
+// backoff returns the wait before the given retry attempt (starting at 1): exponential
+// backoff with full jitter above the base delay, i.e. a random duration between the
+// base delay and min(base*2^attempt, max), so concurrent clients don't retry in lockstep
+func (c *Client) backoff(attempt int) time.Duration {
+	ceiling := c.retryBase
+	for i := 0; i < attempt && ceiling < c.retryMax; i++ {
+		ceiling *= 2
+	}
+	if ceiling > c.retryMax {
+		ceiling = c.retryMax
+	}
+	return c.retryBase + rand.N(ceiling-c.retryBase+1)
+}
+
+// Backoff returns the wait before the given retry attempt (starting at 1), using the
+// same retry delays as requests. It is used by long-running loops that reconnect.
+func (c *Client) Backoff(attempt int) time.Duration {
+	return c.backoff(attempt)
+}
+
+// contextError builds the error returned when a request is aborted, either because
+// the client was closed or because the caller's context is done
+func (c *Client) contextError(ctx context.Context, lastErr error) error {
+	if c.shutdownCtx.Err() != nil {
+		return fmt.Errorf("%w: %v", ErrClientClosed, lastErr)
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ErrTimeout.WithUnderlying(ctx.Err())
+	}
+	return fmt.Errorf("request cancelled: %w", ctx.Err())
+}
+
+// ContextError returns the error a request made with ctx fails with once ctx is done:
+// ErrTimeout when its deadline passed, ErrClientClosed when the client was closed, and
+// a cancellation error otherwise
+func (c *Client) ContextError(ctx context.Context) error {
+	return c.contextError(ctx, ctx.Err())
+}
+
+// Detach returns a context carrying ctx's values but not its cancellation, for a
+// request shared by several callers that must not fail when the caller that started
+// it gives up. Closing the client still aborts requests made with it. A deadline of
+// ctx later than the client's timeout is kept, so a caller allowing longer still gets
+// it; otherwise the client's timeout bounds each attempt.
+func (c *Client) Detach(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) > c.timeout {
+		return context.WithDeadline(detached, deadline)
+	}
+	return detached, func() {}
 }
 
-// makeRequest makes a single HTTP request
-func (c *Client) makeRequest(method, url string, data []byte) ([]byte, error) {
+// makeRequest makes a single HTTP request. The caller's context deadline, when set,
+// bounds the request, whether it is shorter or longer than the client's timeout;
+// otherwise the client's timeout applies to the attempt.
+func (c *Client) makeRequest(ctx context.Context, method, url string, data []byte) ([]byte, error) {
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
 	var body io.Reader
 	if data != nil {
 		body = bytes.NewReader(data)
 	}
 
-	req, err := http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -247,16 +553,26 @@ func (c *Client) makeRequest(method, url string, data []byte) ([]byte, error) {
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ErrTimeout.WithUnderlying(err)
+		}
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("HTTP error %d", resp.StatusCode)
+		bymaErr := MapHTTPError(resp.StatusCode)
+		if wait := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); wait > 0 {
+			bymaErr = bymaErr.WithRetryAfter(wait)
+		}
+		return nil, bymaErr
 	}
 
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ErrTimeout.WithUnderlying(err)
+		}
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
@@ -267,9 +583,52 @@ func (c *Client) makeRequest(method, url string, data []byte) ([]byte, error) {
 	return responseBody, nil
 }
 
+// SetHeader sets a header sent with every later request, e.g. to rotate an auth token,
+// replacing any header whose name differs only in case. Requests already sent keep
+// the headers they were sent with. It is safe to call while requests are running.
+func (c *Client) SetHeader(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setHeader(key, value)
+}
+
+// RemoveHeader stops sending a header, whatever the case of its name; default headers
+// can be removed too. It is safe to call while requests are running.
+func (c *Client) RemoveHeader(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeHeader(key)
+}
+
+// setHeader sets a request header, replacing any default whose name differs only in
+// case. Callers must hold c.mu unless the client is still being built.
+func (c *Client) setHeader(key, value string) {
+	c.removeHeader(key)
+	c.headers[key] = value
+}
+
+// removeHeader deletes a request header regardless of case. Callers must hold c.mu
+// unless the client is still being built.
+func (c *Client) removeHeader(key string) {
+	for existing := range c.headers {
+		if strings.EqualFold(existing, key) {
+			delete(c.headers, existing)
+		}
+	}
+}
+
 // buildURL constructs a full URL from the base URL and endpoint
 func (c *Client) buildURL(endpoint string) string {
-	return c.baseURL + "/vanoms-be-core/rest/api/bymadata/free/" + endpoint
+	return c.baseURL + apiPath + endpoint
+}
+
+// parseErrors turns the field errors collected in strict parsing mode into a single
+// INVALID_RESPONSE error naming every bad item and field, or nil if there are none
+func (c *Client) parseErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return ErrInvalidResponse.WithUnderlying(errors.Join(errs...))
 }
 
 // parseAPIResponse parses a standard API response
@@ -320,8 +679,99 @@ func (c *Client) applyDictionary(symbol string) string {
 	return symbol
 }
 
-// isRetryable determines if an error is retryable
+// Describe returns the name the translation dictionary gives a symbol, or "" if it has none
+func (c *Client) Describe(symbol string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.dictionary[symbol]
+}
+
+// isRetryable reports whether a failed request is worth retrying: network errors,
+// timeouts, rate limiting (429) and server errors (5xx). Other HTTP errors, invalid
+// requests and TLS verification failures are returned immediately.
 func isRetryable(err error) bool {
-	// Simple retry logic - in a real implementation you'd check specific error types
+	var bymaErr *BYMAError
+	if errors.As(err, &bymaErr) {
+		switch bymaErr.Code {
+		case "TIMEOUT", "API_UNAVAILABLE", "RATE_LIMITED":
+			return true
+		}
+		return bymaErr.StatusCode == http.StatusTooManyRequests ||
+			bymaErr.StatusCode == http.StatusRequestTimeout ||
+			bymaErr.StatusCode >= 500
+	}
+
+	var certErr *tls.CertificateVerificationError
+	if errors.As(err, &certErr) {
+		return false
+	}
+
+	// *url.Error always looks like a net.Error, so inspect what it wraps
+	var urlErr *neturl.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// beginRequest registers an in-flight request, returning false if the client is closed
+func (c *Client) beginRequest() bool {
+	c.lifecycleMu.Lock()
+	defer c.lifecycleMu.Unlock()
+
+	if c.closed {
+		return false
+	}
+	c.inFlight.Add(1)
 	return true
 }
+
+// Lifetime returns a context that is cancelled as soon as the client starts shutting
+// down, before in-flight requests are drained. Background work tied to the client,
+// such as subscriptions, should stop with it.
+func (c *Client) Lifetime() context.Context {
+	return c.lifetimeCtx
+}
+
+// Shutdown stops accepting new requests and waits up to drainTimeout for in-flight
+// requests to finish. Requests still running after the timeout are cancelled and an
+// error is returned. A zero drainTimeout cancels in-flight requests immediately.
+// Idle HTTP connections are closed once no request is left.
+func (c *Client) Shutdown(drainTimeout time.Duration) error {
+	c.lifecycleMu.Lock()
+	c.closed = true
+	c.lifecycleMu.Unlock()
+	c.endLifetime()
+	defer c.httpClient.CloseIdleConnections()
+
+	drained := make(chan struct{})
+	go func() {
+		c.inFlight.Wait()
+		close(drained)
+	}()
+
+	if drainTimeout > 0 {
+		timer := time.NewTimer(drainTimeout)
+		defer timer.Stop()
+
+		select {
+		case <-drained:
+			c.cancelRequests()
+			return nil
+		case <-timer.C:
+		}
+	}
+
+	c.cancelRequests()
+	<-drained
+
+	if drainTimeout > 0 {
+		return fmt.Errorf("drain timeout of %v exceeded, in-flight requests were cancelled", drainTimeout)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/carvalab/openbymadata/internal/utils"
+)
+
+// Corporate action types
+const (
+	CorporateActionDividend = "DIVIDEND"
+	CorporateActionSplit    = "SPLIT"
+)
+
+// CorporateAction is a dividend or split of a security, effective from its ex-date
+type CorporateAction struct {
+	Symbol string    `json:"symbol"`
+	Type   string    `json:"type"`    // CorporateActionDividend or CorporateActionSplit
+	ExDate time.Time `json:"ex_date"` // First trading day without the right to the action
+	Ratio  float64   `json:"ratio"`   // Splits: shares after per share before, e.g. 2 for 2:1, 0.1 for 1:10
+	Amount float64   `json:"amount"`  // Dividends: cash paid per share
+}
+
+// AdjustForSplits back-adjusts a price series for the splits among actions: the
+// prices of every candle before a split's ex-date are divided by its ratio and the
+// volumes multiplied by it (rounded), so the series is continuous across the split.
+// Dividends, and splits without a positive ratio, are ignored. Candles are compared by
+// their BYMA session date. The result is a new series; a nil or inconsistent series
+// is returned as is.
+func AdjustForSplits(data *OHLCV, actions []CorporateAction) *OHLCV {
+	length, err := data.Len()
+	if data == nil || err != nil {
+		return data
+	}
+
+	adjusted := &OHLCV{
+		Time:   slices.Clone(data.Time),
+		Open:   slices.Clone(data.Open),
+		High:   slices.Clone(data.High),
+		Low:    slices.Clone(data.Low),
+		Close:  slices.Clone(data.Close),
+		Volume: slices.Clone(data.Volume),
+	}
+	for _, action := range actions {
+		if action.Type != CorporateActionSplit || action.Ratio <= 0 {
+			continue
+		}
+		exDate := sessionDate(action.ExDate)
+		for i := range length {
+			if !sessionDate(data.Time[i].In(utils.MarketLocation)).Before(exDate) {
+				continue
+			}
+			adjusted.Open[i] /= action.Ratio
+			adjusted.High[i] /= action.Ratio
+			adjusted.Low[i] /= action.Ratio
+			adjusted.Close[i] /= action.Ratio
+			adjusted.Volume[i] = int64(math.Round(float64(adjusted.Volume[i]) * action.Ratio))
+		}
+	}
+	return adjusted
+}
+
+// TotalReturn builds a total-return series from a price series and the actions of its
+// security: the series is adjusted for splits as by AdjustForSplits, and then every
+// dividend is assumed reinvested in the security, free of taxes and fees, at the close
+// of the first candle on or after its ex-date. From that candle on, prices are
+// multiplied by 1 + amount / close, compounding across dividends, so the first candle
+// is left as is and the series ends above the price series when dividends were paid.
+// Dividend amounts are per share at their ex-date and are scaled by the later splits.
+// Dividends going ex on or before the first candle, or without a positive amount, are
+// ignored. The result is a new series; a nil or inconsistent series is returned as is.
+func TotalReturn(data *OHLCV, actions []CorporateAction) *OHLCV {
+	length, err := data.Len()
+	if data == nil || err != nil {
+		return data
+	}
+
+	adjusted := AdjustForSplits(data, actions)
+	if length == 0 {
+		return adjusted
+	}
+	dates := make([]time.Time, length)
+	for i := range length {
+		dates[i] = sessionDate(data.Time[i].In(utils.MarketLocation))
+	}
+
+	// Reinvestment factor applied from each candle on
+	factors := make([]float64, length)
+	for _, action := range actions {
+		if action.Type != CorporateActionDividend || action.Amount <= 0 {
+			continue
+		}
+		exDate := sessionDate(action.ExDate)
+		i, _ := slices.BinarySearchFunc(dates, exDate, func(date, target time.Time) int {
+			return date.Compare(target)
+		})
+		if i == 0 || i == length || adjusted.Close[i] <= 0 {
+			continue
+		}
+		amount := action.Amount
+		for _, split := range actions {
+			if split.Type == CorporateActionSplit && split.Ratio > 0 && sessionDate(split.ExDate).After(exDate) {
+				amount /= split.Ratio
+			}
+		}
+		if factors[i] == 0 {
+			factors[i] = 1
+		}
+		factors[i] *= 1 + amount/adjusted.Close[i]
+	}
+
+	factor := 1.0
+	for i := range length {
+		if factors[i] != 0 {
+			factor *= factors[i]
+		}
+		adjusted.Open[i] *= factor
+		adjusted.High[i] *= factor
+		adjusted.Low[i] *= factor
+		adjusted.Close[i] *= factor
+	}
+	return adjusted
+}
+
+// sessionDate returns the calendar date of t as written in its own zone
+func sessionDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"math"
+
+	"github.com/shopspring/decimal"
+)
+
+// Decimal converts a price to a decimal.Decimal holding exactly the value BYMA quoted.
+// Prices arrive as decimal numbers of well under 15 significant digits, and the float64
+// they are parsed into converts back to that same number, so "4505.25" is 4505.25 and
+// not the nearest binary fraction. Do money math (e.g. summing a portfolio) on the
+// decimals rather than on the floats. NaN and infinities convert to zero.
+func Decimal(price float64) decimal.Decimal {
+	if math.IsNaN(price) || math.IsInf(price, 0) {
+		return decimal.Zero
+	}
+	return decimal.NewFromFloat(price)
+}
+
+// LastDecimal returns Last as an exact decimal, see Decimal
+func (s Security) LastDecimal() decimal.Decimal { return Decimal(s.Last) }
+
+// BidDecimal returns Bid as an exact decimal, see Decimal
+func (s Security) BidDecimal() decimal.Decimal { return Decimal(s.Bid) }
+
+// AskDecimal returns Ask as an exact decimal, see Decimal
+func (s Security) AskDecimal() decimal.Decimal { return Decimal(s.Ask) }
+
+// TurnoverDecimal returns Turnover as an exact decimal, see Decimal
+func (s Security) TurnoverDecimal() decimal.Decimal { return Decimal(s.Turnover) }
+
+// LastDecimal returns Last as an exact decimal, see Decimal
+func (b Bond) LastDecimal() decimal.Decimal { return Decimal(b.Last) }
+
+// BidDecimal returns Bid as an exact decimal, see Decimal
+func (b Bond) BidDecimal() decimal.Decimal { return Decimal(b.Bid) }
+
+// AskDecimal returns Ask as an exact decimal, see Decimal
+func (b Bond) AskDecimal() decimal.Decimal { return Decimal(b.Ask) }
+
+// TurnoverDecimal returns Turnover as an exact decimal, see Decimal
+func (b Bond) TurnoverDecimal() decimal.Decimal { return Decimal(b.Turnover) }
+
+// LastDecimal returns Last as an exact decimal, see Decimal
+func (o Option) LastDecimal() decimal.Decimal { return Decimal(o.Last) }
+
+// BidDecimal returns Bid as an exact decimal, see Decimal
+func (o Option) BidDecimal() decimal.Decimal { return Decimal(o.Bid) }
+
+// AskDecimal returns Ask as an exact decimal, see Decimal
+func (o Option) AskDecimal() decimal.Decimal { return Decimal(o.Ask) }
+
+// TurnoverDecimal returns Turnover as an exact decimal, see Decimal
+func (o Option) TurnoverDecimal() decimal.Decimal { return Decimal(o.Turnover) }
+
+// LastDecimal returns Last as a decimal, see Decimal. Futures prices are scaled by
+// Multiplier, so they are exact only as far as that product is.
+func (f Future) LastDecimal() decimal.Decimal { return Decimal(f.Last) }
+
+// BidDecimal returns Bid as a decimal, see LastDecimal
+func (f Future) BidDecimal() decimal.Decimal { return Decimal(f.Bid) }
+
+// AskDecimal returns Ask as a decimal, see LastDecimal
+func (f Future) AskDecimal() decimal.Decimal { return Decimal(f.Ask) }
+
+// TurnoverDecimal returns Turnover as a decimal, see LastDecimal
+func (f Future) TurnoverDecimal() decimal.Decimal { return Decimal(f.Turnover) }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/carvalab/openbymadata/internal/utils"
 )
@@ -13,7 +15,7 @@ func (c *Client) GetOptions(ctx context.Context) ([]Option, error) {
 	data := []byte(`{"Content-Type":"application/json"}`)
 	url := c.buildURL("options")
 
-	respData, err := c.post(url, data)
+	respData, err := c.post(ctx, url, data)
 	if err != nil {
 		return nil, err
 	}
@@ -27,29 +29,41 @@ func (c *Client) GetOptions(ctx context.Context) ([]Option, error) {
 	}
 
 	options := make([]Option, 0, len(rawOptions))
-	for _, raw := range rawOptions {
+	var parseErrs []error
+	for i, raw := range rawOptions {
+		r := utils.NewReader(raw, c.strict)
 		option := Option{
-			Symbol:          utils.GetString(raw, "symbol"),
-			BidSize:         utils.GetInt64(raw, "quantityBid"),
-			Bid:             utils.GetFloat64(raw, "bidPrice"),
-			Ask:             utils.GetFloat64(raw, "offerPrice"),
-			AskSize:         utils.GetInt64(raw, "quantityOffer"),
-			Last:            utils.GetFloat64(raw, "settlementPrice"),
-			Close:           utils.GetFloat64(raw, "closingPrice"),
-			Change:          utils.GetFloat64(raw, "imbalance"),
-			Open:            utils.GetFloat64(raw, "openingPrice"),
-			High:            utils.GetFloat64(raw, "tradingHighPrice"),
-			Low:             utils.GetFloat64(raw, "tradingLowPrice"),
-			PreviousClose:   utils.GetFloat64(raw, "previousClosingPrice"),
-			Turnover:        utils.GetFloat64(raw, "volumeAmount"),
-			Volume:          utils.GetInt64(raw, "volume"),
-			Operations:      utils.GetInt64(raw, "numberOfOrders"),
-			DateTime:        utils.ParseTradeTime(utils.GetString(raw, "tradeHour")),
-			UnderlyingAsset: utils.GetString(raw, "underlyingSymbol"),
+			Symbol:          r.RequiredString("symbol"),
+			BidSize:         r.Int64("quantityBid"),
+			Bid:             r.Float64("bidPrice"),
+			Ask:             r.Float64("offerPrice"),
+			AskSize:         r.Int64("quantityOffer"),
+			Last:            r.Float64("settlementPrice"),
+			Close:           r.Float64("closingPrice"),
+			Change:          r.Float64("imbalance"),
+			Open:            r.Float64("openingPrice"),
+			High:            r.Float64("tradingHighPrice"),
+			Low:             r.Float64("tradingLowPrice"),
+			PreviousClose:   r.Float64("previousClosingPrice"),
+			Turnover:        r.Float64("volumeAmount"),
+			Volume:          r.Int64("volume"),
+			Operations:      r.Int64("numberOfOrders"),
+			DateTime:        utils.ParseTradeTime(raw),
+			UnderlyingAsset: r.String("underlyingSymbol"),
 			Expiration:      utils.GetTime(raw, "maturityDate"),
 		}
+		if kind, strike, ok := ParseOptionSymbol(option.Symbol); ok {
+			option.Kind, option.Strike = kind, strike
+		}
+		if err := r.Err(); err != nil {
+			parseErrs = append(parseErrs, fmt.Errorf("item %d (%s): %w", i, option.Symbol, err))
+			continue
+		}
 		options = append(options, option)
 	}
+	if err := c.parseErrors(parseErrs); err != nil {
+		return nil, err
+	}
 
 	return options, nil
 }
@@ -59,7 +73,7 @@ func (c *Client) GetFutures(ctx context.Context) ([]Future, error) {
 	data := []byte(`{"page_number":1,"excludeZeroPxAndQty":true,"Content-Type":"application/json"}`)
 	url := c.buildURL("index-future")
 
-	respData, err := c.post(url, data)
+	respData, err := c.post(ctx, url, data)
 	if err != nil {
 		return nil, err
 	}
@@ -73,29 +87,103 @@ func (c *Client) GetFutures(ctx context.Context) ([]Future, error) {
 	}
 
 	futures := make([]Future, 0, len(rawFutures))
-	for _, raw := range rawFutures {
+	var parseErrs []error
+	for i, raw := range rawFutures {
+		r := utils.NewReader(raw, c.strict)
+		symbol := r.RequiredString("symbol")
+		multiplier := c.futuresMultiplier(symbol)
 		future := Future{
-			Symbol:        utils.GetString(raw, "symbol"),
-			BidSize:       utils.GetInt64(raw, "quantityBid"),
-			Bid:           utils.GetFloat64(raw, "bidPrice") * 1000, // Apply price multiplier for futures
-			Ask:           utils.GetFloat64(raw, "offerPrice") * 1000,
-			AskSize:       utils.GetInt64(raw, "quantityOffer"),
-			Last:          utils.GetFloat64(raw, "settlementPrice") * 1000,
-			Close:         utils.GetFloat64(raw, "closingPrice") * 1000,
-			Change:        utils.GetFloat64(raw, "imbalance"),
-			Open:          utils.GetFloat64(raw, "openingPrice") * 1000,
-			High:          utils.GetFloat64(raw, "tradingHighPrice") * 1000,
-			Low:           utils.GetFloat64(raw, "tradingLowPrice") * 1000,
-			PreviousClose: utils.GetFloat64(raw, "previousClosingPrice") * 1000,
-			Turnover:      utils.GetFloat64(raw, "volumeAmount") * 1000,
-			Volume:        utils.GetInt64(raw, "volume") * 1000,
-			Operations:    utils.GetInt64(raw, "numberOfOrders"),
-			DateTime:      utils.ParseTradeTime(utils.GetString(raw, "tradeHour")),
+			Symbol:        symbol,
+			BidSize:       r.Int64("quantityBid"),
+			Bid:           r.Float64("bidPrice") * multiplier,
+			Ask:           r.Float64("offerPrice") * multiplier,
+			AskSize:       r.Int64("quantityOffer"),
+			Last:          r.Float64("settlementPrice") * multiplier,
+			Close:         r.Float64("closingPrice") * multiplier,
+			Change:        r.Float64("imbalance"),
+			Open:          r.Float64("openingPrice") * multiplier,
+			High:          r.Float64("tradingHighPrice") * multiplier,
+			Low:           r.Float64("tradingLowPrice") * multiplier,
+			PreviousClose: r.Float64("previousClosingPrice") * multiplier,
+			Turnover:      r.Float64("volumeAmount") * multiplier,
+			Volume:        r.Int64("volume"), // Contracts, not a price: never scaled
+			Operations:    r.Int64("numberOfOrders"),
+			DateTime:      utils.ParseTradeTime(raw),
 			Expiration:    utils.GetTime(raw, "maturityDate"),
-			OpenInterest:  utils.GetInt64(raw, "openInterest"),
+			OpenInterest:  r.Int64("openInterest"),
+			Multiplier:    multiplier,
+		}
+		if err := r.Err(); err != nil {
+			parseErrs = append(parseErrs, fmt.Errorf("item %d (%s): %w", i, future.Symbol, err))
+			continue
 		}
 		futures = append(futures, future)
 	}
+	if err := c.parseErrors(parseErrs); err != nil {
+		return nil, err
+	}
 
 	return futures, nil
 }
+
+// DefaultFuturesMultiplier is the factor applied to the prices BYMA quotes for futures
+// contracts whose underlying has no multiplier configured. BYMA's index-future
+// endpoint quotes prices in thousands (a dollar future at 1450 pesos arrives as
+// 1.45), so every price field, turnover included, is scaled by it to pesos per unit;
+// volume, operations and open interest are counts and are never scaled. Removing or
+// changing it silently shifts every futures price by orders of magnitude.
+const DefaultFuturesMultiplier = 1000.0
+
+// futuresMultiplier returns the price multiplier for a futures symbol: the one
+// configured for its underlying (the part before "/", e.g. "DLR" in "DLR/OCT25"), or
+// DefaultFuturesMultiplier
+func (c *Client) futuresMultiplier(symbol string) float64 {
+	underlying, _, _ := strings.Cut(strings.ToUpper(strings.TrimSpace(symbol)), "/")
+	if multiplier, ok := c.futuresMultipliers[underlying]; ok {
+		return multiplier
+	}
+	return DefaultFuturesMultiplier
+}
+
+// Option kinds, as derived from the symbol by ParseOptionSymbol and set on Option.Kind
+const (
+	OptionCall = "CALL"
+	OptionPut  = "PUT"
+)
+
+// ParseOptionSymbol derives the kind and strike of an option from its BYMA symbol. BYMA
+// option symbols are the three-letter root of the underlying, "C" for a call or "V"
+// (venta) for a put, the strike and a month code: "GFGC3000OC" is a GGAL call with a
+// 3000 strike expiring in October, "GFGV1234." a put with a 1234 strike. The strike may
+// carry decimals ("GFGC41.5DI"). ok is false when the symbol doesn't follow the convention.
+func ParseOptionSymbol(symbol string) (kind string, strike float64, ok bool) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if len(symbol) < 5 {
+		return "", 0, false
+	}
+	switch symbol[3] {
+	case 'C':
+		kind = OptionCall
+	case 'V':
+		kind = OptionPut
+	default:
+		return "", 0, false
+	}
+
+	rest := symbol[4:]
+	end := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if end < 0 {
+		end = len(rest)
+	}
+	digits := strings.TrimRight(rest[:end], ".")
+	strike, err := strconv.ParseFloat(digits, 64)
+	if err != nil || strike <= 0 {
+		return "", 0, false
+	}
+	for _, r := range rest[end:] {
+		if r < 'A' || r > 'Z' {
+			return "", 0, false
+		}
+	}
+	return kind, strike, true
+}
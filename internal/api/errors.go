@@ -0,0 +1,146 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error types for the BYMA library
+var (
+	ErrInvalidResponse = &BYMAError{Code: "INVALID_RESPONSE", Message: "Invalid API response"}
+	ErrAPIUnavailable  = &BYMAError{Code: "API_UNAVAILABLE", Message: "BYMA API is unavailable"}
+	ErrInvalidTicker   = &BYMAError{Code: "INVALID_TICKER", Message: "Invalid ticker symbol"}
+	ErrTimeout         = &BYMAError{Code: "TIMEOUT", Message: "Request timeout"}
+	ErrUnauthorized    = &BYMAError{Code: "UNAUTHORIZED", Message: "Unauthorized access"}
+	ErrRateLimited     = &BYMAError{Code: "RATE_LIMITED", Message: "Rate limit exceeded"}
+	ErrInternalError   = &BYMAError{Code: "INTERNAL_ERROR", Message: "Internal server error"}
+
+	ErrInvalidResolution = &BYMAError{Code: "INVALID_RESOLUTION", Message: "Invalid history resolution"}
+	ErrInvalidDateRange  = &BYMAError{Code: "INVALID_DATE_RANGE", Message: "Invalid history date range"}
+	ErrNoHistory         = &BYMAError{Code: "NO_DATA", Message: "No historical data available"}
+
+	ErrInvalidSearch = &BYMAError{Code: "INVALID_SEARCH", Message: "Invalid search options"}
+	ErrInvalidPage   = &BYMAError{Code: "INVALID_PAGE", Message: "Invalid page parameters"}
+
+	ErrNoTradeTime = &BYMAError{Code: "NO_TRADE_TIME", Message: "Trade time not available"}
+
+	ErrInvalidDocumentURL = &BYMAError{Code: "INVALID_DOCUMENT_URL", Message: "Not a BYMA document download URL"}
+
+	ErrInvalidSettlement = &BYMAError{Code: "INVALID_SETTLEMENT", Message: "Invalid settlement term"}
+
+	ErrInvalidSnapshotName = &BYMAError{Code: "INVALID_SNAPSHOT_NAME", Message: "Invalid snapshot name"}
+	ErrSnapshotNotFound    = &BYMAError{Code: "SNAPSHOT_NOT_FOUND", Message: "Snapshot not found"}
+)
+
+// ErrClientClosed is returned for requests made after the client has been closed
+var ErrClientClosed = errors.New("client is closed")
+
+// BYMAError represents a custom error from the BYMA library
+type BYMAError struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Underlying error  `json:"-"`
+
+	// RetryAfter is the wait BYMA asked for in a Retry-After header, e.g. with a 429
+	// RATE_LIMITED error; zero when it sent none
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
+}
+
+// Error implements the error interface
+func (e *BYMAError) Error() string {
+	if e.Underlying != nil {
+		return fmt.Sprintf("%s: %s (underlying: %v)", e.Code, e.Message, e.Underlying)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap returns the underlying error
+func (e *BYMAError) Unwrap() error {
+	return e.Underlying
+}
+
+// Is reports whether target is a BYMAError with the same Code, so that errors.Is
+// matches the sentinel errors regardless of status code, message or underlying error
+func (e *BYMAError) Is(target error) bool {
+	t, ok := target.(*BYMAError)
+	return ok && t != nil && e.Code == t.Code
+}
+
+// WithUnderlying adds an underlying error
+func (e *BYMAError) WithUnderlying(err error) *BYMAError {
+	return &BYMAError{
+		Code:       e.Code,
+		Message:    e.Message,
+		StatusCode: e.StatusCode,
+		Underlying: err,
+		RetryAfter: e.RetryAfter,
+	}
+}
+
+// WithStatusCode adds an HTTP status code
+func (e *BYMAError) WithStatusCode(code int) *BYMAError {
+	return &BYMAError{
+		Code:       e.Code,
+		Message:    e.Message,
+		StatusCode: code,
+		Underlying: e.Underlying,
+		RetryAfter: e.RetryAfter,
+	}
+}
+
+// WithRetryAfter adds the wait asked for by a Retry-After header
+func (e *BYMAError) WithRetryAfter(wait time.Duration) *BYMAError {
+	return &BYMAError{
+		Code:       e.Code,
+		Message:    e.Message,
+		StatusCode: e.StatusCode,
+		Underlying: e.Underlying,
+		RetryAfter: wait,
+	}
+}
+
+// NewBYMAError creates a new BYMA error
+func NewBYMAError(code, message string) *BYMAError {
+	return &BYMAError{
+		Code:    code,
+		Message: message,
+	}
+}
+
+// MapHTTPError maps HTTP status codes to BYMA errors
+func MapHTTPError(statusCode int) *BYMAError {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return ErrUnauthorized.WithStatusCode(statusCode)
+	case http.StatusTooManyRequests:
+		return ErrRateLimited.WithStatusCode(statusCode)
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable:
+		return ErrAPIUnavailable.WithStatusCode(statusCode)
+	case http.StatusRequestTimeout:
+		return ErrTimeout.WithStatusCode(statusCode)
+	default:
+		return NewBYMAError("HTTP_ERROR", fmt.Sprintf("HTTP error %d", statusCode)).WithStatusCode(statusCode)
+	}
+}
+
+// parseRetryAfter parses a Retry-After header value, given either in seconds or as an
+// HTTP date, into the wait it asks for from now. It returns zero when the value is
+// empty, invalid or already past.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
+}
@@ -3,20 +3,125 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/carvalab/openbymadata/internal/utils"
 )
 
+// resolutionAliases maps the accepted resolution spellings, in lower case, to the
+// value expected by the API
+var resolutionAliases = map[string]string{
+	"d": "D", "1d": "D", "day": "D", "daily": "D",
+	"w": "W", "1w": "W", "week": "W", "weekly": "W",
+	"m": "M", "1m": "M", "month": "M", "monthly": "M",
+}
+
+// DefaultHistorySettlement is the settlement segment queried by GetHistory when the
+// symbol does not name one
+const DefaultHistorySettlement = "24HS"
+
+// HistorySymbol returns the symbol sent to the history endpoint. Symbols that already
+// carry a settlement suffix, such as "AL30 CI" or "GGAL 48HS", are passed through;
+// bare symbols get DefaultHistorySettlement appended.
+func HistorySymbol(symbol string) string {
+	symbol = strings.Join(strings.Fields(symbol), " ")
+	if strings.Contains(symbol, " ") {
+		return symbol
+	}
+	return symbol + " " + DefaultHistorySettlement
+}
+
+// IntradayResolutions lists the supported intraday resolutions, in minutes per bar
+var IntradayResolutions = []int{1, 5, 15, 30, 60}
+
+// MaxIntradayRange is the longest range requested for intraday resolutions. The open
+// API does not publish its intraday depth and only keeps recent sessions at minute
+// granularity, so older starts are clamped instead of requesting months of bars.
+const MaxIntradayRange = 30 * 24 * time.Hour
+
+// NormalizeResolution validates a history resolution and returns the value expected
+// by the API: "D" (daily), "W" (weekly) or "M" (monthly), or the number of minutes per
+// bar for intraday resolutions ("1", "5", "15", "30" or "60"). Matching is
+// case-insensitive and also accepts the aliases "1D"/"day"/"daily", "1W"/"week"/"weekly"
+// and "1M"/"month"/"monthly"; note that "1M" is one month, not one minute. Anything else
+// is an INVALID_RESOLUTION error.
+func NormalizeResolution(resolution string) (string, error) {
+	trimmed := strings.TrimSpace(resolution)
+	if IsIntradayResolution(trimmed) {
+		return trimmed, nil
+	}
+
+	normalized, ok := resolutionAliases[strings.ToLower(trimmed)]
+	if !ok {
+		return "", NewBYMAError(ErrInvalidResolution.Code,
+			fmt.Sprintf("unsupported resolution %q (use D, W, M or 1, 5, 15, 30, 60 minutes)", resolution))
+	}
+	return normalized, nil
+}
+
+// IsIntradayResolution reports whether resolution is a supported number of minutes per bar
+func IsIntradayResolution(resolution string) bool {
+	for _, minutes := range IntradayResolutions {
+		if resolution == strconv.Itoa(minutes) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateDateRange checks a history range, returning an INVALID_DATE_RANGE error when
+// either bound is the zero time, when from is after to, or when the whole range lies
+// in the future
+func ValidateDateRange(from, to time.Time) error {
+	invalid := func(format string, args ...interface{}) error {
+		return NewBYMAError(ErrInvalidDateRange.Code, fmt.Sprintf(format, args...))
+	}
+
+	switch {
+	case from.IsZero() || to.IsZero():
+		return invalid("from and to must be set")
+	case from.After(to):
+		return invalid("from (%s) is after to (%s)", from.Format(time.DateOnly), to.Format(time.DateOnly))
+	case from.After(time.Now()):
+		return invalid("range starting %s is in the future", from.Format(time.DateOnly))
+	}
+	return nil
+}
+
 // GetHistory retrieves historical price data for a given symbol as OHLCV arrays
-// symbol: the ticker symbol (will always have "24HS" suffix added)
-// resolution: "D" for daily, "W" for weekly, "M" for monthly
+// symbol: the ticker symbol, optionally with a settlement suffix (see HistorySymbol)
+// resolution: "D" for daily, "W" for weekly, "M" for monthly (see NormalizeResolution)
 // from: Start date as time.Time
-// to: End date as time.Time
+// to: End date as time.Time; ranges ending in the future are clamped to now, and
+// intraday ranges longer than MaxIntradayRange are shortened to end at to
 func (c *Client) GetHistory(ctx context.Context, symbol, resolution string, from, to time.Time) (*OHLCV, error) {
-	// Always ensure "24HS" suffix is needed for the api
-	symbol = symbol + " 24HS"
+	resolution, err := NormalizeResolution(resolution)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateDateRange(from, to); err != nil {
+		return nil, err
+	}
+	if now := time.Now(); to.After(now) {
+		c.logger.Debug("Clamping history range end to now",
+			LogField{Key: "to", Value: to},
+			LogField{Key: "now", Value: now})
+		to = now
+	}
+	if IsIntradayResolution(resolution) && to.Sub(from) > MaxIntradayRange {
+		c.logger.Debug("Clamping intraday history range",
+			LogField{Key: "from", Value: from},
+			LogField{Key: "max_range", Value: MaxIntradayRange})
+		from = to.Add(-MaxIntradayRange)
+	}
+
+	// The API expects the settlement segment as part of the symbol
+	symbol = HistorySymbol(symbol)
 
 	// Convert time.Time to Unix timestamps
 	fromUnix := from.Unix()
@@ -35,30 +140,59 @@ func (c *Client) GetHistory(ctx context.Context, symbol, resolution string, from
 
 	fullURL := baseURL + "?" + params.Encode()
 
-	respData, err := c.get(fullURL)
+	respData, err := c.get(ctx, fullURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get history data: %w", err)
+		return nil, historyRequestError(err)
 	}
 
 	c.debugLogResponse("chart/historical-series/history", respData)
 
 	var historyResp HistoryResponse
 	if err := json.Unmarshal(respData, &historyResp); err != nil {
-		return nil, fmt.Errorf("failed to parse history response: %w", err)
+		return nil, ErrInvalidResponse.WithUnderlying(fmt.Errorf("failed to parse history response: %w", err))
 	}
 
-	if historyResp.Status != "ok" {
-		return nil, fmt.Errorf("no historical data available for symbol %s (status: %s)", symbol, historyResp.Status)
+	switch historyResp.Status {
+	case "ok":
+	case "no_data":
+		return nil, NewBYMAError(ErrNoHistory.Code, fmt.Sprintf("no historical data for %s from %s to %s",
+			symbol, from.Format(time.DateOnly), to.Format(time.DateOnly)))
+	default:
+		message := fmt.Sprintf("history request for %s from %s to %s returned status %q",
+			symbol, from.Format(time.DateOnly), to.Format(time.DateOnly), historyResp.Status)
+		if historyResp.ErrMsg != "" {
+			message += ": " + historyResp.ErrMsg
+		}
+		return nil, NewBYMAError(ErrInvalidResponse.Code, message)
 	}
 
-	return &OHLCV{
+	ohlcv := &OHLCV{
 		Time:   parseDates(historyResp.Time),
 		Open:   historyResp.Open,
 		High:   historyResp.High,
 		Low:    historyResp.Low,
 		Close:  historyResp.Close,
 		Volume: historyResp.Volume,
-	}, nil
+	}
+	if _, err := ohlcv.Len(); err != nil {
+		return nil, ErrInvalidResponse.WithUnderlying(fmt.Errorf("history for %s: %w (t=%d o=%d h=%d l=%d c=%d v=%d)",
+			symbol, err, len(historyResp.Time), len(historyResp.Open), len(historyResp.High),
+			len(historyResp.Low), len(historyResp.Close), len(historyResp.Volume)))
+	}
+
+	return ohlcv, nil
+}
+
+// historyRequestError types the error of a failed history request. Errors that already
+// carry a BYMAError (HTTP status, timeout), cancellations and a closed client keep
+// their meaning; any other transport failure becomes API_UNAVAILABLE, so IsRetryable
+// and errors.Is classify it like the rest of the client's errors.
+func historyRequestError(err error) error {
+	var bymaErr *BYMAError
+	if errors.As(err, &bymaErr) || errors.Is(err, context.Canceled) || errors.Is(err, ErrClientClosed) {
+		return fmt.Errorf("failed to get history data: %w", err)
+	}
+	return ErrAPIUnavailable.WithUnderlying(fmt.Errorf("failed to get history data: %w", err))
 }
 
 // GetHistoryLastDays is a convenience method to get history for the last N days
@@ -73,10 +207,9 @@ func (c *Client) GetHistoryLastDays(ctx context.Context, symbol string, days int
 // ConvertToHistoricalData converts OHLCV to HistoricalData array (utility function)
 func (c *Client) ConvertToHistoricalData(slices *OHLCV) ([]HistoricalData, error) {
 	// Validate that all arrays have the same length
-	length := len(slices.Time)
-	if len(slices.Close) != length || len(slices.Open) != length ||
-		len(slices.High) != length || len(slices.Low) != length || len(slices.Volume) != length {
-		return nil, fmt.Errorf("inconsistent array lengths in OHLCV slices")
+	length, err := slices.Len()
+	if err != nil {
+		return nil, err
 	}
 
 	// Convert to structured format
@@ -105,11 +238,12 @@ type OHLCV struct {
 	Volume []int64     `json:"volume"`
 }
 
+// parseDates converts Unix timestamps to times in the time zone of the BYMA session
 func parseDates(unixTimes []int64) []time.Time {
 	res := make([]time.Time, len(unixTimes))
 
 	for i, t := range unixTimes {
-		res[i] = time.Unix(t, 0)
+		res[i] = time.Unix(t, 0).In(utils.MarketLocation)
 	}
 
 	return res
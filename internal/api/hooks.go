@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RequestFunc is called after each HTTP attempt, retries included, with the endpoint
+// requested, how long the attempt took and its error (nil on success)
+type RequestFunc func(endpoint string, duration time.Duration, err error)
+
+// ErrorFunc is called when a request fails after any retries, with the endpoint
+// requested and the error returned to the caller
+type ErrorFunc func(endpoint string, err error)
+
+// RequestInfo describes the HTTP attempt a request belongs to. It is carried by the
+// context of every request the client sends, see RequestInfoFrom.
+type RequestInfo struct {
+	Endpoint string // As reported to RequestFunc, e.g. "cedears"
+	Attempt  int    // 0 for the first attempt, n for the nth retry
+}
+
+// requestInfoKey is the context key of RequestInfo
+type requestInfoKey struct{}
+
+// withRequestInfo returns a copy of ctx carrying info
+func withRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFrom returns the RequestInfo carried by the context of a request sent by
+// the client, reporting false for any other context
+func RequestInfoFrom(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+// apiPath is the path prefix of every BYMA API endpoint
+const apiPath = "/vanoms-be-core/rest/api/bymadata/free/"
+
+// endpointOf returns the endpoint reported to RequestFunc and ErrorFunc for a request
+// URL: the path after the API prefix, e.g. "cedears". Document downloads report
+// "sba/download" without the file name, so the set of endpoints stays bounded, and
+// requests outside the API (the session setup) report their path.
+func endpointOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	endpoint, isAPI := strings.CutPrefix(parsed.Path, apiPath)
+	if !isAPI {
+		return parsed.Path
+	}
+	if strings.HasPrefix(endpoint, downloadEndpoint) {
+		return strings.TrimSuffix(downloadEndpoint, "/")
+	}
+	return endpoint
+}
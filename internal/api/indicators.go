@@ -0,0 +1,108 @@
+package api
+
+import "fmt"
+
+// Technical indicators computed on closing prices. Every indicator returns a slice
+// aligned with the series, i.e. result[i] belongs to Time[i]; points inside the
+// warm-up window, before enough data is available, are left at zero.
+
+// checkIndicatorInput returns the closing prices after validating the period and
+// that the series has at least minPoints data points
+func (o *OHLCV) checkIndicatorInput(name string, period, minPoints int) ([]float64, error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("%s: period must be positive, got %d", name, period)
+	}
+	length, err := o.Len()
+	if err != nil {
+		return nil, err
+	}
+	if length < minPoints {
+		return nil, fmt.Errorf("%s(%d): need at least %d data points, got %d", name, period, minPoints, length)
+	}
+	return o.Close, nil
+}
+
+// SMA returns the simple moving average of closing prices over period points.
+// The first period-1 values are zero.
+func (o *OHLCV) SMA(period int) ([]float64, error) {
+	closes, err := o.checkIndicatorInput("SMA", period, period)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]float64, len(closes))
+	sum := 0.0
+	for i, price := range closes {
+		sum += price
+		if i >= period {
+			sum -= closes[i-period]
+		}
+		if i >= period-1 {
+			result[i] = sum / float64(period)
+		}
+	}
+	return result, nil
+}
+
+// EMA returns the exponential moving average of closing prices, with smoothing
+// factor 2/(period+1), seeded with the SMA of the first period points.
+// The first period-1 values are zero.
+func (o *OHLCV) EMA(period int) ([]float64, error) {
+	closes, err := o.checkIndicatorInput("EMA", period, period)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]float64, len(closes))
+	k := 2 / float64(period+1)
+	for i := 0; i < period; i++ {
+		result[period-1] += closes[i]
+	}
+	result[period-1] /= float64(period)
+	for i := period; i < len(closes); i++ {
+		result[i] = closes[i]*k + result[i-1]*(1-k)
+	}
+	return result, nil
+}
+
+// RSI returns Wilder's relative strength index of closing prices, between 0 and 100.
+// It needs period+1 points; the first period values are zero.
+func (o *OHLCV) RSI(period int) ([]float64, error) {
+	closes, err := o.checkIndicatorInput("RSI", period, period+1)
+	if err != nil {
+		return nil, err
+	}
+
+	rsi := func(avgGain, avgLoss float64) float64 {
+		if avgLoss == 0 {
+			return 100
+		}
+		return 100 - 100/(1+avgGain/avgLoss)
+	}
+	gainLoss := func(i int) (float64, float64) {
+		change := closes[i] - closes[i-1]
+		if change > 0 {
+			return change, 0
+		}
+		return 0, -change
+	}
+
+	result := make([]float64, len(closes))
+	var avgGain, avgLoss float64
+	for i := 1; i <= period; i++ {
+		gain, loss := gainLoss(i)
+		avgGain += gain
+		avgLoss += loss
+	}
+	avgGain /= float64(period)
+	avgLoss /= float64(period)
+	result[period] = rsi(avgGain, avgLoss)
+
+	for i := period + 1; i < len(closes); i++ {
+		gain, loss := gainLoss(i)
+		avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+		avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+		result[i] = rsi(avgGain, avgLoss)
+	}
+	return result, nil
+}
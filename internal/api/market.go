@@ -4,39 +4,93 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/carvalab/openbymadata/internal/utils"
 )
 
 // IsWorkingDay checks if the current day is a working day for the BYMA market
 func (c *Client) IsWorkingDay(ctx context.Context) (bool, error) {
-	url := c.buildURL("market-time")
-	respData, err := c.post(url, []byte(`{"Content-Type":"application/json"}`))
+	marketTime, err := c.GetMarketTime(ctx)
 	if err != nil {
 		return false, err
 	}
+	return marketTime.IsWorkingDay, nil
+}
+
+// Regular BYMA trading session, in MarketLocation. The market-time endpoint only
+// reports whether today is a working day, so these hours tell whether the market is open.
+const (
+	sessionOpenHour  = 11
+	sessionCloseHour = 17
+)
+
+// InRegularSession reports whether t falls within the regular trading session of a
+// weekday. Holidays are only known from GetMarketTime, so they count as trading days.
+func InRegularSession(t time.Time) bool {
+	t = t.In(utils.MarketLocation)
+	if weekday := t.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+		return false
+	}
+	return t.Hour() >= sessionOpenHour && t.Hour() < sessionCloseHour
+}
+
+// NextSessionOpen returns the start of the first regular trading session after t,
+// skipping weekends
+func NextSessionOpen(t time.Time) time.Time {
+	t = t.In(utils.MarketLocation)
+	year, month, day := t.Date()
+	open := time.Date(year, month, day, sessionOpenHour, 0, 0, 0, utils.MarketLocation)
+	if !open.After(t) {
+		open = open.AddDate(0, 0, 1)
+	}
+	for open.Weekday() == time.Saturday || open.Weekday() == time.Sunday {
+		open = open.AddDate(0, 0, 1)
+	}
+	return open
+}
+
+// GetMarketTime retrieves BYMA's market time: whether today is a working day and, on
+// working days, the regular session hours and whether the local clock falls within
+// them. The endpoint reports no server time or session hours, so ServerTime is the
+// local clock. Times are expressed in MarketLocation.
+func (c *Client) GetMarketTime(ctx context.Context) (*MarketTime, error) {
+	url := c.buildURL("market-time")
+	respData, err := c.post(ctx, url, []byte(`{"Content-Type":"application/json"}`))
+	if err != nil {
+		return nil, err
+	}
 
 	// Debug: log raw response
 	c.debugLogResponse("market-time", respData)
 
+	marketTime := &MarketTime{ServerTime: time.Now().In(utils.MarketLocation)}
 	var response MarketTimeResponse
 	if err := json.Unmarshal(respData, &response); err != nil {
 		// If we can't parse the specific response, try to check if we got data back
 		// The presence of data usually indicates it's a working day
 		var rawData interface{}
-		if json.Unmarshal(respData, &rawData) == nil {
-			return true, nil
+		if json.Unmarshal(respData, &rawData) != nil {
+			return nil, ErrInvalidResponse.WithUnderlying(fmt.Errorf("invalid market time response: %w", err))
 		}
-		return false, fmt.Errorf("invalid response: %w", err)
+		response.IsWorkingDay = true
 	}
 
-	return response.IsWorkingDay, nil
+	marketTime.IsWorkingDay = response.IsWorkingDay
+	if marketTime.IsWorkingDay {
+		year, month, day := marketTime.ServerTime.Date()
+		marketTime.SessionOpen = time.Date(year, month, day, sessionOpenHour, 0, 0, 0, utils.MarketLocation)
+		marketTime.SessionClose = time.Date(year, month, day, sessionCloseHour, 0, 0, 0, utils.MarketLocation)
+		marketTime.IsOpen = !marketTime.ServerTime.Before(marketTime.SessionOpen) &&
+			marketTime.ServerTime.Before(marketTime.SessionClose)
+	}
+	return marketTime, nil
 }
 
 // GetIndices retrieves market indices information
 func (c *Client) GetIndices(ctx context.Context) ([]Index, error) {
 	url := c.buildURL("index-price")
-	respData, err := c.post(url, []byte(`{"Content-Type":"application/json"}`))
+	respData, err := c.post(ctx, url, []byte(`{"Content-Type":"application/json"}`))
 	if err != nil {
 		return nil, err
 	}
@@ -50,18 +104,27 @@ func (c *Client) GetIndices(ctx context.Context) ([]Index, error) {
 	}
 
 	indices := make([]Index, 0, len(rawIndices))
-	for _, raw := range rawIndices {
+	var parseErrs []error
+	for i, raw := range rawIndices {
+		r := utils.NewReader(raw, c.strict)
 		index := Index{
-			Description:   c.applyDictionary(utils.GetString(raw, "description")),
-			Symbol:        utils.GetString(raw, "symbol"),
-			Last:          utils.GetFloat64(raw, "price"),
-			Change:        utils.GetFloat64(raw, "variation"),
-			High:          utils.GetFloat64(raw, "highValue"),
-			Low:           utils.GetFloat64(raw, "minValue"),
-			PreviousClose: utils.GetFloat64(raw, "previousClosingPrice"),
+			Description:   c.applyDictionary(r.String("description")),
+			Symbol:        r.RequiredString("symbol"),
+			Last:          r.Float64("price"),
+			Change:        r.Float64("variation"),
+			High:          r.Float64("highValue"),
+			Low:           r.Float64("minValue"),
+			PreviousClose: r.Float64("previousClosingPrice"),
+		}
+		if err := r.Err(); err != nil {
+			parseErrs = append(parseErrs, fmt.Errorf("item %d (%s): %w", i, index.Symbol, err))
+			continue
 		}
 		indices = append(indices, index)
 	}
+	if err := c.parseErrors(parseErrs); err != nil {
+		return nil, err
+	}
 
 	return indices, nil
 }
@@ -69,7 +132,7 @@ func (c *Client) GetIndices(ctx context.Context) ([]Index, error) {
 // MarketResume retrieves market summary data
 func (c *Client) MarketResume(ctx context.Context) ([]MarketSummary, error) {
 	url := c.buildURL("total-negotiated")
-	respData, err := c.post(url, []byte(`{"Content-Type":"application/json"}`))
+	respData, err := c.post(ctx, url, []byte(`{"Content-Type":"application/json"}`))
 	if err != nil {
 		return nil, err
 	}
@@ -83,17 +146,26 @@ func (c *Client) MarketResume(ctx context.Context) ([]MarketSummary, error) {
 	}
 
 	summaries := make([]MarketSummary, 0, len(rawSummaries))
-	for _, raw := range rawSummaries {
+	var parseErrs []error
+	for i, raw := range rawSummaries {
+		r := utils.NewReader(raw, c.strict)
 		summary := MarketSummary{
-			Symbol:          utils.GetString(raw, "symbol"),
-			AssetType:       utils.GetString(raw, "assetType"),
-			ParentKey:       utils.GetString(raw, "parentKey"),
-			TotalNegotiated: utils.GetFloat64(raw, "totalNegotiated"),
-			Volume:          utils.GetInt64(raw, "volume"),
-			Operations:      utils.GetInt64(raw, "operations"),
+			Symbol:          r.String("symbol"),
+			AssetType:       r.String("assetType"),
+			ParentKey:       r.String("parentKey"),
+			TotalNegotiated: r.Float64("totalNegotiated"),
+			Volume:          r.Int64("volume"),
+			Operations:      r.Int64("operations"),
+		}
+		if err := r.Err(); err != nil {
+			parseErrs = append(parseErrs, fmt.Errorf("item %d (%s): %w", i, summary.Symbol, err))
+			continue
 		}
 		summaries = append(summaries, summary)
 	}
+	if err := c.parseErrors(parseErrs); err != nil {
+		return nil, err
+	}
 
 	return summaries, nil
 }
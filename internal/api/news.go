@@ -3,14 +3,20 @@ package api
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"path"
+	"strings"
 
 	"github.com/carvalab/openbymadata/internal/utils"
 )
 
+// downloadEndpoint is the endpoint documents are downloaded from, followed by their name
+const downloadEndpoint = "sba/download/"
+
 // GetNews retrieves market news
 func (c *Client) GetNews(ctx context.Context) ([]News, error) {
 	url := c.buildURL("bnown/byma-ads")
-	respData, err := c.post(url, []byte(`{"Content-Type":"application/json"}`))
+	respData, err := c.post(ctx, url, []byte(`{"Content-Type":"application/json"}`))
 	if err != nil {
 		return nil, err
 	}
@@ -29,7 +35,7 @@ func (c *Client) GetNews(ctx context.Context) ([]News, error) {
 			Fecha:       utils.GetTime(raw, "fecha"),
 			Titulo:      utils.GetString(raw, "emisor"),     // emisor is the company name (title)
 			Descripcion: utils.GetString(raw, "referencia"), // referencia is the description
-			Descarga:    "https://open.bymadata.com.ar/vanoms-be-core/rest/api/bymadata/free/sba/download/" + utils.GetString(raw, "descarga"),
+			Descarga:    c.buildURL(downloadEndpoint + utils.GetString(raw, "descarga")),
 		}
 		news = append(news, newsItem)
 	}
@@ -41,7 +47,7 @@ func (c *Client) GetNews(ctx context.Context) ([]News, error) {
 func (c *Client) GetIncomeStatement(ctx context.Context, ticker string) ([]IncomeStatement, error) {
 	url := c.buildURL("bnown/seriesHistoricas/balances")
 	data := fmt.Sprintf(`{"symbol": "%s", "Content-Type": "application/json"}`, ticker)
-	respData, err := c.post(url, []byte(data))
+	respData, err := c.post(ctx, url, []byte(data))
 	if err != nil {
 		return nil, err
 	}
@@ -61,10 +67,43 @@ func (c *Client) GetIncomeStatement(ctx context.Context, ticker string) ([]Incom
 			Periodo:         utils.GetString(raw, "periodo"),
 			TipoPeriodo:     utils.GetString(raw, "tipoPeriodo"),
 			FechaCierre:     utils.GetString(raw, "fechaCierre"),
-			BalancesArchivo: "https://open.bymadata.com.ar/vanoms-be-core/rest/api/bymadata/free/sba/download/" + utils.GetString(raw, "balancesArchivo"),
+			BalancesArchivo: c.buildURL(downloadEndpoint + utils.GetString(raw, "balancesArchivo")),
 		}
 		statements = append(statements, statement)
 	}
 
 	return statements, nil
 }
+
+// FetchDocument downloads a document (news attachment or balance sheet) using the
+// client session. Only document downloads on the client's BYMA host are fetched, see
+// CheckDocumentURL.
+func (c *Client) FetchDocument(ctx context.Context, documentURL string) ([]byte, error) {
+	if err := c.CheckDocumentURL(documentURL); err != nil {
+		return nil, err
+	}
+	return c.get(ctx, documentURL)
+}
+
+// CheckDocumentURL returns an ErrInvalidDocumentURL error unless documentURL is a
+// document download on the client's BYMA host, as found in News.Descarga and
+// IncomeStatement.BalancesArchivo. This keeps the session from being sent to any
+// other server.
+func (c *Client) CheckDocumentURL(documentURL string) error {
+	target, err := url.Parse(documentURL)
+	if err != nil {
+		return ErrInvalidDocumentURL.WithUnderlying(err)
+	}
+	downloads, err := url.Parse(c.buildURL(downloadEndpoint))
+	if err != nil {
+		return ErrInvalidDocumentURL.WithUnderlying(err)
+	}
+
+	name, isDownload := strings.CutPrefix(target.Path, downloads.Path)
+	if !strings.EqualFold(target.Scheme, downloads.Scheme) || !strings.EqualFold(target.Host, downloads.Host) ||
+		target.User != nil || !isDownload || name == "" || path.Clean(target.Path) != target.Path {
+		return NewBYMAError(ErrInvalidDocumentURL.Code,
+			fmt.Sprintf("%s is not a document download on %s", documentURL, downloads.Host))
+	}
+	return nil
+}
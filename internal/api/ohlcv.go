@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// errInconsistentOHLCV is returned when the OHLCV slices differ in length
+var errInconsistentOHLCV = errors.New("inconsistent array lengths in OHLCV slices")
+
+// Len returns the number of data points, or an error if the slices differ in length
+func (o *OHLCV) Len() (int, error) {
+	if o == nil {
+		return 0, nil
+	}
+	length := len(o.Time)
+	if len(o.Open) != length || len(o.High) != length || len(o.Low) != length ||
+		len(o.Close) != length || len(o.Volume) != length {
+		return 0, errInconsistentOHLCV
+	}
+	return length, nil
+}
+
+// Tail returns a new series with the last n data points, or the whole series when it
+// has fewer. A nil or inconsistent series is returned as is.
+func (o *OHLCV) Tail(n int) *OHLCV {
+	length, err := o.Len()
+	if o == nil || err != nil {
+		return o
+	}
+	start := max(length-max(n, 0), 0)
+	return &OHLCV{
+		Time:   slices.Clone(o.Time[start:]),
+		Open:   slices.Clone(o.Open[start:]),
+		High:   slices.Clone(o.High[start:]),
+		Low:    slices.Clone(o.Low[start:]),
+		Close:  slices.Clone(o.Close[start:]),
+		Volume: slices.Clone(o.Volume[start:]),
+	}
+}
+
+// Clone returns a copy of the series that shares no memory with it
+func (o *OHLCV) Clone() *OHLCV {
+	if o == nil {
+		return nil
+	}
+	return &OHLCV{
+		Time:   slices.Clone(o.Time),
+		Open:   slices.Clone(o.Open),
+		High:   slices.Clone(o.High),
+		Low:    slices.Clone(o.Low),
+		Close:  slices.Clone(o.Close),
+		Volume: slices.Clone(o.Volume),
+	}
+}
+
+// MarshalJSON encodes the series with its times in UTC, so it matches the other types
+// of the library
+func (o OHLCV) MarshalJSON() ([]byte, error) {
+	type ohlcv OHLCV // avoids recursing into MarshalJSON
+	out := ohlcv(o)
+	if o.Time != nil {
+		out.Time = make([]time.Time, len(o.Time))
+		for i, t := range o.Time {
+			out.Time[i] = t.UTC()
+		}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes the series, expressing its times in the time zone of the BYMA
+// session
+func (o *OHLCV) UnmarshalJSON(data []byte) error {
+	type ohlcv OHLCV // avoids recursing into UnmarshalJSON
+	var in ohlcv
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*o = OHLCV(in)
+	for i, t := range o.Time {
+		o.Time[i] = inMarketLocation(t)
+	}
+	return nil
+}
+
+// WriteCSV writes the series as CSV: a header row (time, open, high, low, close,
+// volume) followed by one row per data point, with times formatted as RFC 3339.
+// An empty or nil series writes just the header.
+func (o *OHLCV) WriteCSV(w io.Writer) error {
+	length, err := o.Len()
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"time", "open", "high", "low", "close", "volume"}); err != nil {
+		return err
+	}
+	formatFloat := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	for i := range length {
+		row := []string{
+			o.Time[i].Format(time.RFC3339),
+			formatFloat(o.Open[i]),
+			formatFloat(o.High[i]),
+			formatFloat(o.Low[i]),
+			formatFloat(o.Close[i]),
+			strconv.FormatInt(o.Volume[i], 10),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,102 @@
+package api
+
+// Bid/ask pricing helpers. A side quoted at zero (or below) means there is no
+// quote on that side, in which case every helper returns 0 and false. The day
+// range helper works the same way with Low and High.
+
+// Spread returns Ask - Bid
+func (s Security) Spread() (float64, bool) {
+	return spread(s.Bid, s.Ask)
+}
+
+// MidPrice returns the midpoint between Bid and Ask
+func (s Security) MidPrice() (float64, bool) {
+	return midPrice(s.Bid, s.Ask)
+}
+
+// SpreadPercent returns the spread as a percentage of the mid price
+func (s Security) SpreadPercent() (float64, bool) {
+	return spreadPercent(s.Bid, s.Ask)
+}
+
+// PositionInDayRange returns where Last lies within the day's Low-High range, from 0 at
+// the low to 1 at the high, e.g. to screen for securities trading near their highs. It
+// returns 0 and false when Last, High or Low is missing (zero or below) or the range is
+// empty; a Last outside a stale range is clamped to it.
+func (s Security) PositionInDayRange() (float64, bool) {
+	if s.Last <= 0 || s.Low <= 0 || s.High <= s.Low {
+		return 0, false
+	}
+	return min(max((s.Last-s.Low)/(s.High-s.Low), 0), 1), true
+}
+
+// Spread returns Ask - Bid
+func (b Bond) Spread() (float64, bool) {
+	return spread(b.Bid, b.Ask)
+}
+
+// MidPrice returns the midpoint between Bid and Ask
+func (b Bond) MidPrice() (float64, bool) {
+	return midPrice(b.Bid, b.Ask)
+}
+
+// SpreadPercent returns the spread as a percentage of the mid price
+func (b Bond) SpreadPercent() (float64, bool) {
+	return spreadPercent(b.Bid, b.Ask)
+}
+
+// Spread returns Ask - Bid
+func (o Option) Spread() (float64, bool) {
+	return spread(o.Bid, o.Ask)
+}
+
+// MidPrice returns the midpoint between Bid and Ask
+func (o Option) MidPrice() (float64, bool) {
+	return midPrice(o.Bid, o.Ask)
+}
+
+// SpreadPercent returns the spread as a percentage of the mid price
+func (o Option) SpreadPercent() (float64, bool) {
+	return spreadPercent(o.Bid, o.Ask)
+}
+
+// Spread returns Ask - Bid
+func (f Future) Spread() (float64, bool) {
+	return spread(f.Bid, f.Ask)
+}
+
+// MidPrice returns the midpoint between Bid and Ask
+func (f Future) MidPrice() (float64, bool) {
+	return midPrice(f.Bid, f.Ask)
+}
+
+// SpreadPercent returns the spread as a percentage of the mid price
+func (f Future) SpreadPercent() (float64, bool) {
+	return spreadPercent(f.Bid, f.Ask)
+}
+
+// hasQuote reports whether both sides of the book are quoted
+func hasQuote(bid, ask float64) bool {
+	return bid > 0 && ask > 0
+}
+
+func spread(bid, ask float64) (float64, bool) {
+	if !hasQuote(bid, ask) {
+		return 0, false
+	}
+	return ask - bid, true
+}
+
+func midPrice(bid, ask float64) (float64, bool) {
+	if !hasQuote(bid, ask) {
+		return 0, false
+	}
+	return (ask + bid) / 2, true
+}
+
+func spreadPercent(bid, ask float64) (float64, bool) {
+	if !hasQuote(bid, ask) {
+		return 0, false
+	}
+	return (ask - bid) / ((ask + bid) / 2) * 100, true
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+// Resample aggregates daily candles into weekly ("W") or monthly ("M") ones; target
+// accepts the same spellings as GetHistory. Each bucket opens at its first open, closes
+// at its last close, spans the highest high and lowest low, sums the volume and is
+// stamped with the start of the period: Monday 00:00 for weeks and the 1st at 00:00
+// for months, in the location of the candle times. Periods without candles, such as
+// holiday weeks, are skipped, and a trailing incomplete period yields a partial bucket.
+// Resampling to "D" returns a copy of the data. The candles must be in chronological
+// order.
+func Resample(data *OHLCV, target string) (*OHLCV, error) {
+	length, err := data.Len()
+	if err != nil {
+		return nil, err
+	}
+	target, err = NormalizeResolution(target)
+	if err != nil {
+		return nil, err
+	}
+
+	var bucketStart func(time.Time) time.Time
+	switch target {
+	case "D":
+		bucketStart = func(t time.Time) time.Time { return t }
+	case "W":
+		bucketStart = func(t time.Time) time.Time {
+			daysSinceMonday := (int(t.Weekday()) + 6) % 7
+			return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, t.Location())
+		}
+	case "M":
+		bucketStart = func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		}
+	default:
+		return nil, NewBYMAError(ErrInvalidResolution.Code,
+			fmt.Sprintf("cannot resample daily candles to resolution %q", target))
+	}
+
+	result := &OHLCV{
+		Time:   []time.Time{},
+		Open:   []float64{},
+		High:   []float64{},
+		Low:    []float64{},
+		Close:  []float64{},
+		Volume: []int64{},
+	}
+	for i := range length {
+		if i > 0 && data.Time[i].Before(data.Time[i-1]) {
+			return nil, fmt.Errorf("cannot resample: candle %d (%s) is before the previous one",
+				i, data.Time[i].Format(time.RFC3339))
+		}
+
+		start := bucketStart(data.Time[i])
+		last := len(result.Time) - 1
+		if last < 0 || !start.Equal(result.Time[last]) {
+			result.Time = append(result.Time, start)
+			result.Open = append(result.Open, data.Open[i])
+			result.High = append(result.High, data.High[i])
+			result.Low = append(result.Low, data.Low[i])
+			result.Close = append(result.Close, data.Close[i])
+			result.Volume = append(result.Volume, data.Volume[i])
+			continue
+		}
+
+		result.High[last] = max(result.High[last], data.High[i])
+		result.Low[last] = min(result.Low[last], data.Low[i])
+		result.Close[last] = data.Close[i]
+		result.Volume[last] += data.Volume[i]
+	}
+	return result, nil
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// RetryFunc is called before each retry with the retry number (starting at 1), the
+// error that failed the previous attempt and the backoff about to be waited
+type RetryFunc func(attempt int, err error, nextDelay time.Duration)
+
+// RetryStats reports request and retry counts
+type RetryStats struct {
+	Requests int64 `json:"requests"` // Requests made, not counting retries
+	Retries  int64 `json:"retries"`  // Retries made after a failed attempt
+	Failures int64 `json:"failures"` // Requests that still failed after any retries
+}
+
+// RetryRate returns the average number of retries per request, or 0 if there were none
+func (s RetryStats) RetryRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Retries) / float64(s.Requests)
+}
+
+// retryCounters holds the cumulative counts behind RetryStats
+type retryCounters struct {
+	requests atomic.Int64
+	retries  atomic.Int64
+	failures atomic.Int64
+}
+
+// RetryStats returns the request, retry and failure counts since the client was
+// created or the last ResetRetryStats, including the requests made during session setup
+func (c *Client) RetryStats() RetryStats {
+	return RetryStats{
+		Requests: c.retryCounts.requests.Load(),
+		Retries:  c.retryCounts.retries.Load(),
+		Failures: c.retryCounts.failures.Load(),
+	}
+}
+
+// ResetRetryStats sets the request, retry and failure counts back to zero
+func (c *Client) ResetRetryStats() {
+	c.retryCounts.requests.Store(0)
+	c.retryCounts.retries.Store(0)
+	c.retryCounts.failures.Store(0)
+}
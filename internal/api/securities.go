@@ -8,28 +8,44 @@ import (
 	"github.com/carvalab/openbymadata/internal/utils"
 )
 
+// Security collections, reported in Security.Collection. They double as the cache
+// categories of the collections.
+const (
+	CollectionBluechips = "bluechips"
+	CollectionCedears   = "cedears"
+	CollectionGalpones  = "galpones"
+	CollectionEtfs      = "etfs"
+)
+
 // GetBluechips retrieves leading equity securities (blue chip stocks)
 func (c *Client) GetBluechips(ctx context.Context) ([]Security, error) {
-	return c.getSecurities(ctx, "leading-equity")
+	return c.getSecurities(ctx, "leading-equity", CollectionBluechips)
 }
 
 // GetGalpones retrieves general equity securities
 func (c *Client) GetGalpones(ctx context.Context) ([]Security, error) {
-	return c.getSecurities(ctx, "general-equity")
+	return c.getSecurities(ctx, "general-equity", CollectionGalpones)
 }
 
 // GetCedears retrieves CEDEAR securities
 func (c *Client) GetCedears(ctx context.Context) ([]Security, error) {
-	return c.getSecurities(ctx, "cedears")
+	return c.getSecurities(ctx, "cedears", CollectionCedears)
+}
+
+// GetEtfs retrieves exchange-traded funds
+func (c *Client) GetEtfs(ctx context.Context) ([]Security, error) {
+	return c.getSecurities(ctx, "etf", CollectionEtfs)
 }
 
 // getSecurities is a helper function to retrieve securities from different endpoints
-func (c *Client) getSecurities(ctx context.Context, endpoint string) ([]Security, error) {
-	// Use payload: excludeZeroPxAndQty=false, T1=true, others false
-	data := []byte(`{"excludeZeroPxAndQty":false,"T2":false,"T1":true,"T0":false,"Content-Type":"application/json"}`)
+func (c *Client) getSecurities(ctx context.Context, endpoint, collection string) ([]Security, error) {
+	data, err := quotePayload(ctx)
+	if err != nil {
+		return nil, err
+	}
 	url := c.buildURL(endpoint)
 
-	respData, err := c.post(url, data)
+	respData, err := c.post(ctx, url, data)
 	if err != nil {
 		return nil, err
 	}
@@ -50,29 +66,40 @@ func (c *Client) getSecurities(ctx context.Context, endpoint string) ([]Security
 	}
 
 	securities := make([]Security, 0, len(rawSecurities))
-	for _, raw := range rawSecurities {
+	var parseErrs []error
+	for i, raw := range rawSecurities {
+		r := utils.NewReader(raw, c.strict)
 		security := Security{
-			Symbol:        utils.GetString(raw, "symbol"),
-			Settlement:    utils.GetString(raw, "settlementType"),
-			BidSize:       utils.GetInt64(raw, "quantityBid"),
-			Bid:           utils.GetFloat64(raw, "bidPrice"),
-			Ask:           utils.GetFloat64(raw, "offerPrice"),
-			AskSize:       utils.GetInt64(raw, "quantityOffer"),
-			Last:          utils.GetFloat64(raw, "settlementPrice"),
-			Close:         utils.GetFloat64(raw, "closingPrice"),
-			Change:        utils.GetFloat64(raw, "imbalance"),
-			Open:          utils.GetFloat64(raw, "openingPrice"),
-			High:          utils.GetFloat64(raw, "tradingHighPrice"),
-			Low:           utils.GetFloat64(raw, "tradingLowPrice"),
-			PreviousClose: utils.GetFloat64(raw, "previousClosingPrice"),
-			Turnover:      utils.GetFloat64(raw, "volumeAmount"),
-			Volume:        utils.GetInt64(raw, "volume"),
-			Operations:    utils.GetInt64(raw, "numberOfOrders"),
-			DateTime:      utils.ParseTradeTime(utils.GetString(raw, "tradeHour")),
-			Group:         utils.GetString(raw, "securityType"),
+			Symbol:        r.RequiredString("symbol"),
+			Settlement:    r.String("settlementType"),
+			BidSize:       r.Int64("quantityBid"),
+			Bid:           r.Float64("bidPrice"),
+			Ask:           r.Float64("offerPrice"),
+			AskSize:       r.Int64("quantityOffer"),
+			Last:          r.Float64("settlementPrice"),
+			Close:         r.Float64("closingPrice"),
+			Change:        r.Float64("imbalance"),
+			Open:          r.Float64("openingPrice"),
+			High:          r.Float64("tradingHighPrice"),
+			Low:           r.Float64("tradingLowPrice"),
+			PreviousClose: r.Float64("previousClosingPrice"),
+			Turnover:      r.Float64("volumeAmount"),
+			Volume:        r.Int64("volume"),
+			Operations:    r.Int64("numberOfOrders"),
+			DateTime:      utils.ParseTradeTime(raw),
+			Group:         r.String("securityType"),
+			Panel:         r.String("panel"),
+			Collection:    collection,
+		}
+		if err := r.Err(); err != nil {
+			parseErrs = append(parseErrs, fmt.Errorf("item %d (%s): %w", i, security.Symbol, err))
+			continue
 		}
 		securities = append(securities, security)
 	}
+	if err := c.parseErrors(parseErrs); err != nil {
+		return nil, err
+	}
 
 	return securities, nil
 }
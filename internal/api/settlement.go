@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Settlement is the settlement term of the quotes requested for equities, CEDEARs,
+// ETFs and bonds
+type Settlement string
+
+// Settlement terms
+const (
+	SettlementT0 Settlement = "T0" // Contado inmediato (CI), settled the same day
+	SettlementT1 Settlement = "T1" // 24hs, BYMA's standard term and the default
+	SettlementT2 Settlement = "T2" // 48hs
+)
+
+// Valid reports whether s is one of the settlement terms BYMA quotes
+func (s Settlement) Valid() bool {
+	return s == SettlementT0 || s == SettlementT1 || s == SettlementT2
+}
+
+// settlementKey is the context key set by WithSettlement
+type settlementKey struct{}
+
+// WithSettlement returns a copy of ctx that requests quotes for the given settlement
+// term; its letters may be in any case
+func WithSettlement(ctx context.Context, settlement Settlement) context.Context {
+	settlement = Settlement(strings.ToUpper(strings.TrimSpace(string(settlement))))
+	return context.WithValue(ctx, settlementKey{}, settlement)
+}
+
+// SettlementFrom returns the settlement term requested by ctx, SettlementT1 when none
+func SettlementFrom(ctx context.Context) Settlement {
+	if settlement, ok := ctx.Value(settlementKey{}).(Settlement); ok {
+		return settlement
+	}
+	return SettlementT1
+}
+
+// InvalidSettlement returns the error for a settlement term BYMA doesn't quote
+func InvalidSettlement(settlement Settlement) *BYMAError {
+	return NewBYMAError(ErrInvalidSettlement.Code, fmt.Sprintf(
+		"invalid settlement %q: use T0, T1 or T2", settlement))
+}
+
+// quotePayload returns the body of a quote collection request, selecting the
+// settlement term requested by ctx
+func quotePayload(ctx context.Context) ([]byte, error) {
+	settlement := SettlementFrom(ctx)
+	if !settlement.Valid() {
+		return nil, InvalidSettlement(settlement)
+	}
+	return fmt.Appendf(nil, `{"excludeZeroPxAndQty":false,"T2":%t,"T1":%t,"T0":%t,"Content-Type":"application/json"}`,
+		settlement == SettlementT2, settlement == SettlementT1, settlement == SettlementT0), nil
+}
@@ -1,8 +1,23 @@
 package api
 
-import "time"
+import (
+	"encoding/json"
+	"slices"
+	"time"
 
-// Security represents a stock or equity security
+	"github.com/carvalab/openbymadata/internal/utils"
+)
+
+// Security represents a stock or equity security.
+//
+// Its JSON form (the snake_case tags below) is the library's stable public
+// representation, not the BYMA API payload shape: e.g. Group comes from the API's
+// "securityType" and Change from "imbalance". Like every time field the library
+// encodes, DateTime is an RFC 3339 string in UTC (e.g. "2024-03-15T20:00:00Z").
+//
+// In memory, DateTime (on Security as on Bond, Option and Future) is expressed in
+// the Buenos Aires time zone of the BYMA session, independently of the host zone.
+// It is the zero time when BYMA did not report a usable trade time.
 type Security struct {
 	Symbol        string    `json:"symbol"`
 	Settlement    string    `json:"settlement"`
@@ -22,6 +37,29 @@ type Security struct {
 	Operations    int64     `json:"operations"`
 	DateTime      time.Time `json:"datetime"`
 	Group         string    `json:"group"`
+	Panel         string    `json:"panel"`      // Board the instrument is listed on (e.g. general, SME), empty if not reported
+	Collection    string    `json:"collection"` // Collection it was fetched from: "bluechips", "cedears", "galpones" or "etfs"
+}
+
+// MarshalJSON encodes the security in its public JSON representation
+func (s Security) MarshalJSON() ([]byte, error) {
+	type security Security // avoids recursing into MarshalJSON
+	out := security(s)
+	out.DateTime = s.DateTime.UTC()
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes the security from its public JSON representation, expressing
+// DateTime in the time zone of the BYMA session like the securities BYMA returns
+func (s *Security) UnmarshalJSON(data []byte) error {
+	type security Security // avoids recursing into UnmarshalJSON
+	var in security
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*s = Security(in)
+	s.DateTime = inMarketLocation(s.DateTime)
+	return nil
 }
 
 // Bond represents a fixed income security
@@ -47,6 +85,29 @@ type Bond struct {
 	Expiration    time.Time `json:"expiration"`
 }
 
+// MarshalJSON encodes the bond with its time fields in UTC
+func (b Bond) MarshalJSON() ([]byte, error) {
+	type bond Bond // avoids recursing into MarshalJSON
+	out := bond(b)
+	out.DateTime = b.DateTime.UTC()
+	out.Expiration = b.Expiration.UTC()
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes the bond, expressing its time fields in the time zone of the
+// BYMA session
+func (b *Bond) UnmarshalJSON(data []byte) error {
+	type bond Bond // avoids recursing into UnmarshalJSON
+	var in bond
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*b = Bond(in)
+	b.DateTime = inMarketLocation(b.DateTime)
+	b.Expiration = inMarketLocation(b.Expiration)
+	return nil
+}
+
 // Option represents an options contract
 type Option struct {
 	Symbol          string    `json:"symbol"`
@@ -67,6 +128,38 @@ type Option struct {
 	DateTime        time.Time `json:"datetime"`
 	UnderlyingAsset string    `json:"underlying_asset"`
 	Expiration      time.Time `json:"expiration"`
+	Strike          float64   `json:"strike"` // Derived from Symbol, zero if it can't be parsed
+	Kind            string    `json:"kind"`   // OptionCall or OptionPut, empty if Symbol can't be parsed
+}
+
+// MarshalJSON encodes the option with its time fields in UTC
+func (o Option) MarshalJSON() ([]byte, error) {
+	type option Option // avoids recursing into MarshalJSON
+	out := option(o)
+	out.DateTime = o.DateTime.UTC()
+	out.Expiration = o.Expiration.UTC()
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes the option, expressing its time fields in the time zone of
+// the BYMA session
+func (o *Option) UnmarshalJSON(data []byte) error {
+	type option Option // avoids recursing into UnmarshalJSON
+	var in option
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*o = Option(in)
+	o.DateTime = inMarketLocation(o.DateTime)
+	o.Expiration = inMarketLocation(o.Expiration)
+	return nil
+}
+
+// OptionStrike is a row of an option chain: the calls and puts sharing a strike
+type OptionStrike struct {
+	Strike float64  `json:"strike"`
+	Calls  []Option `json:"calls"`
+	Puts   []Option `json:"puts"`
 }
 
 // Future represents a futures contract
@@ -89,6 +182,39 @@ type Future struct {
 	DateTime      time.Time `json:"datetime"`
 	Expiration    time.Time `json:"expiration"`
 	OpenInterest  int64     `json:"open_interest"`
+	Multiplier    float64   `json:"multiplier"` // Factor applied to BYMA's quoted prices, see QuotedPrice
+}
+
+// MarshalJSON encodes the future with its time fields in UTC
+func (f Future) MarshalJSON() ([]byte, error) {
+	type future Future // avoids recursing into MarshalJSON
+	out := future(f)
+	out.DateTime = f.DateTime.UTC()
+	out.Expiration = f.Expiration.UTC()
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes the future, expressing its time fields in the time zone of
+// the BYMA session
+func (f *Future) UnmarshalJSON(data []byte) error {
+	type future Future // avoids recursing into UnmarshalJSON
+	var in future
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*f = Future(in)
+	f.DateTime = inMarketLocation(f.DateTime)
+	f.Expiration = inMarketLocation(f.Expiration)
+	return nil
+}
+
+// QuotedPrice converts one of the future's prices (e.g. Last) back to the value BYMA
+// quoted, before Multiplier was applied
+func (f Future) QuotedPrice(price float64) float64 {
+	if f.Multiplier == 0 {
+		return price
+	}
+	return price / f.Multiplier
 }
 
 // Index represents a market index
@@ -102,6 +228,50 @@ type Index struct {
 	PreviousClose float64 `json:"previous_close"`
 }
 
+// MarketTime is BYMA's market time, as reported by GetMarketTime. Its times are
+// expressed in the Buenos Aires time zone of the BYMA session.
+type MarketTime struct {
+	ServerTime   time.Time `json:"server_time"`    // Local clock when checked; BYMA doesn't report its own
+	IsWorkingDay bool      `json:"is_working_day"` // Whether today is a trading day
+	IsOpen       bool      `json:"is_open"`        // Whether the regular session is open at ServerTime
+	SessionOpen  time.Time `json:"session_open"`   // Regular session start, zero when not a working day
+	SessionClose time.Time `json:"session_close"`  // Regular session end, zero when not a working day
+}
+
+// MarshalJSON encodes the market time with its time fields in UTC
+func (m MarketTime) MarshalJSON() ([]byte, error) {
+	type marketTime MarketTime // avoids recursing into MarshalJSON
+	out := marketTime(m)
+	out.ServerTime = m.ServerTime.UTC()
+	out.SessionOpen = m.SessionOpen.UTC()
+	out.SessionClose = m.SessionClose.UTC()
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes the market time, expressing its time fields in the time zone
+// of the BYMA session
+func (m *MarketTime) UnmarshalJSON(data []byte) error {
+	type marketTime MarketTime // avoids recursing into UnmarshalJSON
+	var in marketTime
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*m = MarketTime(in)
+	m.ServerTime = inMarketLocation(m.ServerTime)
+	m.SessionOpen = inMarketLocation(m.SessionOpen)
+	m.SessionClose = inMarketLocation(m.SessionClose)
+	return nil
+}
+
+// inMarketLocation expresses t in the time zone of the BYMA session, keeping the zero
+// time as is so it still compares equal to time.Time{}
+func inMarketLocation(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.In(utils.MarketLocation)
+}
+
 // MarketSummary represents market summary data
 type MarketSummary struct {
 	Symbol          string  `json:"symbol"`
@@ -112,6 +282,69 @@ type MarketSummary struct {
 	Operations      int64   `json:"operations"`
 }
 
+// DollarRate is an implied exchange rate, in ARS per USD, derived from the peso and
+// dollar lines of the same bond
+type DollarRate struct {
+	Name         string  `json:"name"`          // "MEP" (local dollars) or "CCL" (dollars abroad)
+	PesoSymbol   string  `json:"peso_symbol"`   // Bond line quoted in pesos, e.g. AL30
+	DollarSymbol string  `json:"dollar_symbol"` // Bond line quoted in dollars, e.g. AL30D or AL30C
+	PesoPrice    float64 `json:"peso_price"`
+	DollarPrice  float64 `json:"dollar_price"`
+	Rate         float64 `json:"rate"` // PesoPrice / DollarPrice
+}
+
+// ValidationReport represents the result of cross-checking market summary totals
+// against the sum of the per-instrument collections
+type ValidationReport struct {
+	ResumeTurnover      float64  `json:"resume_turnover"`      // Sum of TotalNegotiated in the market summary
+	ResumeVolume        int64    `json:"resume_volume"`        // Sum of Volume in the market summary
+	CollectionsTurnover float64  `json:"collections_turnover"` // Sum of Turnover across all collections
+	CollectionsVolume   int64    `json:"collections_volume"`   // Sum of Volume across all collections
+	TurnoverDiscrepancy float64  `json:"turnover_discrepancy"` // Relative difference (0..1)
+	VolumeDiscrepancy   float64  `json:"volume_discrepancy"`   // Relative difference (0..1)
+	Tolerance           float64  `json:"tolerance"`            // Maximum accepted relative difference
+	Consistent          bool     `json:"consistent"`           // Whether both discrepancies are within tolerance
+	Issues              []string `json:"issues,omitempty"`     // Human-readable description of each discrepancy
+}
+
+// MarketSnapshot holds every quote collection as it stood at a point in time, so
+// market states can be stored and compared later
+type MarketSnapshot struct {
+	Name           string          `json:"name"`
+	Timestamp      time.Time       `json:"timestamp"` // When the snapshot was taken
+	Bluechips      []Security      `json:"bluechips"`
+	Galpones       []Security      `json:"galpones"`
+	Cedears        []Security      `json:"cedears"`
+	Etfs           []Security      `json:"etfs"`
+	Bonds          []Bond          `json:"bonds"`
+	ShortTermBonds []Bond          `json:"short_term_bonds"`
+	CorporateBonds []Bond          `json:"corporate_bonds"`
+	Options        []Option        `json:"options"`
+	Futures        []Future        `json:"futures"`
+	Indices        []Index         `json:"indices"`
+	MarketSummary  []MarketSummary `json:"market_summary"`
+}
+
+// Clone returns a copy of the snapshot whose collections share no memory with it
+func (s *MarketSnapshot) Clone() *MarketSnapshot {
+	if s == nil {
+		return nil
+	}
+	clone := *s
+	clone.Bluechips = slices.Clone(s.Bluechips)
+	clone.Galpones = slices.Clone(s.Galpones)
+	clone.Cedears = slices.Clone(s.Cedears)
+	clone.Etfs = slices.Clone(s.Etfs)
+	clone.Bonds = slices.Clone(s.Bonds)
+	clone.ShortTermBonds = slices.Clone(s.ShortTermBonds)
+	clone.CorporateBonds = slices.Clone(s.CorporateBonds)
+	clone.Options = slices.Clone(s.Options)
+	clone.Futures = slices.Clone(s.Futures)
+	clone.Indices = slices.Clone(s.Indices)
+	clone.MarketSummary = slices.Clone(s.MarketSummary)
+	return &clone
+}
+
 // News represents market news
 type News struct {
 	Fecha       time.Time `json:"fecha"`
@@ -144,13 +377,36 @@ type HistoricalData struct {
 	Volume int64     `json:"volume"` // Trading volume
 }
 
+// MarshalJSON encodes the data point with Time in UTC, like the quote types
+func (h HistoricalData) MarshalJSON() ([]byte, error) {
+	type historicalData HistoricalData // avoids recursing into MarshalJSON
+	out := historicalData(h)
+	out.Time = h.Time.UTC()
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes the data point, expressing Time in the time zone of the BYMA
+// session
+func (h *HistoricalData) UnmarshalJSON(data []byte) error {
+	type historicalData HistoricalData // avoids recursing into UnmarshalJSON
+	var in historicalData
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*h = HistoricalData(in)
+	h.Time = inMarketLocation(h.Time)
+	return nil
+}
+
 // HistoryResponse represents the response structure for historical data
 type HistoryResponse struct {
-	Status string    `json:"s"` // Status: "ok" or "no_data"
+	Status string    `json:"s"` // Status: "ok", "no_data" or "error"
 	Time   []int64   `json:"t"` // Array of timestamps
 	Close  []float64 `json:"c"` // Array of closing prices
 	Open   []float64 `json:"o"` // Array of opening prices
 	High   []float64 `json:"h"` // Array of high prices
 	Low    []float64 `json:"l"` // Array of low prices
 	Volume []int64   `json:"v"` // Array of volumes
+
+	ErrMsg string `json:"errmsg,omitempty"` // Reason given with an "error" status
 }
@@ -1,21 +1,87 @@
 package cache
 
 import (
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/carvalab/openbymadata/internal/api"
+	"github.com/carvalab/openbymadata/internal/helpers"
 )
 
-// Cache provides 5-minute caching for BYMA data
+// DefaultDuration is the cache duration used when none is configured
+const DefaultDuration = 5 * time.Minute
+
+// DefaultDictionaryDuration is the cache duration of the translation dictionary, which
+// rarely changes, unless an override sets another
+const DefaultDictionaryDuration = 24 * time.Hour
+
+// Cache categories, used as keys for per-category duration overrides
+const (
+	CategoryBluechips        = api.CollectionBluechips
+	CategoryCedears          = api.CollectionCedears
+	CategoryGalpones         = api.CollectionGalpones
+	CategoryEtfs             = api.CollectionEtfs
+	CategoryBonds            = "bonds"
+	CategoryShortTermBonds   = "short_term_bonds"
+	CategoryCorporateBonds   = "corporate_bonds"
+	CategoryOptions          = "options"
+	CategoryFutures          = "futures"
+	CategoryIndices          = "indices"
+	CategoryMarketSummary    = "market_summary"
+	CategoryNews             = "news"
+	CategoryIncomeStatements = "income_statements"
+	CategoryHistory          = "history"
+	CategoryDictionary       = "dictionary"
+)
+
+// categories lists every cache category counted in Stats. Dictionary lookups are made
+// on behalf of other lookups and aren't counted.
+var categories = []string{
+	CategoryBluechips, CategoryCedears, CategoryGalpones, CategoryEtfs,
+	CategoryBonds, CategoryShortTermBonds, CategoryCorporateBonds,
+	CategoryOptions, CategoryFutures, CategoryIndices, CategoryMarketSummary,
+	CategoryNews, CategoryIncomeStatements, CategoryHistory,
+}
+
+// State describes the result of a cache lookup
+type State int
+
+const (
+	Miss  State = iota // Not cached, or too old to be served
+	Fresh              // Within its cache duration
+	Stale              // Expired, but within the grace window: usable while it is refreshed
+)
+
+// Options configures a Cache
+type Options struct {
+	Duration  time.Duration                   // Default duration for every category (DefaultDuration when zero)
+	Overrides map[string]time.Duration        // Per-category durations; non-positive values are ignored
+	Dir       string                          // Directory to persist the cache to (optional)
+	Grace     time.Duration                   // How long expired data may still be served as Stale
+	NotFound  time.Duration                   // How long symbols confirmed absent are remembered (disabled when zero)
+	OnLookup  func(category string, hit bool) // Called after every counted lookup (optional)
+}
+
+// Cache provides time-based caching for BYMA data
 type Cache struct {
-	mu       sync.RWMutex
-	duration time.Duration
+	mu        sync.RWMutex
+	duration  time.Duration
+	overrides map[string]time.Duration
+	dir       string        // Persistence directory, empty when disk persistence is disabled
+	grace     time.Duration // Stale-while-revalidate window after expiry
+
+	// Hit/miss counters per category. The map is filled in New and never modified,
+	// so it is read without locking.
+	stats    map[string]*counters
+	onLookup func(category string, hit bool)
 
 	// Collections cache
 	bluechips      *cachedSecurities
 	cedears        *cachedSecurities
 	galpones       *cachedSecurities
+	etfs           *cachedSecurities
 	bonds          *cachedBonds
 	shortBonds     *cachedBonds
 	corporateBonds *cachedBonds
@@ -24,20 +90,38 @@ type Cache struct {
 	indices        *cachedIndices
 	marketSummary  *cachedMarketSummary
 	news           *cachedNews
+	dictionary     *cachedDictionary
 
 	// Income statements cache (per symbol)
 	incomeStatements map[string]*cachedIncomeStatements
+
+	// Historical data cache (per symbol|resolution|from|to key)
+	history map[string]*cachedHistory
+
+	// Negative cache: normalized symbols not found in any security collection, with
+	// the time they were recorded. Reset whenever a security collection is updated.
+	missingSecurities map[string]time.Time
+	notFoundDuration  time.Duration
+
+	// Disk persistence, see disk.go: version counts updates and is guarded by mu;
+	// diskMu serializes file operations and guards written, the version of the last
+	// capture applied to each file
+	version uint64
+	diskMu  sync.Mutex
+	written map[string]uint64
 }
 
 // Cached data structures
 type cachedSecurities struct {
 	data      []api.Security
 	timestamp time.Time
+	index     helpers.SymbolIndex // Built on first indexed lookup, see SecurityIndex
 }
 
 type cachedBonds struct {
 	data      []api.Bond
 	timestamp time.Time
+	index     helpers.SymbolIndex // Built on first indexed lookup, see BondIndex
 }
 
 type cachedOptions struct {
@@ -65,340 +149,691 @@ type cachedNews struct {
 	timestamp time.Time
 }
 
+type cachedDictionary struct {
+	data      map[string]string
+	timestamp time.Time
+}
+
 type cachedIncomeStatements struct {
 	data      []api.IncomeStatement
 	timestamp time.Time
 }
 
-// New creates a new cache with 5-minute duration
-func New() *Cache {
-	return &Cache{
-		duration:         5 * time.Minute,
-		incomeStatements: make(map[string]*cachedIncomeStatements),
+type cachedHistory struct {
+	data      *api.OHLCV
+	timestamp time.Time
+	duration  time.Duration // Extends the category duration when longer, see SetHistory
+}
+
+// New creates a new cache. When opts.Dir is set, the cache is persisted to that
+// directory and fresh data saved by a previous process is reloaded.
+func New(opts Options) *Cache {
+	duration := opts.Duration
+	if duration <= 0 {
+		duration = DefaultDuration
+	}
+
+	c := &Cache{
+		duration:          duration,
+		grace:             max(opts.Grace, 0),
+		overrides:         make(map[string]time.Duration),
+		incomeStatements:  make(map[string]*cachedIncomeStatements),
+		history:           make(map[string]*cachedHistory),
+		missingSecurities: make(map[string]time.Time),
+		notFoundDuration:  max(opts.NotFound, 0),
+		written:           make(map[string]uint64),
+		stats:             make(map[string]*counters, len(categories)),
+		onLookup:          opts.OnLookup,
+	}
+	for _, category := range categories {
+		c.stats[category] = &counters{}
+	}
+	c.overrides[CategoryDictionary] = DefaultDictionaryDuration
+	for category, d := range opts.Overrides {
+		if d > 0 {
+			c.overrides[category] = d
+		}
+	}
+
+	if opts.Dir != "" {
+		if err := os.MkdirAll(opts.Dir, 0o755); err == nil {
+			c.dir = opts.Dir
+			removeStaleTemps(c.dir)
+			c.load()
+		}
+	}
+	return c
+}
+
+// durationFor returns the cache duration for a category
+func (c *Cache) durationFor(category string) time.Duration {
+	if d, ok := c.overrides[category]; ok {
+		return d
 	}
+	return c.duration
+}
+
+// isFresh checks if cached data of the given category is still valid
+func (c *Cache) isFresh(category string, timestamp time.Time) bool {
+	return time.Since(timestamp) < c.durationFor(category)
+}
+
+// state classifies cached data of the given category by age. Data older than a
+// positive maxAge is a Miss, whatever the category's duration.
+func (c *Cache) state(category string, timestamp time.Time, maxAge time.Duration) State {
+	return c.stateWithin(c.durationFor(category), timestamp, maxAge)
 }
 
-// isFresh checks if cached data is still valid
-func (c *Cache) isFresh(timestamp time.Time) bool {
-	return time.Since(timestamp) < c.duration
+// stateWithin classifies cached data by age like state, for data fresh for duration
+func (c *Cache) stateWithin(duration time.Duration, timestamp time.Time, maxAge time.Duration) State {
+	age := time.Since(timestamp)
+	switch {
+	case maxAge > 0 && age > maxAge:
+		return Miss
+	case age < duration:
+		return Fresh
+	case age < duration+c.grace:
+		return Stale
+	default:
+		return Miss
+	}
 }
 
-// GetBluechips returns cached data or nil if not available/expired
-func (c *Cache) GetBluechips() ([]api.Security, bool) {
+// GetBluechips returns cached data, or nil and Miss if not available, expired or
+// older than a positive maxAge
+func (c *Cache) GetBluechips(maxAge time.Duration) ([]api.Security, State) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if c.bluechips != nil && c.isFresh(c.bluechips.timestamp) {
-		return c.bluechips.data, true
+	if c.bluechips != nil {
+		if state := c.state(CategoryBluechips, c.bluechips.timestamp, maxAge); state != Miss {
+			c.record(CategoryBluechips, true)
+			return c.bluechips.data, state
+		}
 	}
-	return nil, false
+	c.record(CategoryBluechips, false)
+	return nil, Miss
 }
 
 // SetBluechips stores data in cache
 func (c *Cache) SetBluechips(data []api.Security) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.bluechips = &cachedSecurities{
-		data:      data,
-		timestamp: time.Now(),
-	}
+	c.update(func() []pendingWrite {
+		c.bluechips = &cachedSecurities{
+			data:      data,
+			timestamp: time.Now(),
+		}
+		c.missingSecurities = make(map[string]time.Time)
+		return c.capture(CategoryBluechips, c.bluechips.timestamp, data)
+	})
 }
 
-// GetCedears returns cached data or nil if not available/expired
-func (c *Cache) GetCedears() ([]api.Security, bool) {
+// GetCedears returns cached data, or nil and Miss if not available, expired or
+// older than a positive maxAge
+func (c *Cache) GetCedears(maxAge time.Duration) ([]api.Security, State) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if c.cedears != nil && c.isFresh(c.cedears.timestamp) {
-		return c.cedears.data, true
+	if c.cedears != nil {
+		if state := c.state(CategoryCedears, c.cedears.timestamp, maxAge); state != Miss {
+			c.record(CategoryCedears, true)
+			return c.cedears.data, state
+		}
 	}
-	return nil, false
+	c.record(CategoryCedears, false)
+	return nil, Miss
 }
 
 // SetCedears stores data in cache
 func (c *Cache) SetCedears(data []api.Security) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.cedears = &cachedSecurities{
-		data:      data,
-		timestamp: time.Now(),
-	}
+	c.update(func() []pendingWrite {
+		c.cedears = &cachedSecurities{
+			data:      data,
+			timestamp: time.Now(),
+		}
+		c.missingSecurities = make(map[string]time.Time)
+		return c.capture(CategoryCedears, c.cedears.timestamp, data)
+	})
 }
 
-// GetGalpones returns cached data or nil if not available/expired
-func (c *Cache) GetGalpones() ([]api.Security, bool) {
+// GetGalpones returns cached data, or nil and Miss if not available, expired or
+// older than a positive maxAge
+func (c *Cache) GetGalpones(maxAge time.Duration) ([]api.Security, State) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if c.galpones != nil && c.isFresh(c.galpones.timestamp) {
-		return c.galpones.data, true
+	if c.galpones != nil {
+		if state := c.state(CategoryGalpones, c.galpones.timestamp, maxAge); state != Miss {
+			c.record(CategoryGalpones, true)
+			return c.galpones.data, state
+		}
 	}
-	return nil, false
+	c.record(CategoryGalpones, false)
+	return nil, Miss
 }
 
 // SetGalpones stores data in cache
 func (c *Cache) SetGalpones(data []api.Security) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.update(func() []pendingWrite {
+		c.galpones = &cachedSecurities{
+			data:      data,
+			timestamp: time.Now(),
+		}
+		c.missingSecurities = make(map[string]time.Time)
+		return c.capture(CategoryGalpones, c.galpones.timestamp, data)
+	})
+}
 
-	c.galpones = &cachedSecurities{
-		data:      data,
-		timestamp: time.Now(),
+// GetEtfs returns cached data, or nil and Miss if not available, expired or
+// older than a positive maxAge
+func (c *Cache) GetEtfs(maxAge time.Duration) ([]api.Security, State) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.etfs != nil {
+		if state := c.state(CategoryEtfs, c.etfs.timestamp, maxAge); state != Miss {
+			c.record(CategoryEtfs, true)
+			return c.etfs.data, state
+		}
 	}
+	c.record(CategoryEtfs, false)
+	return nil, Miss
 }
 
-// GetBonds returns cached data or nil if not available/expired
-func (c *Cache) GetBonds() ([]api.Bond, bool) {
+// SetEtfs stores data in cache
+func (c *Cache) SetEtfs(data []api.Security) {
+	c.update(func() []pendingWrite {
+		c.etfs = &cachedSecurities{
+			data:      data,
+			timestamp: time.Now(),
+		}
+		c.missingSecurities = make(map[string]time.Time)
+		return c.capture(CategoryEtfs, c.etfs.timestamp, data)
+	})
+}
+
+// GetBonds returns cached data, or nil and Miss if not available, expired or
+// older than a positive maxAge
+func (c *Cache) GetBonds(maxAge time.Duration) ([]api.Bond, State) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if c.bonds != nil && c.isFresh(c.bonds.timestamp) {
-		return c.bonds.data, true
+	if c.bonds != nil {
+		if state := c.state(CategoryBonds, c.bonds.timestamp, maxAge); state != Miss {
+			c.record(CategoryBonds, true)
+			return c.bonds.data, state
+		}
 	}
-	return nil, false
+	c.record(CategoryBonds, false)
+	return nil, Miss
 }
 
 // SetBonds stores data in cache
 func (c *Cache) SetBonds(data []api.Bond) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.bonds = &cachedBonds{
-		data:      data,
-		timestamp: time.Now(),
-	}
+	c.update(func() []pendingWrite {
+		c.bonds = &cachedBonds{
+			data:      data,
+			timestamp: time.Now(),
+		}
+		return c.capture(CategoryBonds, c.bonds.timestamp, data)
+	})
 }
 
-// GetShortTermBonds returns cached data or nil if not available/expired
-func (c *Cache) GetShortTermBonds() ([]api.Bond, bool) {
+// GetShortTermBonds returns cached data, or nil and Miss if not available, expired or
+// older than a positive maxAge
+func (c *Cache) GetShortTermBonds(maxAge time.Duration) ([]api.Bond, State) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if c.shortBonds != nil && c.isFresh(c.shortBonds.timestamp) {
-		return c.shortBonds.data, true
+	if c.shortBonds != nil {
+		if state := c.state(CategoryShortTermBonds, c.shortBonds.timestamp, maxAge); state != Miss {
+			c.record(CategoryShortTermBonds, true)
+			return c.shortBonds.data, state
+		}
 	}
-	return nil, false
+	c.record(CategoryShortTermBonds, false)
+	return nil, Miss
 }
 
 // SetShortTermBonds stores data in cache
 func (c *Cache) SetShortTermBonds(data []api.Bond) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.shortBonds = &cachedBonds{
-		data:      data,
-		timestamp: time.Now(),
-	}
+	c.update(func() []pendingWrite {
+		c.shortBonds = &cachedBonds{
+			data:      data,
+			timestamp: time.Now(),
+		}
+		return c.capture(CategoryShortTermBonds, c.shortBonds.timestamp, data)
+	})
 }
 
-// GetCorporateBonds returns cached data or nil if not available/expired
-func (c *Cache) GetCorporateBonds() ([]api.Bond, bool) {
+// GetCorporateBonds returns cached data, or nil and Miss if not available, expired or
+// older than a positive maxAge
+func (c *Cache) GetCorporateBonds(maxAge time.Duration) ([]api.Bond, State) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if c.corporateBonds != nil && c.isFresh(c.corporateBonds.timestamp) {
-		return c.corporateBonds.data, true
+	if c.corporateBonds != nil {
+		if state := c.state(CategoryCorporateBonds, c.corporateBonds.timestamp, maxAge); state != Miss {
+			c.record(CategoryCorporateBonds, true)
+			return c.corporateBonds.data, state
+		}
 	}
-	return nil, false
+	c.record(CategoryCorporateBonds, false)
+	return nil, Miss
 }
 
 // SetCorporateBonds stores data in cache
 func (c *Cache) SetCorporateBonds(data []api.Bond) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.corporateBonds = &cachedBonds{
-		data:      data,
-		timestamp: time.Now(),
-	}
+	c.update(func() []pendingWrite {
+		c.corporateBonds = &cachedBonds{
+			data:      data,
+			timestamp: time.Now(),
+		}
+		return c.capture(CategoryCorporateBonds, c.corporateBonds.timestamp, data)
+	})
 }
 
-// GetOptions returns cached data or nil if not available/expired
-func (c *Cache) GetOptions() ([]api.Option, bool) {
+// GetOptions returns cached data, or nil and Miss if not available, expired or
+// older than a positive maxAge
+func (c *Cache) GetOptions(maxAge time.Duration) ([]api.Option, State) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if c.options != nil && c.isFresh(c.options.timestamp) {
-		return c.options.data, true
+	if c.options != nil {
+		if state := c.state(CategoryOptions, c.options.timestamp, maxAge); state != Miss {
+			c.record(CategoryOptions, true)
+			return c.options.data, state
+		}
 	}
-	return nil, false
+	c.record(CategoryOptions, false)
+	return nil, Miss
 }
 
 // SetOptions stores data in cache
 func (c *Cache) SetOptions(data []api.Option) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.options = &cachedOptions{
-		data:      data,
-		timestamp: time.Now(),
-	}
+	c.update(func() []pendingWrite {
+		c.options = &cachedOptions{
+			data:      data,
+			timestamp: time.Now(),
+		}
+		return c.capture(CategoryOptions, c.options.timestamp, data)
+	})
 }
 
-// GetFutures returns cached data or nil if not available/expired
-func (c *Cache) GetFutures() ([]api.Future, bool) {
+// GetFutures returns cached data, or nil and Miss if not available, expired or
+// older than a positive maxAge
+func (c *Cache) GetFutures(maxAge time.Duration) ([]api.Future, State) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if c.futures != nil && c.isFresh(c.futures.timestamp) {
-		return c.futures.data, true
+	if c.futures != nil {
+		if state := c.state(CategoryFutures, c.futures.timestamp, maxAge); state != Miss {
+			c.record(CategoryFutures, true)
+			return c.futures.data, state
+		}
 	}
-	return nil, false
+	c.record(CategoryFutures, false)
+	return nil, Miss
 }
 
 // SetFutures stores data in cache
 func (c *Cache) SetFutures(data []api.Future) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.futures = &cachedFutures{
-		data:      data,
-		timestamp: time.Now(),
-	}
+	c.update(func() []pendingWrite {
+		c.futures = &cachedFutures{
+			data:      data,
+			timestamp: time.Now(),
+		}
+		return c.capture(CategoryFutures, c.futures.timestamp, data)
+	})
 }
 
-// GetIndices returns cached data or nil if not available/expired
-func (c *Cache) GetIndices() ([]api.Index, bool) {
+// GetIndices returns cached data, or nil and Miss if not available, expired or
+// older than a positive maxAge
+func (c *Cache) GetIndices(maxAge time.Duration) ([]api.Index, State) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if c.indices != nil && c.isFresh(c.indices.timestamp) {
-		return c.indices.data, true
+	if c.indices != nil {
+		if state := c.state(CategoryIndices, c.indices.timestamp, maxAge); state != Miss {
+			c.record(CategoryIndices, true)
+			return c.indices.data, state
+		}
 	}
-	return nil, false
+	c.record(CategoryIndices, false)
+	return nil, Miss
 }
 
 // SetIndices stores data in cache
 func (c *Cache) SetIndices(data []api.Index) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.indices = &cachedIndices{
-		data:      data,
-		timestamp: time.Now(),
-	}
+	c.update(func() []pendingWrite {
+		c.indices = &cachedIndices{
+			data:      data,
+			timestamp: time.Now(),
+		}
+		return c.capture(CategoryIndices, c.indices.timestamp, data)
+	})
 }
 
-// GetMarketSummary returns cached data or nil if not available/expired
-func (c *Cache) GetMarketSummary() ([]api.MarketSummary, bool) {
+// GetMarketSummary returns cached data, or nil and Miss if not available, expired or
+// older than a positive maxAge
+func (c *Cache) GetMarketSummary(maxAge time.Duration) ([]api.MarketSummary, State) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if c.marketSummary != nil && c.isFresh(c.marketSummary.timestamp) {
-		return c.marketSummary.data, true
+	if c.marketSummary != nil {
+		if state := c.state(CategoryMarketSummary, c.marketSummary.timestamp, maxAge); state != Miss {
+			c.record(CategoryMarketSummary, true)
+			return c.marketSummary.data, state
+		}
 	}
-	return nil, false
+	c.record(CategoryMarketSummary, false)
+	return nil, Miss
 }
 
 // SetMarketSummary stores data in cache
 func (c *Cache) SetMarketSummary(data []api.MarketSummary) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.marketSummary = &cachedMarketSummary{
-		data:      data,
-		timestamp: time.Now(),
-	}
+	c.update(func() []pendingWrite {
+		c.marketSummary = &cachedMarketSummary{
+			data:      data,
+			timestamp: time.Now(),
+		}
+		return c.capture(CategoryMarketSummary, c.marketSummary.timestamp, data)
+	})
 }
 
-// GetNews returns cached data or nil if not available/expired
-func (c *Cache) GetNews() ([]api.News, bool) {
+// GetNews returns cached data, or nil and Miss if not available, expired or
+// older than a positive maxAge
+func (c *Cache) GetNews(maxAge time.Duration) ([]api.News, State) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if c.news != nil && c.isFresh(c.news.timestamp) {
-		return c.news.data, true
+	if c.news != nil {
+		if state := c.state(CategoryNews, c.news.timestamp, maxAge); state != Miss {
+			c.record(CategoryNews, true)
+			return c.news.data, state
+		}
 	}
-	return nil, false
+	c.record(CategoryNews, false)
+	return nil, Miss
 }
 
 // SetNews stores data in cache
 func (c *Cache) SetNews(data []api.News) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.update(func() []pendingWrite {
+		c.news = &cachedNews{
+			data:      data,
+			timestamp: time.Now(),
+		}
+		return c.capture(CategoryNews, c.news.timestamp, data)
+	})
+}
 
-	c.news = &cachedNews{
-		data:      data,
-		timestamp: time.Now(),
+// GetDictionary returns the cached translation dictionary, or nil and Miss if not
+// available/expired. Unlike the other lookups it isn't counted in Stats.
+func (c *Cache) GetDictionary() (map[string]string, State) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.dictionary != nil {
+		if state := c.state(CategoryDictionary, c.dictionary.timestamp, 0); state != Miss {
+			return c.dictionary.data, state
+		}
 	}
+	return nil, Miss
 }
 
-// GetIncomeStatement returns cached data or nil if not available/expired
-func (c *Cache) GetIncomeStatement(ticker string) ([]api.IncomeStatement, bool) {
+// SetDictionary stores the translation dictionary in cache
+func (c *Cache) SetDictionary(data map[string]string) {
+	c.update(func() []pendingWrite {
+		c.dictionary = &cachedDictionary{
+			data:      data,
+			timestamp: time.Now(),
+		}
+		return c.capture(CategoryDictionary, c.dictionary.timestamp, data)
+	})
+}
+
+// GetIncomeStatement returns cached data, or nil and Miss if not available, expired or
+// older than a positive maxAge
+func (c *Cache) GetIncomeStatement(ticker string, maxAge time.Duration) ([]api.IncomeStatement, State) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if cached, exists := c.incomeStatements[ticker]; exists && c.isFresh(cached.timestamp) {
-		return cached.data, true
+	if cached, exists := c.incomeStatements[ticker]; exists {
+		if state := c.state(CategoryIncomeStatements, cached.timestamp, maxAge); state != Miss {
+			c.record(CategoryIncomeStatements, true)
+			return cached.data, state
+		}
 	}
-	return nil, false
+	c.record(CategoryIncomeStatements, false)
+	return nil, Miss
 }
 
 // SetIncomeStatement stores data in cache
 func (c *Cache) SetIncomeStatement(ticker string, data []api.IncomeStatement) {
+	c.update(func() []pendingWrite {
+		cached := &cachedIncomeStatements{
+			data:      data,
+			timestamp: time.Now(),
+		}
+		c.incomeStatements[ticker] = cached
+		return c.captureKey(CategoryIncomeStatements, ticker, cached.timestamp, 0, data)
+	})
+}
+
+// GetHistory returns cached data for a history key, or nil and Miss if not available,
+// expired or older than a positive maxAge
+func (c *Cache) GetHistory(key string, maxAge time.Duration) (*api.OHLCV, State) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if cached, exists := c.history[key]; exists {
+		if state := c.stateWithin(c.historyDuration(cached.duration), cached.timestamp, maxAge); state != Miss {
+			c.record(CategoryHistory, true)
+			return cached.data, state
+		}
+	}
+	c.record(CategoryHistory, false)
+	return nil, Miss
+}
+
+// SetHistory stores data in cache under a history key. A duration longer than the
+// category's keeps this entry fresh for that long instead, e.g. for data that can't
+// change before the next trading session; zero uses the category's duration.
+func (c *Cache) SetHistory(key string, data *api.OHLCV, duration time.Duration) {
+	c.update(func() []pendingWrite {
+		var writes []pendingWrite
+		for cachedKey, cached := range c.history {
+			if c.stateWithin(c.historyDuration(cached.duration), cached.timestamp, 0) == Miss {
+				delete(c.history, cachedKey)
+				writes = append(writes, c.captureRemoval(CategoryHistory+"/"+cachedKey, c.keyedPath(CategoryHistory, cachedKey))...)
+			}
+		}
+		cached := &cachedHistory{
+			data:      data,
+			timestamp: time.Now(),
+			duration:  duration,
+		}
+		c.history[key] = cached
+		return append(writes, c.captureKey(CategoryHistory, key, cached.timestamp, duration, data)...)
+	})
+}
+
+// historyDuration returns how long a history entry stored with duration stays fresh
+func (c *Cache) historyDuration(duration time.Duration) time.Duration {
+	return max(duration, c.durationFor(CategoryHistory))
+}
+
+// IsMissingSecurity reports whether symbol was recently confirmed absent from every
+// security collection. Symbols are compared in normalized form.
+func (c *Cache) IsMissingSecurity(symbol string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	recorded, exists := c.missingSecurities[symbol]
+	return exists && time.Since(recorded) < c.notFoundDuration
+}
+
+// SetMissingSecurity remembers that symbol is absent from every security collection.
+// It is a no-op when negative caching is disabled.
+func (c *Cache) SetMissingSecurity(symbol string) {
+	if c.notFoundDuration <= 0 {
+		return
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.incomeStatements[ticker] = &cachedIncomeStatements{
-		data:      data,
-		timestamp: time.Now(),
+	now := time.Now()
+	for missing, recorded := range c.missingSecurities {
+		if now.Sub(recorded) >= c.notFoundDuration {
+			delete(c.missingSecurities, missing)
+		}
 	}
+	c.missingSecurities[symbol] = now
 }
 
-// GetInfo returns information about cached data
+// GetInfo returns information about cached data. Each cached category maps to its
+// count, timestamp, age and freshness; income statements map to the number of cached
+// tickers and a per-ticker breakdown under "tickers", and history maps to the number
+// of cached ranges and a per-key breakdown under "keys".
 func (c *Cache) GetInfo() map[string]interface{} {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	info := make(map[string]interface{})
 
-	if c.bluechips != nil {
-		info["bluechips"] = map[string]interface{}{
-			"count":     len(c.bluechips.data),
-			"timestamp": c.bluechips.timestamp,
-			"age":       time.Since(c.bluechips.timestamp),
-			"fresh":     c.isFresh(c.bluechips.timestamp),
-		}
+	add := func(category string, count int, timestamp time.Time) {
+		info[category] = c.entryInfo(category, count, timestamp)
 	}
 
+	if c.bluechips != nil {
+		add(CategoryBluechips, len(c.bluechips.data), c.bluechips.timestamp)
+	}
 	if c.cedears != nil {
-		info["cedears"] = map[string]interface{}{
-			"count":     len(c.cedears.data),
-			"timestamp": c.cedears.timestamp,
-			"age":       time.Since(c.cedears.timestamp),
-			"fresh":     c.isFresh(c.cedears.timestamp),
+		add(CategoryCedears, len(c.cedears.data), c.cedears.timestamp)
+	}
+	if c.galpones != nil {
+		add(CategoryGalpones, len(c.galpones.data), c.galpones.timestamp)
+	}
+	if c.etfs != nil {
+		add(CategoryEtfs, len(c.etfs.data), c.etfs.timestamp)
+	}
+	if c.bonds != nil {
+		add(CategoryBonds, len(c.bonds.data), c.bonds.timestamp)
+	}
+	if c.shortBonds != nil {
+		add(CategoryShortTermBonds, len(c.shortBonds.data), c.shortBonds.timestamp)
+	}
+	if c.corporateBonds != nil {
+		add(CategoryCorporateBonds, len(c.corporateBonds.data), c.corporateBonds.timestamp)
+	}
+	if c.options != nil {
+		add(CategoryOptions, len(c.options.data), c.options.timestamp)
+	}
+	if c.futures != nil {
+		add(CategoryFutures, len(c.futures.data), c.futures.timestamp)
+	}
+	if c.indices != nil {
+		add(CategoryIndices, len(c.indices.data), c.indices.timestamp)
+	}
+	if c.marketSummary != nil {
+		add(CategoryMarketSummary, len(c.marketSummary.data), c.marketSummary.timestamp)
+	}
+	if c.news != nil {
+		add(CategoryNews, len(c.news.data), c.news.timestamp)
+	}
+	if c.dictionary != nil {
+		add(CategoryDictionary, len(c.dictionary.data), c.dictionary.timestamp)
+	}
+
+	if len(c.incomeStatements) > 0 {
+		tickers := make(map[string]interface{}, len(c.incomeStatements))
+		for ticker, cached := range c.incomeStatements {
+			tickers[ticker] = c.entryInfo(CategoryIncomeStatements, len(cached.data), cached.timestamp)
+		}
+		info[CategoryIncomeStatements] = map[string]interface{}{
+			"count":   len(c.incomeStatements),
+			"tickers": tickers,
 		}
 	}
 
-	if c.galpones != nil {
-		info["galpones"] = map[string]interface{}{
-			"count":     len(c.galpones.data),
-			"timestamp": c.galpones.timestamp,
-			"age":       time.Since(c.galpones.timestamp),
-			"fresh":     c.isFresh(c.galpones.timestamp),
+	if len(c.history) > 0 {
+		keys := make(map[string]interface{}, len(c.history))
+		for key, cached := range c.history {
+			entry := c.entryInfo(CategoryHistory, len(cached.data.Time), cached.timestamp)
+			entry["fresh"] = time.Since(cached.timestamp) < c.historyDuration(cached.duration)
+			keys[key] = entry
+		}
+		info[CategoryHistory] = map[string]interface{}{
+			"count": len(c.history),
+			"keys":  keys,
 		}
 	}
 
 	return info
 }
 
-// Clear clears all cached data
+// entryInfo describes a single cached entry
+func (c *Cache) entryInfo(category string, count int, timestamp time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"count":     count,
+		"timestamp": timestamp,
+		"age":       time.Since(timestamp),
+		"fresh":     c.isFresh(category, timestamp),
+	}
+}
+
+// ExpireIndices drops the cached indices, so the next read fetches them again
+func (c *Cache) ExpireIndices() {
+	c.update(func() []pendingWrite {
+		c.indices = nil
+		return c.captureRemoval(CategoryIndices, c.filePath(CategoryIndices))
+	})
+}
+
+// Clear clears all cached data, removing its files when the cache is persisted
 func (c *Cache) Clear() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	// Listed before taking the lock, which also covers the keys stored in the meantime
+	var keyed []string
+	if c.dir != "" {
+		for _, category := range []string{CategoryIncomeStatements, CategoryHistory} {
+			files, _ := filepath.Glob(filepath.Join(c.keyedDir(category), "*.json"))
+			keyed = append(keyed, files...)
+		}
+	}
 
-	c.bluechips = nil
-	c.cedears = nil
-	c.galpones = nil
-	c.bonds = nil
-	c.shortBonds = nil
-	c.corporateBonds = nil
-	c.options = nil
-	c.futures = nil
-	c.indices = nil
-	c.marketSummary = nil
-	c.news = nil
-	c.incomeStatements = make(map[string]*cachedIncomeStatements)
+	c.update(func() []pendingWrite {
+		var removals []pendingWrite
+		for ticker := range c.incomeStatements {
+			removals = append(removals, c.captureRemoval(CategoryIncomeStatements+"/"+ticker, c.keyedPath(CategoryIncomeStatements, ticker))...)
+		}
+		for key := range c.history {
+			removals = append(removals, c.captureRemoval(CategoryHistory+"/"+key, c.keyedPath(CategoryHistory, key))...)
+		}
+		for _, file := range keyed {
+			removals = append(removals, c.captureRemoval(file, file)...)
+		}
+		for _, category := range categories {
+			removals = append(removals, c.captureRemoval(category, c.filePath(category))...)
+		}
+		removals = append(removals, c.captureRemoval(CategoryDictionary, c.filePath(CategoryDictionary))...)
+
+		c.bluechips = nil
+		c.cedears = nil
+		c.galpones = nil
+		c.etfs = nil
+		c.bonds = nil
+		c.shortBonds = nil
+		c.corporateBonds = nil
+		c.options = nil
+		c.futures = nil
+		c.indices = nil
+		c.marketSummary = nil
+		c.news = nil
+		c.dictionary = nil
+		c.incomeStatements = make(map[string]*cachedIncomeStatements)
+		c.history = make(map[string]*cachedHistory)
+		c.missingSecurities = make(map[string]time.Time)
+		return removals
+	})
 }
@@ -0,0 +1,380 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/carvalab/openbymadata/internal/api"
+)
+
+// Disk persistence: when a directory is configured, every cached category is also
+// written to <dir>/<category>.json together with its timestamp, and reloaded by New.
+// Income statements and history are written one file per key, to
+// <dir>/<category>/<key>.json, so an update only rewrites its own key.
+//
+// Updates capture what to write while holding c.mu and write it after releasing the
+// lock, so readers never wait on disk I/O. Each capture is stamped with the cache
+// version at the time; a file is never replaced by a capture older than the last one
+// written to it, so concurrent writes can't leave older data on disk.
+//
+// Writes go to a temporary file, synced and renamed over the previous one, so an
+// interrupted write leaves the previous file intact; New removes the temporary files
+// such writes leave behind. Persistence on update is best-effort: write failures
+// leave the in-memory cache working, and Flush, which the client also calls on Close,
+// writes everything again and reports them. Unreadable, corrupt or expired files are
+// treated as cache misses.
+
+// diskEntry is the on-disk representation of a cached category
+type diskEntry[T any] struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Duration  time.Duration `json:"duration,omitempty"` // Per-entry duration, see SetHistory
+	Data      T             `json:"data"`
+}
+
+// pendingWrite is a file change captured under c.mu, applied by write once the lock
+// is released
+type pendingWrite struct {
+	name    string      // Category, or category/key, for errors
+	path    string      // File to write or remove
+	version uint64      // Cache version when captured
+	entry   interface{} // diskEntry to encode, nil to remove the file
+}
+
+// filePath returns the file used to persist a category
+func (c *Cache) filePath(category string) string {
+	return filepath.Join(c.dir, category+".json")
+}
+
+// keyedDir returns the directory holding the per-key files of a category
+func (c *Cache) keyedDir(category string) string {
+	return filepath.Join(c.dir, category)
+}
+
+// keyedPath returns the file used to persist one key of a per-key category. Keys are
+// escaped so any key is a valid file name.
+func (c *Cache) keyedPath(category, key string) string {
+	return filepath.Join(c.keyedDir(category), url.QueryEscape(key)+".json")
+}
+
+// update applies change under c.mu, bumping the cache version, and then writes the
+// files it returns
+func (c *Cache) update(change func() []pendingWrite) {
+	c.mu.Lock()
+	c.version++
+	writes := change()
+	c.mu.Unlock()
+
+	_ = c.write(writes) // Best-effort, see Flush
+}
+
+// capture returns the write of a category's data. Callers must hold c.mu.
+func (c *Cache) capture(category string, timestamp time.Time, data interface{}) []pendingWrite {
+	if c.dir == "" {
+		return nil
+	}
+	return []pendingWrite{{
+		name:    category,
+		path:    c.filePath(category),
+		version: c.version,
+		entry:   diskEntry[interface{}]{Timestamp: timestamp, Data: data},
+	}}
+}
+
+// captureKey returns the write of one key of a per-key category. Callers must hold
+// c.mu.
+func (c *Cache) captureKey(category, key string, timestamp time.Time, duration time.Duration, data interface{}) []pendingWrite {
+	if c.dir == "" {
+		return nil
+	}
+	return []pendingWrite{{
+		name:    category + "/" + key,
+		path:    c.keyedPath(category, key),
+		version: c.version,
+		entry:   diskEntry[interface{}]{Timestamp: timestamp, Duration: duration, Data: data},
+	}}
+}
+
+// captureRemoval returns the removal of a persisted file. Callers must hold c.mu.
+func (c *Cache) captureRemoval(name, path string) []pendingWrite {
+	if c.dir == "" {
+		return nil
+	}
+	return []pendingWrite{{name: name, path: path, version: c.version}}
+}
+
+// write applies captured writes in order and returns the errors of those that failed.
+// Encoding happens before taking c.diskMu, which only serializes the file operations.
+func (c *Cache) write(writes []pendingWrite) error {
+	var errs []error
+	for _, w := range writes {
+		var payload []byte
+		if w.entry != nil {
+			var err error
+			if payload, err = json.Marshal(w.entry); err != nil {
+				errs = append(errs, fmt.Errorf("failed to persist %s: %w", w.name, err))
+				continue
+			}
+		}
+		if err := c.writeFile(w, payload); err != nil {
+			errs = append(errs, fmt.Errorf("failed to persist %s: %w", w.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// writeFile writes or removes the file of a captured write, unless a newer capture
+// was already applied to it
+func (c *Cache) writeFile(w pendingWrite, payload []byte) error {
+	c.diskMu.Lock()
+	defer c.diskMu.Unlock()
+
+	if w.version < c.written[w.path] {
+		return nil
+	}
+	c.written[w.path] = w.version
+
+	if payload == nil {
+		if err := os.Remove(w.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(w.path), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(w.path, payload)
+}
+
+// Flush writes every cached category to disk, replacing the files of earlier writes,
+// and returns the errors of the writes that failed. Without a directory it does nothing.
+func (c *Cache) Flush() error {
+	if c.dir == "" {
+		return nil
+	}
+
+	c.mu.RLock()
+	var writes []pendingWrite
+	add := func(category string, timestamp time.Time, data interface{}) {
+		writes = append(writes, c.capture(category, timestamp, data)...)
+	}
+	if c.bluechips != nil {
+		add(CategoryBluechips, c.bluechips.timestamp, c.bluechips.data)
+	}
+	if c.cedears != nil {
+		add(CategoryCedears, c.cedears.timestamp, c.cedears.data)
+	}
+	if c.galpones != nil {
+		add(CategoryGalpones, c.galpones.timestamp, c.galpones.data)
+	}
+	if c.etfs != nil {
+		add(CategoryEtfs, c.etfs.timestamp, c.etfs.data)
+	}
+	if c.bonds != nil {
+		add(CategoryBonds, c.bonds.timestamp, c.bonds.data)
+	}
+	if c.shortBonds != nil {
+		add(CategoryShortTermBonds, c.shortBonds.timestamp, c.shortBonds.data)
+	}
+	if c.corporateBonds != nil {
+		add(CategoryCorporateBonds, c.corporateBonds.timestamp, c.corporateBonds.data)
+	}
+	if c.options != nil {
+		add(CategoryOptions, c.options.timestamp, c.options.data)
+	}
+	if c.futures != nil {
+		add(CategoryFutures, c.futures.timestamp, c.futures.data)
+	}
+	if c.indices != nil {
+		add(CategoryIndices, c.indices.timestamp, c.indices.data)
+	}
+	if c.marketSummary != nil {
+		add(CategoryMarketSummary, c.marketSummary.timestamp, c.marketSummary.data)
+	}
+	if c.news != nil {
+		add(CategoryNews, c.news.timestamp, c.news.data)
+	}
+	if c.dictionary != nil {
+		add(CategoryDictionary, c.dictionary.timestamp, c.dictionary.data)
+	}
+	for ticker, cached := range c.incomeStatements {
+		writes = append(writes, c.captureKey(CategoryIncomeStatements, ticker, cached.timestamp, 0, cached.data)...)
+	}
+	for key, cached := range c.history {
+		writes = append(writes, c.captureKey(CategoryHistory, key, cached.timestamp, cached.duration, cached.data)...)
+	}
+	c.mu.RUnlock()
+
+	return c.write(writes)
+}
+
+// writeFileAtomic writes data to a temporary file and renames it over path, so
+// readers never observe a partially written file. The data is synced before the
+// rename and the directory after it, so after a crash or power loss path holds either
+// the previous or the new contents.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+tempSuffix+"*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// syncDir makes a rename in dir durable. Windows can't sync directories, and renames
+// there are durable once they return.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// tempSuffix marks the temporary files of writeFileAtomic, <name>.tmp<random>
+const tempSuffix = ".tmp"
+
+// staleTempAge is how old a temporary file must be for removeStaleTemps to delete it.
+// Younger files may belong to a write in progress by another client sharing the
+// directory.
+const staleTempAge = time.Minute
+
+// removeStaleTemps deletes the temporary files left in dir and its per-key
+// directories by writes that were interrupted before their rename, e.g. by a crash
+func removeStaleTemps(dir string) {
+	temps, _ := filepath.Glob(filepath.Join(dir, "*.json"+tempSuffix+"*"))
+	for _, category := range []string{CategoryIncomeStatements, CategoryHistory} {
+		keyed, _ := filepath.Glob(filepath.Join(dir, category, "*.json"+tempSuffix+"*"))
+		temps = append(temps, keyed...)
+	}
+	for _, temp := range temps {
+		if info, err := os.Stat(temp); err == nil && time.Since(info.ModTime()) > staleTempAge {
+			_ = os.Remove(temp)
+		}
+	}
+}
+
+// readEntry loads a category from disk, reporting false if the file is missing,
+// corrupt or no longer fresh
+func readEntry[T any](c *Cache, category string) (T, time.Time, bool) {
+	var entry diskEntry[T]
+
+	payload, err := os.ReadFile(c.filePath(category))
+	if err != nil {
+		return entry.Data, time.Time{}, false
+	}
+	if err := json.Unmarshal(payload, &entry); err != nil || !c.isFresh(category, entry.Timestamp) {
+		return entry.Data, time.Time{}, false
+	}
+	return entry.Data, entry.Timestamp, true
+}
+
+// load reloads every fresh category persisted in the cache directory
+func (c *Cache) load() {
+	if data, ts, ok := readEntry[[]api.Security](c, CategoryBluechips); ok {
+		c.bluechips = &cachedSecurities{data: data, timestamp: ts}
+	}
+	if data, ts, ok := readEntry[[]api.Security](c, CategoryCedears); ok {
+		c.cedears = &cachedSecurities{data: data, timestamp: ts}
+	}
+	if data, ts, ok := readEntry[[]api.Security](c, CategoryGalpones); ok {
+		c.galpones = &cachedSecurities{data: data, timestamp: ts}
+	}
+	if data, ts, ok := readEntry[[]api.Security](c, CategoryEtfs); ok {
+		c.etfs = &cachedSecurities{data: data, timestamp: ts}
+	}
+	if data, ts, ok := readEntry[[]api.Bond](c, CategoryBonds); ok {
+		c.bonds = &cachedBonds{data: data, timestamp: ts}
+	}
+	if data, ts, ok := readEntry[[]api.Bond](c, CategoryShortTermBonds); ok {
+		c.shortBonds = &cachedBonds{data: data, timestamp: ts}
+	}
+	if data, ts, ok := readEntry[[]api.Bond](c, CategoryCorporateBonds); ok {
+		c.corporateBonds = &cachedBonds{data: data, timestamp: ts}
+	}
+	if data, ts, ok := readEntry[[]api.Option](c, CategoryOptions); ok {
+		c.options = &cachedOptions{data: data, timestamp: ts}
+	}
+	if data, ts, ok := readEntry[[]api.Future](c, CategoryFutures); ok {
+		c.futures = &cachedFutures{data: data, timestamp: ts}
+	}
+	if data, ts, ok := readEntry[[]api.Index](c, CategoryIndices); ok {
+		c.indices = &cachedIndices{data: data, timestamp: ts}
+	}
+	if data, ts, ok := readEntry[[]api.MarketSummary](c, CategoryMarketSummary); ok {
+		c.marketSummary = &cachedMarketSummary{data: data, timestamp: ts}
+	}
+	if data, ts, ok := readEntry[[]api.News](c, CategoryNews); ok {
+		c.news = &cachedNews{data: data, timestamp: ts}
+	}
+	if data, ts, ok := readEntry[map[string]string](c, CategoryDictionary); ok && data != nil {
+		c.dictionary = &cachedDictionary{data: data, timestamp: ts}
+	}
+
+	// Expired per-key files are removed, as nothing else would replace them
+	for ticker, entry := range readKeyedEntries[[]api.IncomeStatement](c, CategoryIncomeStatements) {
+		if c.isFresh(CategoryIncomeStatements, entry.Timestamp) {
+			c.incomeStatements[ticker] = &cachedIncomeStatements{data: entry.Data, timestamp: entry.Timestamp}
+		} else {
+			_ = os.Remove(c.keyedPath(CategoryIncomeStatements, ticker))
+		}
+	}
+	for key, entry := range readKeyedEntries[*api.OHLCV](c, CategoryHistory) {
+		if entry.Data != nil && time.Since(entry.Timestamp) < c.historyDuration(entry.Duration) {
+			c.history[key] = &cachedHistory{data: entry.Data, timestamp: entry.Timestamp, duration: entry.Duration}
+		} else {
+			_ = os.Remove(c.keyedPath(CategoryHistory, key))
+		}
+	}
+}
+
+// readKeyedEntries loads the per-key files of a category, skipping the files that
+// are unreadable or corrupt
+func readKeyedEntries[T any](c *Cache, category string) map[string]diskEntry[T] {
+	files, _ := filepath.Glob(filepath.Join(c.keyedDir(category), "*.json"))
+	entries := make(map[string]diskEntry[T], len(files))
+	for _, file := range files {
+		key, err := url.QueryUnescape(strings.TrimSuffix(filepath.Base(file), ".json"))
+		if err != nil {
+			continue
+		}
+		payload, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var entry diskEntry[T]
+		if err := json.Unmarshal(payload, &entry); err != nil {
+			continue
+		}
+		entries[key] = entry
+	}
+	return entries
+}
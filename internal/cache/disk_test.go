@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/carvalab/openbymadata/internal/api"
+)
+
+func TestFlush_FailedWriteKeepsPreviousFile(t *testing.T) {
+	dir := t.TempDir()
+	store := New(Options{Dir: dir})
+	file := filepath.Join(dir, CategoryBluechips+".json")
+
+	store.SetBluechips([]api.Security{{Symbol: "GGAL", Last: 4500}})
+	require.NoError(t, store.Flush())
+	previous, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	// NaN can't be encoded as JSON, so the write fails before replacing the file
+	store.SetBluechips([]api.Security{{Symbol: "GGAL", Last: math.NaN()}})
+	err = store.Flush()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), CategoryBluechips)
+
+	current, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, previous, current)
+	temps, err := filepath.Glob(file + ".tmp*")
+	require.NoError(t, err)
+	assert.Empty(t, temps)
+
+	reloaded, state := New(Options{Dir: dir}).GetBluechips(0)
+	require.Equal(t, Fresh, state)
+	assert.Equal(t, 4500.0, reloaded[0].Last)
+}
+
+func TestWrite_OlderCaptureNeverReplacesNewer(t *testing.T) {
+	dir := t.TempDir()
+	store := New(Options{Dir: dir})
+	file := filepath.Join(dir, CategoryBluechips+".json")
+
+	// Captures taken in order but written out of order, as by concurrent updates
+	store.mu.Lock()
+	store.version++
+	older := store.capture(CategoryBluechips, time.Now(), []api.Security{{Symbol: "OLD"}})
+	store.version++
+	newer := store.capture(CategoryBluechips, time.Now(), []api.Security{{Symbol: "NEW"}})
+	store.mu.Unlock()
+
+	require.NoError(t, store.write(newer))
+	require.NoError(t, store.write(older))
+	payload, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(payload), "NEW")
+	assert.NotContains(t, string(payload), "OLD")
+}
+
+func TestPersist_PerKeyFiles(t *testing.T) {
+	dir := t.TempDir()
+	store := New(Options{Dir: dir})
+	data := &api.OHLCV{Time: []time.Time{time.Now()}, Close: []float64{4500}}
+
+	store.SetHistory("GGAL 24HS|D|1|2", data, 0)
+	store.SetHistory("YPFD 24HS|D|1|2", data, 0)
+	store.SetIncomeStatement("GGAL", []api.IncomeStatement{{}})
+
+	files, err := filepath.Glob(filepath.Join(dir, CategoryHistory, "*.json"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2, "one file per history key")
+
+	reloaded := New(Options{Dir: dir})
+	_, state := reloaded.GetHistory("GGAL 24HS|D|1|2", 0)
+	assert.Equal(t, Fresh, state)
+	_, state = reloaded.GetHistory("YPFD 24HS|D|1|2", 0)
+	assert.Equal(t, Fresh, state)
+	_, state = reloaded.GetIncomeStatement("GGAL", 0)
+	assert.Equal(t, Fresh, state)
+
+	reloaded.Clear()
+	for _, category := range []string{CategoryHistory, CategoryIncomeStatements} {
+		files, err := filepath.Glob(filepath.Join(dir, category, "*.json"))
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	}
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"github.com/carvalab/openbymadata/internal/api"
+	"github.com/carvalab/openbymadata/internal/helpers"
+)
+
+// SecurityIndex returns a symbol index over data when data is a security collection
+// currently held by the cache, building it on first use, or nil otherwise. The index
+// belongs to the cached collection, so it is dropped whenever the collection is
+// refreshed, expired or cleared and never describes different data.
+func (c *Cache) SecurityIndex(data []api.Security) helpers.SymbolIndex {
+	c.mu.RLock()
+	cached := c.securitiesHolding(data)
+	var index helpers.SymbolIndex
+	if cached != nil {
+		index = cached.index
+	}
+	c.mu.RUnlock()
+	if cached == nil || index != nil {
+		return index
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached.index == nil {
+		cached.index = helpers.IndexSecurities(cached.data)
+	}
+	return cached.index
+}
+
+// BondIndex returns a symbol index over data when data is a bond collection currently
+// held by the cache, building it on first use, or nil otherwise, like SecurityIndex
+func (c *Cache) BondIndex(data []api.Bond) helpers.SymbolIndex {
+	c.mu.RLock()
+	cached := c.bondsHolding(data)
+	var index helpers.SymbolIndex
+	if cached != nil {
+		index = cached.index
+	}
+	c.mu.RUnlock()
+	if cached == nil || index != nil {
+		return index
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached.index == nil {
+		cached.index = helpers.IndexBonds(cached.data)
+	}
+	return cached.index
+}
+
+// securitiesHolding returns the cached security collection whose data is data, if any
+func (c *Cache) securitiesHolding(data []api.Security) *cachedSecurities {
+	for _, cached := range []*cachedSecurities{c.bluechips, c.cedears, c.galpones, c.etfs} {
+		if cached != nil && sameSlice(cached.data, data) {
+			return cached
+		}
+	}
+	return nil
+}
+
+// bondsHolding returns the cached bond collection whose data is data, if any
+func (c *Cache) bondsHolding(data []api.Bond) *cachedBonds {
+	for _, cached := range []*cachedBonds{c.bonds, c.shortBonds, c.corporateBonds} {
+		if cached != nil && sameSlice(cached.data, data) {
+			return cached
+		}
+	}
+	return nil
+}
+
+// sameSlice reports whether a and b are the same non-empty slice of a backing array
+func sameSlice[T any](a, b []T) bool {
+	return len(a) > 0 && len(a) == len(b) && &a[0] == &b[0]
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/carvalab/openbymadata/internal/api"
+)
+
+// SnapshotStore keeps named market snapshots. Unlike the cache, snapshots never expire
+// and are not cleared with it. When a directory is configured, each snapshot is also
+// written to <dir>/<name>.json, so it can be read back by later processes.
+type SnapshotStore struct {
+	mu        sync.RWMutex
+	dir       string // Persistence directory, empty when snapshots are kept in memory only
+	snapshots map[string]*api.MarketSnapshot
+}
+
+// NewSnapshotStore creates a snapshot store, persisted to dir unless it is empty
+func NewSnapshotStore(dir string) *SnapshotStore {
+	return &SnapshotStore{dir: dir, snapshots: make(map[string]*api.MarketSnapshot)}
+}
+
+// ValidSnapshotName reports whether name can name a snapshot: letters, digits, '-',
+// '_' and '.', not starting with '.', so it is also a safe file name
+func ValidSnapshotName(name string) bool {
+	if name == "" || name[0] == '.' {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Save stores a snapshot under its name, replacing any previous one. Unlike cache
+// persistence, failing to write the snapshot to disk is reported, and the snapshot
+// is then not stored.
+func (s *SnapshotStore) Save(snapshot *api.MarketSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.dir != "" {
+		payload, err := json.Marshal(snapshot)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(s.dir, 0o755); err != nil {
+			return err
+		}
+		if err := writeFileAtomic(filepath.Join(s.dir, snapshot.Name+".json"), payload); err != nil {
+			return err
+		}
+	}
+	s.snapshots[snapshot.Name] = snapshot
+	return nil
+}
+
+// Get returns the snapshot stored under name, reading it from disk when it was saved
+// by another process. It reports false when there is no such snapshot.
+func (s *SnapshotStore) Get(name string) (*api.MarketSnapshot, bool, error) {
+	s.mu.RLock()
+	snapshot, found := s.snapshots[name]
+	s.mu.RUnlock()
+	if found || s.dir == "" {
+		return snapshot, found, nil
+	}
+
+	payload, err := os.ReadFile(filepath.Join(s.dir, name+".json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	snapshot = &api.MarketSnapshot{}
+	if err := json.Unmarshal(payload, snapshot); err != nil {
+		return nil, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Keep a snapshot saved meanwhile by this process
+	if saved, exists := s.snapshots[name]; exists {
+		return saved, true, nil
+	}
+	s.snapshots[name] = snapshot
+	return snapshot, true, nil
+}
@@ -0,0 +1,63 @@
+package cache
+
+import "sync/atomic"
+
+// counters holds the hit and miss counts of a category
+type counters struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// CategoryStats reports cache hits and misses for a single category
+type CategoryStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// Stats reports cache hits and misses, in total and per category
+type Stats struct {
+	Hits       int64                    `json:"hits"`
+	Misses     int64                    `json:"misses"`
+	Categories map[string]CategoryStats `json:"categories"`
+}
+
+// HitRate returns the fraction of lookups served from cache, or 0 if there were none
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// record counts a lookup of category as a hit or a miss and reports it to OnLookup
+func (c *Cache) record(category string, hit bool) {
+	if hit {
+		c.stats[category].hits.Add(1)
+	} else {
+		c.stats[category].misses.Add(1)
+	}
+	if c.onLookup != nil {
+		c.onLookup(category, hit)
+	}
+}
+
+// Stats returns the hit and miss counts since the cache was created or last reset
+func (c *Cache) Stats() Stats {
+	stats := Stats{Categories: make(map[string]CategoryStats, len(c.stats))}
+	for category, counts := range c.stats {
+		categoryStats := CategoryStats{Hits: counts.hits.Load(), Misses: counts.misses.Load()}
+		stats.Categories[category] = categoryStats
+		stats.Hits += categoryStats.Hits
+		stats.Misses += categoryStats.Misses
+	}
+	return stats
+}
+
+// ResetStats sets every hit and miss count back to zero
+func (c *Cache) ResetStats() {
+	for _, counts := range c.stats {
+		counts.hits.Store(0)
+		counts.misses.Store(0)
+	}
+}
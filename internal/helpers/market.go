@@ -0,0 +1,133 @@
+package helpers
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/carvalab/openbymadata/internal/api"
+)
+
+// ValidateMarketResume cross-checks the market summary totals against the turnover
+// and volume summed over the per-instrument collections
+func ValidateMarketResume(summaries []api.MarketSummary, securities []api.Security, bonds []api.Bond,
+	options []api.Option, futures []api.Future, tolerance float64) *api.ValidationReport {
+	report := &api.ValidationReport{Tolerance: tolerance}
+
+	report.ResumeTurnover, report.ResumeVolume = MarketTotals(summaries)
+
+	for _, security := range securities {
+		report.CollectionsTurnover += security.Turnover
+		report.CollectionsVolume += security.Volume
+	}
+	for _, bond := range bonds {
+		report.CollectionsTurnover += bond.Turnover
+		report.CollectionsVolume += bond.Volume
+	}
+	for _, option := range options {
+		report.CollectionsTurnover += option.Turnover
+		report.CollectionsVolume += option.Volume
+	}
+	for _, future := range futures {
+		report.CollectionsTurnover += future.Turnover
+		report.CollectionsVolume += future.Volume
+	}
+
+	report.TurnoverDiscrepancy = relativeDifference(report.ResumeTurnover, report.CollectionsTurnover)
+	report.VolumeDiscrepancy = relativeDifference(float64(report.ResumeVolume), float64(report.CollectionsVolume))
+
+	if report.TurnoverDiscrepancy > tolerance {
+		report.Issues = append(report.Issues, fmt.Sprintf(
+			"turnover mismatch: market summary %.2f vs collections %.2f (%.1f%%)",
+			report.ResumeTurnover, report.CollectionsTurnover, report.TurnoverDiscrepancy*100))
+	}
+	if report.VolumeDiscrepancy > tolerance {
+		report.Issues = append(report.Issues, fmt.Sprintf(
+			"volume mismatch: market summary %d vs collections %d (%.1f%%)",
+			report.ResumeVolume, report.CollectionsVolume, report.VolumeDiscrepancy*100))
+	}
+	report.Consistent = len(report.Issues) == 0
+
+	return report
+}
+
+// FindMarketSummary returns the summary entry of an asset type (e.g. "ACCIONES"),
+// matched case-insensitively against its asset type or symbol
+func FindMarketSummary(assetType string, summaries []api.MarketSummary) (*api.MarketSummary, error) {
+	if strings.TrimSpace(assetType) == "" {
+		return nil, tickerNotFound("market summary for", assetType)
+	}
+	for i := range summaries {
+		if strings.EqualFold(summaries[i].AssetType, assetType) || SymbolsEqual(summaries[i].Symbol, assetType) {
+			return copyOf(summaries[i]), nil
+		}
+	}
+	return nil, tickerNotFound("market summary for", assetType)
+}
+
+// MarketTotals sums the turnover and volume of every market summary entry
+func MarketTotals(summaries []api.MarketSummary) (turnover float64, volume int64) {
+	for _, summary := range summaries {
+		turnover += summary.TotalNegotiated
+		volume += summary.Volume
+	}
+	return turnover, volume
+}
+
+// dollarRatePairs lists the bonds used to derive implied dollar rates: the peso line
+// of each bond is paired with its "D" (MEP) and "C" (CCL) dollar lines
+var dollarRatePairs = []struct{ name, suffix string }{
+	{"MEP", "D"},
+	{"CCL", "C"},
+}
+
+// dollarRateBonds are the liquid sovereign bonds whose peso/dollar lines are used
+var dollarRateBonds = []string{"AL30", "GD30"}
+
+// ImpliedDollarRates derives the MEP and CCL rates from the peso and dollar lines of
+// the reference bonds. Pairs missing a line or a positive price are skipped.
+func ImpliedDollarRates(bonds []api.Bond) []api.DollarRate {
+	prices := make(map[string]float64, len(bonds))
+	for _, bond := range bonds {
+		if price := bondPrice(bond); price > 0 {
+			prices[NormalizeSymbol(bond.Symbol)] = price
+		}
+	}
+
+	rates := make([]api.DollarRate, 0, len(dollarRatePairs)*len(dollarRateBonds))
+	for _, pair := range dollarRatePairs {
+		for _, peso := range dollarRateBonds {
+			dollar := peso + pair.suffix
+			pesoPrice, dollarPrice := prices[peso], prices[dollar]
+			if pesoPrice <= 0 || dollarPrice <= 0 {
+				continue
+			}
+			rates = append(rates, api.DollarRate{
+				Name:         pair.name,
+				PesoSymbol:   peso,
+				DollarSymbol: dollar,
+				PesoPrice:    pesoPrice,
+				DollarPrice:  dollarPrice,
+				Rate:         pesoPrice / dollarPrice,
+			})
+		}
+	}
+	return rates
+}
+
+// bondPrice returns the last traded price, falling back to the close
+func bondPrice(bond api.Bond) float64 {
+	if bond.Last > 0 {
+		return bond.Last
+	}
+	return bond.Close
+}
+
+// relativeDifference returns |a-b| relative to the larger magnitude, or 0 when both are 0
+func relativeDifference(a, b float64) float64 {
+	largest := math.Max(math.Abs(a), math.Abs(b))
+	if largest == 0 {
+		return 0
+	}
+	return math.Abs(a-b) / largest
+}
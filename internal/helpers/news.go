@@ -0,0 +1,33 @@
+package helpers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/carvalab/openbymadata/internal/api"
+)
+
+// FilterNews returns the news published by emisor (matched case-insensitively against
+// Titulo, ignoring surrounding spaces) between from and to, both included. An empty
+// emisor matches every issuer and a zero bound leaves that side of the range open.
+// News whose date could not be parsed are dropped whenever a bound is given.
+func FilterNews(news []api.News, emisor string, from, to time.Time) []api.News {
+	emisor = strings.TrimSpace(emisor)
+	results := []api.News{}
+	for _, item := range news {
+		if emisor != "" && !strings.EqualFold(strings.TrimSpace(item.Titulo), emisor) {
+			continue
+		}
+		if (!from.IsZero() || !to.IsZero()) && item.Fecha.IsZero() {
+			continue
+		}
+		if !from.IsZero() && item.Fecha.Before(from) {
+			continue
+		}
+		if !to.IsZero() && item.Fecha.After(to) {
+			continue
+		}
+		results = append(results, item)
+	}
+	return results
+}
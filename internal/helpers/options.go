@@ -0,0 +1,104 @@
+package helpers
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/carvalab/openbymadata/internal/api"
+)
+
+// FilterOptionsByUnderlying returns the options on the given underlying, matched
+// case-insensitively against either the reported underlying symbol (e.g. "GGAL") or
+// the three-letter root the option symbols start with (e.g. "GFG")
+func FilterOptionsByUnderlying(underlying string, options []api.Option) []api.Option {
+	underlying = NormalizeSymbol(underlying)
+	results := []api.Option{}
+	for _, option := range options {
+		if SymbolsEqual(option.UnderlyingAsset, underlying) || optionRoot(option.Symbol) == underlying {
+			results = append(results, option)
+		}
+	}
+	return results
+}
+
+// optionRoot returns the three-letter underlying root of a BYMA option symbol
+func optionRoot(symbol string) string {
+	symbol = NormalizeSymbol(symbol)
+	if _, _, ok := api.ParseOptionSymbol(symbol); !ok {
+		return ""
+	}
+	return symbol[:3]
+}
+
+// FilterOptionsByExpiration returns the options expiring between from and to, both
+// dates included. A zero bound leaves that side of the range open; options without a
+// known expiration are dropped whenever a bound is given.
+func FilterOptionsByExpiration(options []api.Option, from, to time.Time) []api.Option {
+	results := []api.Option{}
+	for _, option := range options {
+		expiration := dateOf(option.Expiration)
+		if (!from.IsZero() || !to.IsZero()) && option.Expiration.IsZero() {
+			continue
+		}
+		if !from.IsZero() && expiration.Before(dateOf(from)) {
+			continue
+		}
+		if !to.IsZero() && expiration.After(dateOf(to)) {
+			continue
+		}
+		results = append(results, option)
+	}
+	return results
+}
+
+// dateOf truncates a time to its calendar date, keeping the date as written in its zone
+func dateOf(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// FilterOptionsByKind returns the options of the given kind (api.OptionCall or
+// api.OptionPut, case-insensitive)
+func FilterOptionsByKind(options []api.Option, kind string) []api.Option {
+	results := []api.Option{}
+	for _, option := range options {
+		if option.Kind != "" && strings.EqualFold(option.Kind, kind) {
+			results = append(results, option)
+		}
+	}
+	return results
+}
+
+// BuildOptionChain groups options by strike, ordered from the lowest strike up, with
+// the calls and puts of each strike in collection order. Options without a parsed
+// strike and kind are left out.
+func BuildOptionChain(options []api.Option) []api.OptionStrike {
+	byStrike := make(map[float64]*api.OptionStrike)
+	for _, option := range options {
+		kind, strike := option.Kind, option.Strike
+		if kind == "" || strike <= 0 {
+			continue
+		}
+		row, exists := byStrike[strike]
+		if !exists {
+			row = &api.OptionStrike{Strike: strike, Calls: []api.Option{}, Puts: []api.Option{}}
+			byStrike[strike] = row
+		}
+		if kind == api.OptionCall {
+			row.Calls = append(row.Calls, option)
+		} else {
+			row.Puts = append(row.Puts, option)
+		}
+	}
+
+	chain := make([]api.OptionStrike, 0, len(byStrike))
+	for _, row := range byStrike {
+		chain = append(chain, *row)
+	}
+	slices.SortFunc(chain, func(a, b api.OptionStrike) int {
+		return cmp.Compare(a.Strike, b.Strike)
+	})
+	return chain
+}
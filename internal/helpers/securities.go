@@ -2,202 +2,443 @@ package helpers
 
 import (
 	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/carvalab/openbymadata/internal/api"
 )
 
-// FindSecurityBySymbol searches for a security in multiple collections
-func FindSecurityBySymbol(symbol string, bluechips, cedears, galpones []api.Security) (*api.Security, error) {
-	// Search in blue chips first
-	for i := range bluechips {
-		if bluechips[i].Symbol == symbol {
-			return &bluechips[i], nil
-		}
-	}
+// NormalizeSymbol returns the canonical form of a ticker symbol: trimmed and uppercased
+func NormalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
 
-	// Search in CEDEARs
-	for i := range cedears {
-		if cedears[i].Symbol == symbol {
-			return &cedears[i], nil
-		}
-	}
+// SymbolsEqual reports whether two ticker symbols match, ignoring case and surrounding whitespace
+func SymbolsEqual(a, b string) bool {
+	return NormalizeSymbol(a) == NormalizeSymbol(b)
+}
 
-	// Search in galpones
-	for i := range galpones {
-		if galpones[i].Symbol == symbol {
-			return &galpones[i], nil
-		}
-	}
+// copyOf returns a pointer to a copy of v, so lookups never hand out pointers into
+// collections that may be shared with the cache
+func copyOf[T any](v T) *T {
+	return &v
+}
 
-	return nil, fmt.Errorf("security %s not found", symbol)
+// tickerNotFound builds the INVALID_TICKER error returned by the lookup helpers
+func tickerNotFound(kind, symbol string) *api.BYMAError {
+	return api.NewBYMAError(api.ErrInvalidTicker.Code, fmt.Sprintf("%s %s not found", kind, symbol))
 }
 
 // FindSecurityInCollection searches for a security in a specific collection
 func FindSecurityInCollection(symbol string, securities []api.Security) (*api.Security, error) {
 	for i := range securities {
-		if securities[i].Symbol == symbol {
-			return &securities[i], nil
+		if SymbolsEqual(securities[i].Symbol, symbol) {
+			return copyOf(securities[i]), nil
 		}
 	}
-	return nil, fmt.Errorf("security %s not found", symbol)
+	return nil, tickerNotFound("security", symbol)
 }
 
-// FindBondBySymbol searches for a bond in multiple bond collections
-func FindBondBySymbol(symbol string, bonds, shortBonds, corporateBonds []api.Bond) (*api.Bond, error) {
-	// Search in regular bonds
+// FindBondInCollection searches for a bond in a specific collection
+func FindBondInCollection(symbol string, bonds []api.Bond) (*api.Bond, error) {
 	for i := range bonds {
-		if bonds[i].Symbol == symbol {
-			return &bonds[i], nil
+		if SymbolsEqual(bonds[i].Symbol, symbol) {
+			return copyOf(bonds[i]), nil
 		}
 	}
+	return nil, tickerNotFound("bond", symbol)
+}
+
+// SymbolIndex maps normalized symbols to the position of their first occurrence in a
+// collection, for constant-time lookups in large collections
+type SymbolIndex map[string]int
 
-	// Search in short-term bonds
-	for i := range shortBonds {
-		if shortBonds[i].Symbol == symbol {
-			return &shortBonds[i], nil
+// IndexSecurities builds a symbol index over a security collection
+func IndexSecurities(securities []api.Security) SymbolIndex {
+	return indexSymbols(securities, func(s *api.Security) string { return s.Symbol })
+}
+
+// IndexBonds builds a symbol index over a bond collection
+func IndexBonds(bonds []api.Bond) SymbolIndex {
+	return indexSymbols(bonds, func(b *api.Bond) string { return b.Symbol })
+}
+
+// indexSymbols builds a symbol index over any collection
+func indexSymbols[T any](items []T, symbolOf func(*T) string) SymbolIndex {
+	index := make(SymbolIndex, len(items))
+	for i := range items {
+		key := NormalizeSymbol(symbolOf(&items[i]))
+		if _, exists := index[key]; !exists {
+			index[key] = i
 		}
 	}
+	return index
+}
 
-	// Search in corporate bonds
-	for i := range corporateBonds {
-		if corporateBonds[i].Symbol == symbol {
-			return &corporateBonds[i], nil
-		}
+// FindSecurityIndexed works like FindSecurityInCollection, using index (built over
+// securities) when it is not nil instead of scanning the collection
+func FindSecurityIndexed(symbol string, securities []api.Security, index SymbolIndex) (*api.Security, error) {
+	if index == nil {
+		return FindSecurityInCollection(symbol, securities)
+	}
+	if i, exists := index[NormalizeSymbol(symbol)]; exists {
+		return copyOf(securities[i]), nil
+	}
+	return nil, tickerNotFound("security", symbol)
+}
+
+// FindBondIndexed works like FindBondInCollection, using index (built over bonds) when
+// it is not nil instead of scanning the collection
+func FindBondIndexed(symbol string, bonds []api.Bond, index SymbolIndex) (*api.Bond, error) {
+	if index == nil {
+		return FindBondInCollection(symbol, bonds)
+	}
+	if i, exists := index[NormalizeSymbol(symbol)]; exists {
+		return copyOf(bonds[i]), nil
 	}
+	return nil, tickerNotFound("bond", symbol)
+}
 
-	return nil, fmt.Errorf("bond %s not found", symbol)
+// FindIndexBySymbol searches for an index by symbol
+func FindIndexBySymbol(symbol string, indices []api.Index) (*api.Index, error) {
+	for i := range indices {
+		if SymbolsEqual(indices[i].Symbol, symbol) {
+			return copyOf(indices[i]), nil
+		}
+	}
+	return nil, tickerNotFound("index", symbol)
 }
 
 // FindOptionBySymbol searches for an option by symbol
 func FindOptionBySymbol(symbol string, options []api.Option) (*api.Option, error) {
 	for i := range options {
-		if options[i].Symbol == symbol {
-			return &options[i], nil
+		if SymbolsEqual(options[i].Symbol, symbol) {
+			return copyOf(options[i]), nil
 		}
 	}
-	return nil, fmt.Errorf("option %s not found", symbol)
+	return nil, tickerNotFound("option", symbol)
 }
 
 // FindFutureBySymbol searches for a future by symbol
 func FindFutureBySymbol(symbol string, futures []api.Future) (*api.Future, error) {
 	for i := range futures {
-		if futures[i].Symbol == symbol {
-			return &futures[i], nil
+		if SymbolsEqual(futures[i].Symbol, symbol) {
+			return copyOf(futures[i]), nil
 		}
 	}
-	return nil, fmt.Errorf("future %s not found", symbol)
+	return nil, tickerNotFound("future", symbol)
 }
 
-// GetMultipleSecurities creates a lookup map for multiple securities
-func GetMultipleSecurities(symbols []string, bluechips, cedears, galpones []api.Security) map[string]*api.Security {
-	results := make(map[string]*api.Security)
+// LatestIncomeStatement returns the statement with the most recent closing date.
+// Statements whose closing date cannot be parsed are only chosen if none can.
+func LatestIncomeStatement(statements []api.IncomeStatement) (*api.IncomeStatement, error) {
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("no income statements available")
+	}
 
-	// Create lookup maps for efficient searching
-	bluechipMap := make(map[string]*api.Security)
-	for i := range bluechips {
-		bluechipMap[bluechips[i].Symbol] = &bluechips[i]
+	latest := &statements[0]
+	latestDate := parseDate(latest.FechaCierre)
+	for i := 1; i < len(statements); i++ {
+		if date := parseDate(statements[i].FechaCierre); date.After(latestDate) {
+			latest = &statements[i]
+			latestDate = date
+		}
 	}
 
-	cedearMap := make(map[string]*api.Security)
-	for i := range cedears {
-		cedearMap[cedears[i].Symbol] = &cedears[i]
+	return latest, nil
+}
+
+// parseDate parses the date formats used by BYMA, returning the zero time on failure
+func parseDate(s string) time.Time {
+	formats := []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02",
+		"02/01/2006",
 	}
+	for _, format := range formats {
+		if t, err := time.Parse(format, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
 
-	galponeMap := make(map[string]*api.Security)
-	for i := range galpones {
-		galponeMap[galpones[i].Symbol] = &galpones[i]
+// GetMultipleSecurities creates a lookup map for multiple securities. Symbols are
+// matched ignoring case and surrounding whitespace; results are keyed by the symbols
+// as given.
+func GetMultipleSecurities(symbols []string, bluechips, cedears, galpones []api.Security) map[string]*api.Security {
+	results, _ := GetMultipleSecuritiesDetailed(symbols, bluechips, cedears, galpones)
+	return results
+}
+
+// GetMultipleBonds creates a lookup map for multiple bonds, searching the regular,
+// short-term and corporate collections in that order. Symbols are matched like in
+// GetMultipleSecurities; symbols not found are left out.
+func GetMultipleBonds(symbols []string, bonds, shortBonds, corporateBonds []api.Bond) map[string]*api.Bond {
+	return lookupSymbols(symbols, func(b *api.Bond) string { return b.Symbol }, bonds, shortBonds, corporateBonds)
+}
+
+// GetMultipleOptions creates a lookup map for multiple options, matching symbols like
+// GetMultipleSecurities; symbols not found are left out
+func GetMultipleOptions(symbols []string, options []api.Option) map[string]*api.Option {
+	return lookupSymbols(symbols, func(o *api.Option) string { return o.Symbol }, options)
+}
+
+// GetMultipleFutures creates a lookup map for multiple futures, matching symbols like
+// GetMultipleSecurities; symbols not found are left out
+func GetMultipleFutures(symbols []string, futures []api.Future) map[string]*api.Future {
+	return lookupSymbols(symbols, func(f *api.Future) string { return f.Symbol }, futures)
+}
+
+// lookupSymbols indexes the collections by normalized symbol, earlier collections
+// winning, and returns the items for the requested symbols keyed as given
+func lookupSymbols[T any](symbols []string, symbolOf func(*T) string, collections ...[]T) map[string]*T {
+	index := make(map[string]*T)
+	for _, items := range collections {
+		for i := range items {
+			key := NormalizeSymbol(symbolOf(&items[i]))
+			if _, exists := index[key]; !exists {
+				index[key] = &items[i]
+			}
+		}
 	}
 
-	// Find each requested symbol
+	results := make(map[string]*T)
 	for _, symbol := range symbols {
-		if security, exists := bluechipMap[symbol]; exists {
-			results[symbol] = security
-		} else if security, exists := cedearMap[symbol]; exists {
-			results[symbol] = security
-		} else if security, exists := galponeMap[symbol]; exists {
-			results[symbol] = security
+		if item, exists := index[NormalizeSymbol(symbol)]; exists {
+			results[symbol] = copyOf(*item)
 		}
-		// If not found, it's simply not included in results
 	}
-
 	return results
 }
 
-// SearchSecurities searches for securities containing the given text
-func SearchSecurities(searchText string, bluechips, cedears, galpones []api.Security) []api.Security {
-	var results []api.Security
+// GetMultipleSecuritiesDetailed works like GetMultipleSecurities but also returns the
+// symbols that were not found in any collection, in the order they were given.
+// Collections are searched in order, so earlier ones win when a symbol repeats.
+func GetMultipleSecuritiesDetailed(symbols []string, collections ...[]api.Security) (map[string]*api.Security, []string) {
+	results := lookupSymbols(symbols, func(s *api.Security) string { return s.Symbol }, collections...)
 
-	// Search in blue chips
-	for _, security := range bluechips {
-		if contains(security.Symbol, searchText) {
-			results = append(results, security)
+	notFound := []string{}
+	for _, symbol := range symbols {
+		if _, found := results[symbol]; !found {
+			notFound = append(notFound, symbol)
 		}
 	}
+	return results, notFound
+}
 
-	// Search in CEDEARs
-	for _, security := range cedears {
-		if contains(security.Symbol, searchText) {
-			results = append(results, security)
+// ProjectSecurity returns a copy of security with only the named fields set, using
+// the Security JSON field names. Symbol is always kept; unknown names are ignored.
+func ProjectSecurity(security api.Security, fields []string) api.Security {
+	projected := api.Security{Symbol: security.Symbol}
+
+	for _, field := range fields {
+		switch field {
+		case "settlement":
+			projected.Settlement = security.Settlement
+		case "bid_size":
+			projected.BidSize = security.BidSize
+		case "bid":
+			projected.Bid = security.Bid
+		case "ask":
+			projected.Ask = security.Ask
+		case "ask_size":
+			projected.AskSize = security.AskSize
+		case "last":
+			projected.Last = security.Last
+		case "close":
+			projected.Close = security.Close
+		case "change":
+			projected.Change = security.Change
+		case "open":
+			projected.Open = security.Open
+		case "high":
+			projected.High = security.High
+		case "low":
+			projected.Low = security.Low
+		case "previous_close":
+			projected.PreviousClose = security.PreviousClose
+		case "turnover":
+			projected.Turnover = security.Turnover
+		case "volume":
+			projected.Volume = security.Volume
+		case "operations":
+			projected.Operations = security.Operations
+		case "datetime":
+			projected.DateTime = security.DateTime
+		case "group":
+			projected.Group = security.Group
+		case "panel":
+			projected.Panel = security.Panel
+		case "collection":
+			projected.Collection = security.Collection
 		}
 	}
 
-	// Search in galpones
-	for _, security := range galpones {
-		if contains(security.Symbol, searchText) {
-			results = append(results, security)
-		}
+	return projected
+}
+
+// SecurityApproxEqual reports whether two securities are equal, treating price and
+// amount fields as equal when they differ by at most epsilon. All other fields
+// (symbol, sizes, volume, operations, timestamps, etc.) must match exactly.
+func SecurityApproxEqual(a, b api.Security, epsilon float64) bool {
+	if a.Symbol != b.Symbol || a.Settlement != b.Settlement || a.Group != b.Group || a.Panel != b.Panel ||
+		a.Collection != b.Collection ||
+		a.BidSize != b.BidSize || a.AskSize != b.AskSize ||
+		a.Volume != b.Volume || a.Operations != b.Operations ||
+		!a.DateTime.Equal(b.DateTime) {
+		return false
 	}
 
-	return results
+	return approxEqual(a.Bid, b.Bid, epsilon) &&
+		approxEqual(a.Ask, b.Ask, epsilon) &&
+		approxEqual(a.Last, b.Last, epsilon) &&
+		approxEqual(a.Close, b.Close, epsilon) &&
+		approxEqual(a.Change, b.Change, epsilon) &&
+		approxEqual(a.Open, b.Open, epsilon) &&
+		approxEqual(a.High, b.High, epsilon) &&
+		approxEqual(a.Low, b.Low, epsilon) &&
+		approxEqual(a.PreviousClose, b.PreviousClose, epsilon) &&
+		approxEqual(a.Turnover, b.Turnover, epsilon)
 }
 
-// contains checks if a string contains another string (case-insensitive)
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) &&
-		(s == substr ||
-			len(substr) == 0 ||
-			stringContains(s, substr))
+// approxEqual reports whether a and b differ by at most epsilon
+func approxEqual(a, b, epsilon float64) bool {
+	return math.Abs(a-b) <= epsilon
 }
 
-// stringContains performs case-insensitive substring search
-func stringContains(s, substr string) bool {
-	sLower := toLower(s)
-	substrLower := toLower(substr)
-	return simpleContains(sLower, substrLower)
+// FilterSecuritiesByPanel returns the securities listed on the given panel (case-insensitive)
+func FilterSecuritiesByPanel(panel string, collections ...[]api.Security) []api.Security {
+	results := []api.Security{}
+	for _, securities := range collections {
+		for _, security := range securities {
+			if security.Panel != "" && strings.EqualFold(security.Panel, panel) {
+				results = append(results, security)
+			}
+		}
+	}
+	return results
 }
 
-// toLower converts string to lowercase
-func toLower(s string) string {
-	result := make([]byte, len(s))
-	for i := 0; i < len(s); i++ {
-		if s[i] >= 'A' && s[i] <= 'Z' {
-			result[i] = s[i] + ('a' - 'A')
-		} else {
-			result[i] = s[i]
+// UnionSecurities returns the securities of every collection, in order, keeping only
+// the first occurrence of each symbol (compared in normalized form)
+func UnionSecurities(collections ...[]api.Security) []api.Security {
+	total := 0
+	for _, securities := range collections {
+		total += len(securities)
+	}
+
+	results := make([]api.Security, 0, total)
+	seen := make(map[string]bool, total)
+	for _, securities := range collections {
+		for _, security := range securities {
+			key := NormalizeSymbol(security.Symbol)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			results = append(results, security)
 		}
 	}
-	return string(result)
+	return results
 }
 
-// simpleContains checks if s contains substr
-func simpleContains(s, substr string) bool {
-	if len(substr) == 0 {
-		return true
+// Paginate returns up to limit items starting at offset, copied so the page doesn't keep
+// the whole collection alive. A limit of zero returns every item from offset on, and an
+// offset past the end yields an empty page.
+func Paginate[T any](items []T, offset, limit int) ([]T, error) {
+	if offset < 0 || limit < 0 {
+		return nil, api.NewBYMAError(api.ErrInvalidPage.Code,
+			fmt.Sprintf("offset and limit must not be negative, got offset=%d limit=%d", offset, limit))
 	}
-	if len(substr) > len(s) {
-		return false
+	if offset >= len(items) {
+		return []T{}, nil
+	}
+	end := len(items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return slices.Clone(items[offset:end]), nil
+}
+
+// Match modes and fields understood by SearchSecurities
+const (
+	SearchSubstring = "substring"
+	SearchPrefix    = "prefix"
+	SearchExact     = "exact"
+
+	SearchFieldSymbol      = "symbol"
+	SearchFieldDescription = "description"
+)
+
+// SearchSecurities returns the securities whose symbol, or whose description as given by
+// describe, matches the search text (case-insensitive) under the given mode. Results are
+// ordered by relevance: exact matches first, then prefix and then substring matches,
+// keeping collection order within each. No fields means the symbol alone, and a limit of
+// zero returns every match.
+func SearchSecurities(searchText, mode string, fields []string, limit int, describe func(symbol string) string, collections ...[]api.Security) []api.Security {
+	if len(fields) == 0 {
+		fields = []string{SearchFieldSymbol}
+	}
+
+	type match struct {
+		security api.Security
+		rank     int
 	}
-	for i := 0; i <= len(s)-len(substr); i++ {
-		match := true
-		for j := 0; j < len(substr); j++ {
-			if s[i+j] != substr[j] {
-				match = false
-				break
+	var matches []match
+	for _, securities := range collections {
+		for _, security := range securities {
+			best := -1
+			for _, field := range fields {
+				value := security.Symbol
+				if field == SearchFieldDescription {
+					if describe == nil {
+						continue
+					}
+					if value = describe(security.Symbol); value == "" {
+						continue
+					}
+				}
+				if rank, ok := matchRank(value, searchText, mode); ok && (best < 0 || rank < best) {
+					best = rank
+				}
+			}
+			if best >= 0 {
+				matches = append(matches, match{security: security, rank: best})
 			}
 		}
-		if match {
-			return true
-		}
 	}
-	return false
+
+	slices.SortStableFunc(matches, func(a, b match) int { return a.rank - b.rank })
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	var results []api.Security
+	for _, m := range matches {
+		results = append(results, m.security)
+	}
+	return results
+}
+
+// matchRank reports whether value matches text under mode and how relevant the match
+// is: 0 for an exact match, 1 for a prefix and 2 for any other substring
+func matchRank(value, text, mode string) (int, bool) {
+	value, text = strings.ToLower(value), strings.ToLower(text)
+	switch {
+	case value == text:
+		return 0, true
+	case mode == SearchExact:
+		return 0, false
+	case strings.HasPrefix(value, text):
+		return 1, true
+	case mode == SearchPrefix:
+		return 0, false
+	case strings.Contains(value, text):
+		return 2, true
+	}
+	return 0, false
 }
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -26,6 +28,10 @@ func GetFloat64(m map[string]interface{}, key string) float64 {
 			return float64(val)
 		case int64:
 			return float64(val)
+		case string:
+			if f, ok := parseNumber(val); ok {
+				return f
+			}
 		}
 	}
 	return 0
@@ -43,11 +49,52 @@ func GetInt64(m map[string]interface{}, key string) int64 {
 			return int64(val)
 		case float32:
 			return int64(val)
+		case string:
+			if f, ok := parseNumber(val); ok {
+				return int64(f)
+			}
 		}
 	}
 	return 0
 }
 
+// parseNumber parses a number sent as a string, accepting both the plain format
+// ("1234.56") and Argentine formatting with "." for thousands and "," for decimals
+// ("1.234,56"). When both separators appear the last one is the decimal separator;
+// a lone "," followed by exactly three digits ("1,234") or repeated separators
+// ("1.234.567") are thousands separators.
+func parseNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+
+	lastDot, lastComma := strings.LastIndex(s, "."), strings.LastIndex(s, ",")
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		if lastComma > lastDot {
+			s = strings.ReplaceAll(s, ".", "")
+			s = strings.Replace(s, ",", ".", 1)
+		} else {
+			s = strings.ReplaceAll(s, ",", "")
+		}
+	case lastComma >= 0:
+		if strings.Count(s, ",") > 1 || len(s)-lastComma-1 == 3 {
+			s = strings.ReplaceAll(s, ",", "")
+		} else {
+			s = strings.Replace(s, ",", ".", 1)
+		}
+	case strings.Count(s, ".") > 1:
+		s = strings.ReplaceAll(s, ".", "")
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
 // GetTime extracts a time.Time value from a map[string]interface{}
 func GetTime(m map[string]interface{}, key string) time.Time {
 	if v, ok := m[key]; ok {
@@ -71,10 +118,34 @@ func GetTime(m map[string]interface{}, key string) time.Time {
 	return time.Time{}
 }
 
-// ParseTradeTime converts trade hour string to time.Time
-func ParseTradeTime(tradeHour string) time.Time {
+// MarketLocation is the time zone of the BYMA trading session. Trade times are parsed
+// and returned in this zone regardless of the local zone of the host. It falls back to
+// a fixed UTC-3 zone, the offset Argentina has used year-round since 2009, when the
+// system has no time zone database.
+var MarketLocation = loadMarketLocation()
+
+func loadMarketLocation() *time.Location {
+	if loc, err := time.LoadLocation("America/Argentina/Buenos_Aires"); err == nil {
+		return loc
+	}
+	return time.FixedZone("ART", -3*60*60)
+}
+
+// tradeDateKeys are the raw payload fields that may carry the trading date of a quote
+var tradeDateKeys = []string{"tradeDate", "date"}
+
+// ParseTradeTime returns the time of the last trade of a raw quote. Its "tradeHour"
+// may be a full timestamp or just a time of day; a time of day is placed on the
+// trading date found in the payload (see tradeDateKeys), so quotes fetched after the
+// session, on weekends or holidays keep the date they traded on. Today is used only
+// when the payload has no trading date. When there is no usable trade hour the zero
+// time is returned, so callers can tell an unknown trade time from a fresh quote.
+// Times without an explicit offset are BYMA session times, and the result is always
+// expressed in MarketLocation.
+func ParseTradeTime(raw map[string]interface{}) time.Time {
+	tradeHour := GetString(raw, "tradeHour")
 	if tradeHour == "" {
-		return time.Now()
+		return time.Time{}
 	}
 
 	// Try to parse the trade hour with different formats
@@ -87,17 +158,45 @@ func ParseTradeTime(tradeHour string) time.Time {
 	}
 
 	for _, format := range formats {
-		if t, err := time.Parse(format, tradeHour); err == nil {
-			// If only time was parsed, set it to today
+		if t, err := time.ParseInLocation(format, tradeHour, MarketLocation); err == nil {
+			// If only time was parsed, set it to the trading date
 			if format == "15:04:05" || format == "15:04" {
-				now := time.Now()
-				return time.Date(now.Year(), now.Month(), now.Day(),
-					t.Hour(), t.Minute(), t.Second(), 0, now.Location())
+				year, month, day := time.Now().In(MarketLocation).Date()
+				if date := parseTradeDate(raw); !date.IsZero() {
+					year, month, day = date.Date()
+				}
+				return time.Date(year, month, day,
+					t.Hour(), t.Minute(), t.Second(), 0, MarketLocation)
 			}
-			return t
+			return t.In(MarketLocation)
 		}
 	}
 
-	// If parsing fails, return current time
-	return time.Now()
+	// If parsing fails, the trade time is unknown
+	return time.Time{}
+}
+
+// parseTradeDate returns the trading date of a raw quote, or the zero time if the
+// payload has none. Dates without an explicit offset are BYMA session dates, parsed
+// in MarketLocation.
+func parseTradeDate(raw map[string]interface{}) time.Time {
+	formats := []string{
+		"2006-01-02",
+		"02/01/2006",
+		"2006-01-02T15:04:05",
+		time.RFC3339,
+	}
+
+	for _, key := range tradeDateKeys {
+		value := GetString(raw, key)
+		if value == "" {
+			continue
+		}
+		for _, format := range formats {
+			if t, err := time.ParseInLocation(format, value, MarketLocation); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
 }
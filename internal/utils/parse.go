@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FieldError describes a raw payload field that is missing or has an unexpected type
+type FieldError struct {
+	Field   string
+	Missing bool   // The field is absent or null
+	Problem string // Description of the problem
+}
+
+// Error implements the error interface
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %q %s", e.Field, e.Problem)
+}
+
+func missingField(key string) *FieldError {
+	return &FieldError{Field: key, Missing: true, Problem: "is missing"}
+}
+
+func wrongType(key string, v interface{}, expected string) *FieldError {
+	return &FieldError{Field: key, Problem: fmt.Sprintf("is %T %v, expected %s", v, v, expected)}
+}
+
+// ParseString is the strict variant of GetString: it returns a *FieldError when the
+// field is missing, null or not a string
+func ParseString(m map[string]interface{}, key string) (string, error) {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return "", missingField(key)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", wrongType(key, v, "a string")
+	}
+	return s, nil
+}
+
+// ParseFloat64 is the strict variant of GetFloat64: it returns a *FieldError when the
+// field is missing, null, or neither a number nor a numeric string
+func ParseFloat64(m map[string]interface{}, key string) (float64, error) {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return 0, missingField(key)
+	}
+	switch val := v.(type) {
+	case float64:
+		return val, nil
+	case float32:
+		return float64(val), nil
+	case int:
+		return float64(val), nil
+	case int64:
+		return float64(val), nil
+	case string:
+		if f, ok := parseNumber(val); ok {
+			return f, nil
+		}
+	}
+	return 0, wrongType(key, v, "a number")
+}
+
+// ParseInt64 is the strict variant of GetInt64, with the same rules as ParseFloat64
+func ParseInt64(m map[string]interface{}, key string) (int64, error) {
+	f, err := ParseFloat64(m, key)
+	return int64(f), err
+}
+
+// Reader extracts the fields of a raw payload item. In lenient mode it behaves like
+// the Get* helpers, turning missing or malformed fields into zero values. In strict
+// mode it also records an error for every required field that is missing and for every
+// field present with the wrong type; optional fields may still be absent or null.
+type Reader struct {
+	raw    map[string]interface{}
+	strict bool
+	errs   []error
+}
+
+// NewReader returns a Reader over raw
+func NewReader(raw map[string]interface{}, strict bool) *Reader {
+	return &Reader{raw: raw, strict: strict}
+}
+
+// check records err unless it is lenient mode, or a missing optional field
+func (r *Reader) check(err error, required bool) {
+	var fieldErr *FieldError
+	if err == nil || !r.strict || (!required && errors.As(err, &fieldErr) && fieldErr.Missing) {
+		return
+	}
+	r.errs = append(r.errs, err)
+}
+
+// String returns an optional string field
+func (r *Reader) String(key string) string {
+	if !r.strict {
+		return GetString(r.raw, key)
+	}
+	s, err := ParseString(r.raw, key)
+	r.check(err, false)
+	return s
+}
+
+// RequiredString returns a string field that must be present and non-empty in strict mode
+func (r *Reader) RequiredString(key string) string {
+	if !r.strict {
+		return GetString(r.raw, key)
+	}
+	s, err := ParseString(r.raw, key)
+	if err == nil && s == "" {
+		err = &FieldError{Field: key, Missing: true, Problem: "is empty"}
+	}
+	r.check(err, true)
+	return s
+}
+
+// Float64 returns an optional numeric field
+func (r *Reader) Float64(key string) float64 {
+	if !r.strict {
+		return GetFloat64(r.raw, key)
+	}
+	f, err := ParseFloat64(r.raw, key)
+	r.check(err, false)
+	return f
+}
+
+// Int64 returns an optional integer field
+func (r *Reader) Int64(key string) int64 {
+	if !r.strict {
+		return GetInt64(r.raw, key)
+	}
+	i, err := ParseInt64(r.raw, key)
+	r.check(err, false)
+	return i
+}
+
+// Err returns every field error recorded so far, joined, or nil
+func (r *Reader) Err() error {
+	return errors.Join(r.errs...)
+}
@@ -0,0 +1,188 @@
+// Package openbymadataotel traces openbymadata client requests with OpenTelemetry.
+//
+// It is a separate module so that programs which don't use OpenTelemetry don't depend
+// on it. Instrument wraps the transport of the client's HTTP client so every HTTP
+// attempt, retries included, becomes a client span, child of the span in the context
+// passed to the client call, and sets the cache hooks (OnCacheHit and OnCacheMiss) to
+// count lookups with a meter.
+//
+//	opts := openbymadata.DefaultClientOptions()
+//	if err := openbymadataotel.Instrument(opts); err != nil {
+//		log.Fatal(err)
+//	}
+//	client := openbymadata.NewClient(opts)
+//
+// Spans are named "BYMA <endpoint>", e.g. "BYMA cedears", and carry these attributes:
+//
+//	openbymadata.endpoint        Endpoint requested, as reported to OnRequest
+//	openbymadata.attempt         0 for the first attempt, n for the nth retry
+//	openbymadata.correlation_id  Trace ID, also sent to BYMA in the traceparent header
+//	http.request.method          HTTP method
+//	http.response.status_code    HTTP status, when a response was received
+//	url.full                     Requested URL
+//
+// A span is marked as an error when the attempt fails or BYMA answers with a status
+// of 400 or above. Cache hits make no request, so they have no span; lookups are
+// counted by the openbymadata.cache.lookups counter instead, with the category
+// (a Cache* constant) and the result ("hit" or "miss") as attributes.
+package openbymadataotel
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/carvalab/openbymadata"
+)
+
+// instrumentationName identifies the tracer and meter of this package
+const instrumentationName = "github.com/carvalab/openbymadata/openbymadataotel"
+
+// config holds the providers set by the options of Instrument
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// Option configures Instrument
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider spans are created with (default: the
+// global one, otel.GetTracerProvider)
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) { c.tracerProvider = provider }
+}
+
+// WithMeterProvider sets the meter provider cache lookups are counted with (default:
+// the global one, otel.GetMeterProvider)
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) { c.meterProvider = provider }
+}
+
+// WithPropagator sets the propagator that injects the span context into the request
+// headers (default: W3C Trace Context, the traceparent header)
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) { c.propagator = propagator }
+}
+
+// Instrument sets opts to trace every HTTP request and count cache lookups; hooks
+// already set in opts are still called. Pass the options to NewClient afterwards.
+//
+// When opts.HTTPClient is set, Instrument replaces it with a copy whose transport is
+// wrapped, leaving the original client untouched; otherwise it installs a client like
+// the default one, honoring InsecureSkipVerify and RootCAs.
+func Instrument(opts *openbymadata.ClientOptions, options ...Option) error {
+	if opts == nil {
+		return errors.New("openbymadataotel: nil client options")
+	}
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagator:     propagation.TraceContext{},
+	}
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	lookups, err := cfg.meterProvider.Meter(instrumentationName).Int64Counter("openbymadata.cache.lookups",
+		metric.WithDescription("Cache lookups by category and result (hit or miss)."))
+	if err != nil {
+		return fmt.Errorf("openbymadataotel: %w", err)
+	}
+
+	var httpClient http.Client
+	if opts.HTTPClient != nil {
+		httpClient = *opts.HTTPClient
+	} else {
+		// Like the client's default: no http.Client.Timeout, the client applies its own
+		// timeout per attempt
+		base := http.DefaultTransport.(*http.Transport).Clone()
+		base.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: opts.InsecureSkipVerify,
+			RootCAs:            opts.RootCAs,
+		}
+		httpClient.Transport = base
+	}
+	next := httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	httpClient.Transport = &transport{
+		next:       next,
+		tracer:     cfg.tracerProvider.Tracer(instrumentationName),
+		propagator: cfg.propagator,
+	}
+	opts.HTTPClient = &httpClient
+
+	onCacheHit, onCacheMiss := opts.OnCacheHit, opts.OnCacheMiss
+	opts.OnCacheHit = func(category string) {
+		lookups.Add(context.Background(), 1, metric.WithAttributes(
+			attribute.String("category", category), attribute.String("result", "hit")))
+		if onCacheHit != nil {
+			onCacheHit(category)
+		}
+	}
+	opts.OnCacheMiss = func(category string) {
+		lookups.Add(context.Background(), 1, metric.WithAttributes(
+			attribute.String("category", category), attribute.String("result", "miss")))
+		if onCacheMiss != nil {
+			onCacheMiss(category)
+		}
+	}
+	return nil
+}
+
+// transport starts a span around every request it sends
+type transport struct {
+	next       http.RoundTripper
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := req.URL.Path
+	attempt := 0
+	if info, ok := openbymadata.RequestInfoFrom(req.Context()); ok {
+		endpoint, attempt = info.Endpoint, info.Attempt
+	}
+
+	ctx, span := t.tracer.Start(req.Context(), "BYMA "+endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("openbymadata.endpoint", endpoint),
+			attribute.Int("openbymadata.attempt", attempt),
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", req.URL.String()),
+		))
+	defer span.End()
+	if traceID := span.SpanContext().TraceID(); traceID.IsValid() {
+		span.SetAttributes(attribute.String("openbymadata.correlation_id", traceID.String()))
+	}
+
+	// RoundTrippers must not modify the request they are given
+	req = req.Clone(ctx)
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
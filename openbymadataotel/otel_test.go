@@ -0,0 +1,125 @@
+package openbymadataotel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/carvalab/openbymadata"
+)
+
+func TestInstrument(t *testing.T) {
+	var bondRequests atomic.Int32
+	var traceparent atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent.Store(r.Header.Get("traceparent"))
+		switch path.Base(r.URL.Path) {
+		case "leading-equity":
+			fmt.Fprint(w, `{"data":[{"symbol":"GGAL"}]}`)
+		case "public-bonds":
+			if bondRequests.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `{"data":[{"symbol":"AL30"}]}`)
+		default:
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+	defer server.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	var hits int
+	opts := &openbymadata.ClientOptions{
+		BaseURL:         server.URL,
+		RetryAttempts:   1,
+		RetryBaseDelay:  time.Millisecond,
+		RetryMaxDelay:   time.Millisecond,
+		Logger:          &openbymadata.NoOpLogger{},
+		SkipSessionInit: true,
+		OnCacheHit:      func(string) { hits++ },
+	}
+	require.NoError(t, Instrument(opts, WithTracerProvider(tracerProvider), WithMeterProvider(meterProvider)))
+	require.Error(t, Instrument(nil))
+
+	client := openbymadata.NewClient(opts)
+	defer client.Close()
+	ctx, parent := tracerProvider.Tracer("test").Start(context.Background(), "portfolio")
+
+	// One span per request: the cached calls make none
+	for i := 0; i < 3; i++ {
+		_, err := client.GetBluechips(ctx)
+		require.NoError(t, err)
+	}
+	// The failed attempt and its retry get a span each
+	_, err := client.GetBonds(ctx)
+	require.NoError(t, err)
+	parent.End()
+
+	ended := spans.Ended()
+	require.Len(t, ended, 4)
+	bluechips, failed, retried := ended[0], ended[1], ended[2]
+	assert.Equal(t, "portfolio", ended[3].Name())
+
+	assert.Equal(t, "BYMA leading-equity", bluechips.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), bluechips.Parent().SpanID())
+	traceID := parent.SpanContext().TraceID().String()
+	assert.Contains(t, bluechips.Attributes(), attribute.String("openbymadata.correlation_id", traceID))
+	assert.Contains(t, bluechips.Attributes(), attribute.Int("http.response.status_code", http.StatusOK))
+	assert.Contains(t, traceparent.Load().(string), traceID, "the trace is propagated to BYMA")
+
+	assert.Equal(t, "BYMA public-bonds", failed.Name())
+	assert.Contains(t, failed.Attributes(), attribute.Int("openbymadata.attempt", 0))
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.Contains(t, retried.Attributes(), attribute.Int("openbymadata.attempt", 1))
+	assert.Equal(t, codes.Unset, retried.Status().Code)
+
+	// Cache lookups are counted, and the hooks already set still called
+	var metrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &metrics))
+	lookups := map[string]int64{}
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != "openbymadata.cache.lookups" {
+				continue
+			}
+			for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				category, _ := point.Attributes.Value("category")
+				result, _ := point.Attributes.Value("result")
+				lookups[category.AsString()+"/"+result.AsString()] = point.Value
+			}
+		}
+	}
+	assert.Equal(t, int64(2), lookups[openbymadata.CacheBluechips+"/hit"])
+	assert.Equal(t, int64(1), lookups[openbymadata.CacheBluechips+"/miss"])
+	assert.Equal(t, 2, hits)
+}
+
+func TestInstrument_KeepsHTTPClient(t *testing.T) {
+	original := &http.Client{Timeout: time.Second}
+	opts := &openbymadata.ClientOptions{HTTPClient: original}
+	require.NoError(t, Instrument(opts))
+
+	assert.NotSame(t, original, opts.HTTPClient)
+	assert.Nil(t, original.Transport, "the original client is left untouched")
+	assert.Equal(t, time.Second, opts.HTTPClient.Timeout)
+	assert.IsType(t, &transport{}, opts.HTTPClient.Transport)
+}
@@ -0,0 +1,119 @@
+// Package openbymadataprom exports openbymadata client metrics to Prometheus.
+//
+// It is a separate module so that programs which don't use Prometheus don't depend on
+// it: Instrument sets the client's metric hooks (OnRequest, OnError, OnCacheHit and
+// OnCacheMiss) to update collectors registered with a prometheus.Registerer.
+//
+//	opts := openbymadata.DefaultClientOptions()
+//	if err := openbymadataprom.Instrument(opts, prometheus.DefaultRegisterer); err != nil {
+//		log.Fatal(err)
+//	}
+//	client := openbymadata.NewClient(opts)
+//
+// The exported metrics are:
+//
+//	openbymadata_requests_total{endpoint}              HTTP attempts, retries included
+//	openbymadata_request_duration_seconds{endpoint}    Latency of each attempt
+//	openbymadata_errors_total{endpoint,code}           Failed requests by BYMAError code
+//	openbymadata_cache_lookups_total{category,result}  Cache lookups, result "hit" or "miss"
+//
+// The cache hit ratio of a category is then
+//
+//	sum by (category) (rate(openbymadata_cache_lookups_total{result="hit"}[5m]))
+//	  / sum by (category) (rate(openbymadata_cache_lookups_total[5m]))
+package openbymadataprom
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/carvalab/openbymadata"
+)
+
+// UnknownCode is the code label of errors that are not a BYMAError, such as a
+// cancelled context
+const UnknownCode = "UNKNOWN"
+
+// Instrument registers the client metrics with reg and sets the metric hooks of opts
+// to update them; hooks already set in opts are still called. Pass the options to
+// NewClient afterwards. A nil reg uses prometheus.DefaultRegisterer.
+//
+// Registering twice with the same registry fails, so instrument a single client per
+// registry, or wrap reg with prometheus.WrapRegistererWith to tell clients apart.
+func Instrument(opts *openbymadata.ClientOptions, reg prometheus.Registerer) error {
+	if opts == nil {
+		return errors.New("openbymadataprom: nil client options")
+	}
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "openbymadata",
+		Name:      "requests_total",
+		Help:      "HTTP requests sent to BYMA, retries included.",
+	}, []string{"endpoint"})
+	durations := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "openbymadata",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of the HTTP requests sent to BYMA.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"endpoint"})
+	failures := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "openbymadata",
+		Name:      "errors_total",
+		Help:      "Requests that failed after any retries, by BYMAError code.",
+	}, []string{"endpoint", "code"})
+	lookups := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "openbymadata",
+		Name:      "cache_lookups_total",
+		Help:      "Cache lookups by category and result (hit or miss).",
+	}, []string{"category", "result"})
+
+	for _, collector := range []prometheus.Collector{requests, durations, failures, lookups} {
+		if err := reg.Register(collector); err != nil {
+			return err
+		}
+	}
+
+	onRequest, onError := opts.OnRequest, opts.OnError
+	onCacheHit, onCacheMiss := opts.OnCacheHit, opts.OnCacheMiss
+
+	opts.OnRequest = func(endpoint string, duration time.Duration, err error) {
+		requests.WithLabelValues(endpoint).Inc()
+		durations.WithLabelValues(endpoint).Observe(duration.Seconds())
+		if onRequest != nil {
+			onRequest(endpoint, duration, err)
+		}
+	}
+	opts.OnError = func(endpoint string, err error) {
+		failures.WithLabelValues(endpoint, errorCode(err)).Inc()
+		if onError != nil {
+			onError(endpoint, err)
+		}
+	}
+	opts.OnCacheHit = func(category string) {
+		lookups.WithLabelValues(category, "hit").Inc()
+		if onCacheHit != nil {
+			onCacheHit(category)
+		}
+	}
+	opts.OnCacheMiss = func(category string) {
+		lookups.WithLabelValues(category, "miss").Inc()
+		if onCacheMiss != nil {
+			onCacheMiss(category)
+		}
+	}
+	return nil
+}
+
+// errorCode returns the BYMAError code of err, or UnknownCode
+func errorCode(err error) string {
+	var bymaErr *openbymadata.BYMAError
+	if errors.As(err, &bymaErr) && bymaErr.Code != "" {
+		return bymaErr.Code
+	}
+	return UnknownCode
+}
@@ -0,0 +1,96 @@
+package openbymadataprom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/carvalab/openbymadata"
+)
+
+func TestInstrument(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch path.Base(r.URL.Path) {
+		case "leading-equity":
+			fmt.Fprint(w, `{"data":[{"symbol":"GGAL"}]}`)
+		case "public-bonds":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+	defer server.Close()
+
+	var hits, errs int
+	opts := &openbymadata.ClientOptions{
+		BaseURL:        server.URL,
+		RetryAttempts:  1,
+		RetryBaseDelay: time.Millisecond,
+		RetryMaxDelay:  time.Millisecond,
+		Logger:         &openbymadata.NoOpLogger{},
+		OnCacheHit:     func(string) { hits++ },
+		OnError:        func(string, error) { errs++ },
+	}
+	registry := prometheus.NewRegistry()
+	require.NoError(t, Instrument(opts, registry))
+	require.Error(t, Instrument(opts, registry), "the metrics are already registered")
+
+	client := openbymadata.NewClient(opts)
+	defer client.Close()
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := client.GetBluechips(ctx)
+		require.NoError(t, err)
+	}
+	_, err := client.GetBonds(ctx)
+	require.ErrorIs(t, err, openbymadata.ErrAPIUnavailable)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	metric := func(name string, labels map[string]string) float64 {
+		for _, family := range families {
+			if family.GetName() != name {
+				continue
+			}
+		metrics:
+			for _, m := range family.GetMetric() {
+				for _, label := range m.GetLabel() {
+					if labels[label.GetName()] != label.GetValue() {
+						continue metrics
+					}
+				}
+				if m.GetHistogram() != nil {
+					return float64(m.GetHistogram().GetSampleCount())
+				}
+				return m.GetCounter().GetValue()
+			}
+		}
+		return 0
+	}
+
+	assert.Equal(t, 1.0, metric("openbymadata_requests_total", map[string]string{"endpoint": "leading-equity"}))
+	assert.Equal(t, 2.0, metric("openbymadata_requests_total", map[string]string{"endpoint": "public-bonds"}), "retries are counted")
+	assert.Equal(t, 1.0, metric("openbymadata_request_duration_seconds", map[string]string{"endpoint": "leading-equity"}))
+	assert.Equal(t, 1.0, metric("openbymadata_errors_total", map[string]string{"endpoint": "public-bonds", "code": "API_UNAVAILABLE"}))
+	assert.Equal(t, 0.0, metric("openbymadata_errors_total", map[string]string{"endpoint": "leading-equity", "code": "API_UNAVAILABLE"}))
+	assert.Equal(t, 2.0, metric("openbymadata_cache_lookups_total", map[string]string{"category": openbymadata.CacheBluechips, "result": "hit"}))
+	assert.Equal(t, 1.0, metric("openbymadata_cache_lookups_total", map[string]string{"category": openbymadata.CacheBluechips, "result": "miss"}))
+
+	// Hooks set before Instrument still run
+	assert.Equal(t, 2, hits)
+	assert.Equal(t, 1, errs)
+}
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, "RATE_LIMITED", errorCode(fmt.Errorf("request failed: %w", openbymadata.ErrRateLimited)))
+	assert.Equal(t, UnknownCode, errorCode(context.Canceled))
+}
@@ -0,0 +1,272 @@
+// Package openbymadatatest provides a fake openbymadata client for testing code that
+// depends on openbymadata.Client without reaching BYMA.
+//
+// The fake is a real client whose HTTP transport answers in process from a FakeData,
+// so every method works as in production, including the ones derived from the raw
+// collections (GetSecurity, SearchSecurities, option chains, dollar rates, ...):
+//
+//	client := openbymadatatest.NewFakeClient(openbymadatatest.FakeData{
+//		Bluechips: []openbymadata.Security{{Symbol: "GGAL", Last: 4500}},
+//		Errors:    map[string]error{openbymadata.CacheBonds: errors.New("boom")},
+//	})
+//	defer client.Close()
+//
+//	ggal, _ := client.GetSecurity(ctx, "GGAL") // 4500
+//	_, err := client.GetBonds(ctx)              // errors.Is(err, boom)
+package openbymadatatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/carvalab/openbymadata"
+)
+
+// BaseURL is the base URL of the fake client; no request leaves the process
+const BaseURL = "http://openbymadata.fake"
+
+// Keys of FakeData.Errors for the requests without a cache category
+const (
+	ErrorsMarketTime = "market_time" // IsWorkingDay and GetMarketTime
+	ErrorsDocuments  = "documents"   // DownloadAttachment and the statement downloads
+)
+
+// apiPath is the path prefix of every BYMA API endpoint
+const apiPath = "/vanoms-be-core/rest/api/bymadata/free/"
+
+// FakeData is the canned data a fake client serves. Collections are returned as
+// given, each time they are requested; a nil collection is served as empty.
+type FakeData struct {
+	WorkingDay bool // Returned by IsWorkingDay
+
+	Bluechips []openbymadata.Security
+	Galpones  []openbymadata.Security
+	Cedears   []openbymadata.Security
+	Etfs      []openbymadata.Security
+
+	Bonds          []openbymadata.Bond
+	ShortTermBonds []openbymadata.Bond
+	CorporateBonds []openbymadata.Bond
+
+	Options []openbymadata.Option // Kind and Strike are derived from the symbol, as for BYMA data
+	Futures []openbymadata.Future // Prices as quoted: the fake uses a Multiplier of 1
+
+	Indices       []openbymadata.Index
+	MarketSummary []openbymadata.MarketSummary
+	News          []openbymadata.News
+
+	// IncomeStatements are keyed by ticker (case-insensitive)
+	IncomeStatements map[string][]openbymadata.IncomeStatement
+
+	// History is keyed by symbol: either with its settlement suffix, e.g. "GGAL 48HS",
+	// or bare, e.g. "GGAL", to serve every settlement. GetHistory returns the candles
+	// within the requested range whatever the resolution; none is ErrNoHistory.
+	History map[string]*openbymadata.OHLCV
+
+	// Documents are the files served for News.Descarga and
+	// IncomeStatement.BalancesArchivo, keyed by the file name those URLs end with.
+	// Unknown names are answered with 404 Not Found.
+	Documents map[string][]byte
+
+	// Errors makes the requests for a collection fail with the given error, keyed by
+	// its cache category (openbymadata.CacheBluechips, CacheHistory, ...) or by
+	// ErrorsMarketTime and ErrorsDocuments. The error is wrapped like a network
+	// error, so errors.Is and errors.As see it through the client's error.
+	Errors map[string]error
+}
+
+// NewFakeClient returns a client that serves data instead of calling BYMA. data must
+// not be modified while the client is in use. Caching works as in the real client:
+// collections are requested once per CacheTTL.
+func NewFakeClient(data FakeData) openbymadata.Client {
+	multipliers := make(map[string]float64)
+	for _, future := range data.Futures {
+		underlying, _, _ := strings.Cut(strings.ToUpper(strings.TrimSpace(future.Symbol)), "/")
+		multipliers[underlying] = 1
+	}
+
+	return openbymadata.NewClient(&openbymadata.ClientOptions{
+		BaseURL:            BaseURL,
+		RetryAttempts:      1,
+		RetryBaseDelay:     time.Millisecond,
+		RetryMaxDelay:      time.Millisecond,
+		Logger:             &openbymadata.NoOpLogger{},
+		HTTPClient:         &http.Client{Transport: &transport{data: data}},
+		FuturesMultipliers: multipliers,
+	})
+}
+
+// transport answers BYMA requests from a FakeData
+type transport struct {
+	data FakeData
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+
+	endpoint, isAPI := strings.CutPrefix(req.URL.Path, apiPath)
+	if !isAPI {
+		// Session initialization: the dashboard and the translation dictionary
+		return respond(req, http.StatusOK, []byte("{}")), nil
+	}
+
+	if name, isDocument := strings.CutPrefix(endpoint, "sba/download/"); isDocument {
+		if err := t.data.Errors[ErrorsDocuments]; err != nil {
+			return nil, err
+		}
+		document, exists := t.data.Documents[name]
+		if !exists {
+			return respond(req, http.StatusNotFound, nil), nil
+		}
+		return respond(req, http.StatusOK, document), nil
+	}
+
+	category, items, err := t.collection(req, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if category == "" {
+		return respond(req, http.StatusNotFound, nil), nil
+	}
+	if err := t.data.Errors[category]; err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("openbymadatatest: encoding %s: %w", category, err)
+	}
+	return respond(req, http.StatusOK, body), nil
+}
+
+// collection returns the category of an endpoint and the raw payload BYMA would send
+// for it; the category is empty for unknown endpoints
+func (t *transport) collection(req *http.Request, endpoint string) (string, any, error) {
+	switch endpoint {
+	case "leading-equity":
+		return openbymadata.CacheBluechips, rawSecurities(t.data.Bluechips), nil
+	case "general-equity":
+		return openbymadata.CacheGalpones, rawSecurities(t.data.Galpones), nil
+	case "cedears":
+		return openbymadata.CacheCedears, rawSecurities(t.data.Cedears), nil
+	case "etf":
+		return openbymadata.CacheEtfs, rawSecurities(t.data.Etfs), nil
+	case "public-bonds":
+		return openbymadata.CacheBonds, rawBonds(t.data.Bonds), nil
+	case "lebacs":
+		return openbymadata.CacheShortTermBonds, rawBonds(t.data.ShortTermBonds), nil
+	case "negociable-obligations":
+		return openbymadata.CacheCorporateBonds, rawBonds(t.data.CorporateBonds), nil
+	case "options":
+		return openbymadata.CacheOptions, rawOptions(t.data.Options), nil
+	case "index-future":
+		return openbymadata.CacheFutures, rawFutures(t.data.Futures), nil
+	case "index-price":
+		return openbymadata.CacheIndices, rawIndices(t.data.Indices), nil
+	case "total-negotiated":
+		return openbymadata.CacheMarketSummary, rawMarketSummary(t.data.MarketSummary), nil
+	case "bnown/byma-ads":
+		return openbymadata.CacheNews, rawNews(t.data.News), nil
+	case "market-time":
+		return ErrorsMarketTime, map[string]any{"isWorkingDay": t.data.WorkingDay}, nil
+	case "bnown/seriesHistoricas/balances":
+		var request struct {
+			Symbol string `json:"symbol"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+			return "", nil, fmt.Errorf("openbymadatatest: decoding income statement request: %w", err)
+		}
+		return openbymadata.CacheIncomeStatements, rawIncomeStatements(t.incomeStatements(request.Symbol)), nil
+	case "chart/historical-series/history":
+		history, err := t.history(req.URL.Query())
+		return openbymadata.CacheHistory, history, err
+	}
+	return "", nil, nil
+}
+
+// incomeStatements returns the income statements of a ticker
+func (t *transport) incomeStatements(ticker string) []openbymadata.IncomeStatement {
+	for key, statements := range t.data.IncomeStatements {
+		if strings.EqualFold(key, ticker) {
+			return statements
+		}
+	}
+	return nil
+}
+
+// historyPayload is the body of a history response
+type historyPayload struct {
+	Status string    `json:"s"`
+	Time   []int64   `json:"t,omitempty"`
+	Open   []float64 `json:"o,omitempty"`
+	High   []float64 `json:"h,omitempty"`
+	Low    []float64 `json:"l,omitempty"`
+	Close  []float64 `json:"c,omitempty"`
+	Volume []int64   `json:"v,omitempty"`
+}
+
+// history returns the history payload for a request, keeping the candles between
+// its from and to timestamps
+func (t *transport) history(query url.Values) (historyPayload, error) {
+	symbol := query.Get("symbol")
+	data, exists := t.data.History[symbol]
+	if !exists {
+		root, _, _ := strings.Cut(symbol, " ")
+		data = t.data.History[root]
+	}
+
+	from, err := strconv.ParseInt(query.Get("from"), 10, 64)
+	if err != nil {
+		return historyPayload{}, fmt.Errorf("openbymadatatest: invalid history start: %w", err)
+	}
+	to, err := strconv.ParseInt(query.Get("to"), 10, 64)
+	if err != nil {
+		return historyPayload{}, fmt.Errorf("openbymadatatest: invalid history end: %w", err)
+	}
+
+	payload := historyPayload{Status: "ok"}
+	length, err := data.Len()
+	if err != nil {
+		return historyPayload{}, fmt.Errorf("openbymadatatest: history for %s: %w", symbol, err)
+	}
+	for i := range length {
+		if unix := data.Time[i].Unix(); unix < from || unix > to {
+			continue
+		}
+		payload.Time = append(payload.Time, data.Time[i].Unix())
+		payload.Open = append(payload.Open, data.Open[i])
+		payload.High = append(payload.High, data.High[i])
+		payload.Low = append(payload.Low, data.Low[i])
+		payload.Close = append(payload.Close, data.Close[i])
+		payload.Volume = append(payload.Volume, data.Volume[i])
+	}
+	if len(payload.Time) == 0 {
+		return historyPayload{Status: "no_data"}, nil
+	}
+	return payload, nil
+}
+
+// respond builds a response to req
+func respond(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
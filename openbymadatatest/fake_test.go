@@ -0,0 +1,105 @@
+package openbymadatatest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/carvalab/openbymadata"
+)
+
+func TestNewFakeClient(t *testing.T) {
+	ctx := context.Background()
+	tradeTime := time.Date(2024, 3, 15, 16, 30, 0, 0, time.UTC)
+	expiration := time.Date(2024, 4, 19, 0, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 3, 0, 0, 0, time.UTC) }
+	errBonds := errors.New("bonds unavailable")
+
+	client := NewFakeClient(FakeData{
+		WorkingDay: true,
+		Bluechips: []openbymadata.Security{
+			{Symbol: "GGAL", Settlement: "2", Bid: 4490, Ask: 4510, Last: 4500, Volume: 1000, DateTime: tradeTime, Group: "Acciones"},
+		},
+		Cedears: []openbymadata.Security{{Symbol: "AAPL", Last: 15000}},
+		Options: []openbymadata.Option{
+			{Symbol: "GFGC4500AB", Last: 120, UnderlyingAsset: "GGAL", Expiration: expiration},
+			{Symbol: "GFGV4500AB", Last: 80, UnderlyingAsset: "GGAL", Expiration: expiration},
+		},
+		Futures: []openbymadata.Future{{Symbol: "DLR/ABR24", Last: 900.5, OpenInterest: 50}},
+		Indices: []openbymadata.Index{{Symbol: "M", Description: "MERVAL", Last: 1000000}},
+		History: map[string]*openbymadata.OHLCV{
+			"GGAL": {
+				Time:   []time.Time{day(11), day(12), day(13)},
+				Open:   []float64{10, 11, 12},
+				High:   []float64{10, 11, 12},
+				Low:    []float64{10, 11, 12},
+				Close:  []float64{10, 11, 12},
+				Volume: []int64{100, 110, 120},
+			},
+		},
+		IncomeStatements: map[string][]openbymadata.IncomeStatement{
+			"ggal": {{Symbol: "GGAL", FechaCierre: "2023-12-31", BalancesArchivo: "ggal-2023.pdf"}},
+		},
+		Documents: map[string][]byte{"ggal-2023.pdf": []byte("%PDF")},
+		Errors:    map[string]error{openbymadata.CacheBonds: errBonds},
+	})
+	defer client.Close()
+
+	security, err := client.GetSecurity(ctx, "GGAL")
+	require.NoError(t, err)
+	assert.Equal(t, 4500.0, security.Last)
+	assert.Equal(t, 4490.0, security.Bid)
+	assert.Equal(t, int64(1000), security.Volume)
+	assert.Equal(t, "Acciones", security.Group)
+	assert.True(t, security.DateTime.Equal(tradeTime))
+
+	cedear, err := client.GetCedear(ctx, "AAPL")
+	require.NoError(t, err)
+	assert.Equal(t, 15000.0, cedear.Last)
+
+	_, err = client.GetBonds(ctx)
+	assert.ErrorIs(t, err, errBonds, "injected errors surface through the client")
+
+	calls, err := client.GetOptionsForUnderlying(ctx, "GGAL")
+	require.NoError(t, err)
+	chain := openbymadata.BuildOptionChain(calls)
+	require.Len(t, chain, 1)
+	assert.Equal(t, 4500.0, chain[0].Strike)
+	require.Len(t, chain[0].Calls, 1)
+	assert.True(t, chain[0].Calls[0].Expiration.Equal(expiration))
+
+	future, err := client.GetFuture(ctx, "DLR/ABR24")
+	require.NoError(t, err)
+	assert.Equal(t, 900.5, future.Last, "futures prices are served as given")
+	assert.Equal(t, int64(50), future.OpenInterest)
+
+	indices, err := client.GetIndices(ctx)
+	require.NoError(t, err)
+	require.Len(t, indices, 1)
+	assert.Equal(t, 1000000.0, indices[0].Last)
+
+	workingDay, err := client.IsWorkingDay(ctx)
+	require.NoError(t, err)
+	assert.True(t, workingDay)
+
+	history, err := client.GetHistory(ctx, "GGAL", "D", day(12), day(13))
+	require.NoError(t, err)
+	assert.Equal(t, []float64{11, 12}, history.Close)
+
+	_, err = client.GetHistory(ctx, "GGAL", "D", day(1), day(5))
+	assert.ErrorIs(t, err, openbymadata.ErrNoHistory)
+
+	dir := t.TempDir()
+	paths, err := client.DownloadLatestStatements(ctx, []string{"GGAL"}, dir)
+	require.NoError(t, err)
+	require.Contains(t, paths, "GGAL")
+	document, err := os.ReadFile(filepath.Clean(paths["GGAL"]))
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF", string(document))
+}
@@ -0,0 +1,164 @@
+package openbymadatatest
+
+import (
+	"path"
+	"time"
+
+	"github.com/carvalab/openbymadata"
+)
+
+// The raw* functions encode the public types back into the fields BYMA sends, so the
+// client parses them as it parses real responses.
+
+// rawTime encodes a time as RFC 3339, or nil for the zero time so the field is absent
+func rawTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
+
+// fileName returns the last element of a download URL, or "" for an empty one
+func fileName(downloadURL string) string {
+	if downloadURL == "" {
+		return ""
+	}
+	return path.Base(downloadURL)
+}
+
+// rawQuote holds the fields shared by every quoted instrument
+func rawQuote(symbol string, bidSize int64, bid, ask float64, askSize int64, last, closing, change,
+	open, high, low, previousClose, turnover float64, volume, operations int64, tradeTime time.Time) map[string]any {
+	raw := map[string]any{
+		"symbol":               symbol,
+		"quantityBid":          bidSize,
+		"bidPrice":             bid,
+		"offerPrice":           ask,
+		"quantityOffer":        askSize,
+		"settlementPrice":      last,
+		"closingPrice":         closing,
+		"imbalance":            change,
+		"openingPrice":         open,
+		"tradingHighPrice":     high,
+		"tradingLowPrice":      low,
+		"previousClosingPrice": previousClose,
+		"volumeAmount":         turnover,
+		"volume":               volume,
+		"numberOfOrders":       operations,
+	}
+	if !tradeTime.IsZero() {
+		raw["tradeHour"] = tradeTime.Format(time.RFC3339)
+	}
+	return raw
+}
+
+func rawSecurities(securities []openbymadata.Security) []map[string]any {
+	raws := make([]map[string]any, 0, len(securities))
+	for _, s := range securities {
+		raw := rawQuote(s.Symbol, s.BidSize, s.Bid, s.Ask, s.AskSize, s.Last, s.Close, s.Change,
+			s.Open, s.High, s.Low, s.PreviousClose, s.Turnover, s.Volume, s.Operations, s.DateTime)
+		raw["settlementType"] = s.Settlement
+		raw["securityType"] = s.Group
+		raw["panel"] = s.Panel
+		raws = append(raws, raw)
+	}
+	return raws
+}
+
+func rawBonds(bonds []openbymadata.Bond) []map[string]any {
+	raws := make([]map[string]any, 0, len(bonds))
+	for _, b := range bonds {
+		raw := rawQuote(b.Symbol, b.BidSize, b.Bid, b.Ask, b.AskSize, b.Last, b.Close, b.Change,
+			b.Open, b.High, b.Low, b.PreviousClose, b.Turnover, b.Volume, b.Operations, b.DateTime)
+		raw["settlementType"] = b.Settlement
+		raw["securityType"] = b.Group
+		raw["maturityDate"] = rawTime(b.Expiration)
+		raws = append(raws, raw)
+	}
+	return raws
+}
+
+func rawOptions(options []openbymadata.Option) []map[string]any {
+	raws := make([]map[string]any, 0, len(options))
+	for _, o := range options {
+		raw := rawQuote(o.Symbol, o.BidSize, o.Bid, o.Ask, o.AskSize, o.Last, o.Close, o.Change,
+			o.Open, o.High, o.Low, o.PreviousClose, o.Turnover, o.Volume, o.Operations, o.DateTime)
+		raw["underlyingSymbol"] = o.UnderlyingAsset
+		raw["maturityDate"] = rawTime(o.Expiration)
+		raws = append(raws, raw)
+	}
+	return raws
+}
+
+func rawFutures(futures []openbymadata.Future) []map[string]any {
+	raws := make([]map[string]any, 0, len(futures))
+	for _, f := range futures {
+		raw := rawQuote(f.Symbol, f.BidSize, f.Bid, f.Ask, f.AskSize, f.Last, f.Close, f.Change,
+			f.Open, f.High, f.Low, f.PreviousClose, f.Turnover, f.Volume, f.Operations, f.DateTime)
+		raw["maturityDate"] = rawTime(f.Expiration)
+		raw["openInterest"] = f.OpenInterest
+		raws = append(raws, raw)
+	}
+	return raws
+}
+
+func rawIndices(indices []openbymadata.Index) []map[string]any {
+	raws := make([]map[string]any, 0, len(indices))
+	for _, i := range indices {
+		raws = append(raws, map[string]any{
+			"description":          i.Description,
+			"symbol":               i.Symbol,
+			"price":                i.Last,
+			"variation":            i.Change,
+			"highValue":            i.High,
+			"minValue":             i.Low,
+			"previousClosingPrice": i.PreviousClose,
+		})
+	}
+	return raws
+}
+
+func rawMarketSummary(summaries []openbymadata.MarketSummary) []map[string]any {
+	raws := make([]map[string]any, 0, len(summaries))
+	for _, s := range summaries {
+		raws = append(raws, map[string]any{
+			"symbol":          s.Symbol,
+			"assetType":       s.AssetType,
+			"parentKey":       s.ParentKey,
+			"totalNegotiated": s.TotalNegotiated,
+			"volume":          s.Volume,
+			"operations":      s.Operations,
+		})
+	}
+	return raws
+}
+
+// rawNews keeps the file name of Descarga, which the client turns back into a URL
+func rawNews(news []openbymadata.News) []map[string]any {
+	raws := make([]map[string]any, 0, len(news))
+	for _, n := range news {
+		raws = append(raws, map[string]any{
+			"fecha":      rawTime(n.Fecha),
+			"emisor":     n.Titulo,
+			"referencia": n.Descripcion,
+			"descarga":   fileName(n.Descarga),
+		})
+	}
+	return raws
+}
+
+// rawIncomeStatements keeps the file name of BalancesArchivo, which the client turns
+// back into a URL
+func rawIncomeStatements(statements []openbymadata.IncomeStatement) []map[string]any {
+	raws := make([]map[string]any, 0, len(statements))
+	for _, s := range statements {
+		raws = append(raws, map[string]any{
+			"symbol":          s.Symbol,
+			"periodo":         s.Periodo,
+			"tipoPeriodo":     s.TipoPeriodo,
+			"fechaCierre":     s.FechaCierre,
+			"balancesArchivo": fileName(s.BalancesArchivo),
+		})
+	}
+	return raws
+}
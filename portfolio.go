@@ -0,0 +1,131 @@
+package openbymadata
+
+import (
+	"context"
+	"time"
+
+	"github.com/carvalab/openbymadata/internal/helpers"
+	"github.com/shopspring/decimal"
+)
+
+// Holding is a position of a Portfolio: a quantity of a security
+type Holding struct {
+	Symbol   string  `json:"symbol"`
+	Quantity float64 `json:"quantity"`
+}
+
+// Portfolio is a set of holdings of equities, CEDEARs and ETFs, valued at the current quotes
+// with Value. The zero value is an empty portfolio ready to use. A Portfolio is not
+// safe for concurrent modification.
+//
+// Example usage:
+//
+//	var portfolio openbymadata.Portfolio
+//	portfolio.AddHolding("GGAL", 100)
+//	portfolio.AddHolding("AAPL", 25)
+//
+//	valuation, err := portfolio.Value(ctx, client)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, h := range valuation.Holdings {
+//		fmt.Printf("%-6s %8s %10s\n", h.Symbol, h.MarketValue.StringFixed(2), h.DayPnL.StringFixed(2))
+//	}
+//	fmt.Printf("Total: $%s (day P&L $%s)\n",
+//		valuation.MarketValue.StringFixed(2), valuation.DayPnL.StringFixed(2))
+//	for _, symbol := range valuation.NotFound {
+//		fmt.Printf("%s: not found\n", symbol)
+//	}
+type Portfolio struct {
+	holdings []Holding
+}
+
+// AddHolding adds qty units of symbol to the portfolio. Adding a symbol already held
+// (ignoring case and surrounding whitespace) adds to its quantity; a negative qty
+// reduces it, or records a short position.
+func (p *Portfolio) AddHolding(symbol string, qty float64) {
+	for i := range p.holdings {
+		if helpers.SymbolsEqual(p.holdings[i].Symbol, symbol) {
+			p.holdings[i].Quantity += qty
+			return
+		}
+	}
+	p.holdings = append(p.holdings, Holding{Symbol: helpers.NormalizeSymbol(symbol), Quantity: qty})
+}
+
+// Holdings returns the holdings of the portfolio, in the order they were first added
+func (p *Portfolio) Holdings() []Holding {
+	return append([]Holding(nil), p.holdings...)
+}
+
+// HoldingValuation is the valuation of a single holding. Security is nil, and the
+// amounts are zero, when the symbol was not found.
+type HoldingValuation struct {
+	Holding
+	Security    *Security       `json:"security"`
+	MarketValue decimal.Decimal `json:"market_value"` // Quantity × Last
+	DayPnL      decimal.Decimal `json:"day_pnl"`      // Change in MarketValue since the previous close
+}
+
+// PortfolioValuation is the result of Portfolio.Value: a valuation per holding, in the
+// order of the portfolio, and the totals over the holdings that were found
+type PortfolioValuation struct {
+	Holdings    []HoldingValuation `json:"holdings"`
+	MarketValue decimal.Decimal    `json:"market_value"`
+	DayPnL      decimal.Decimal    `json:"day_pnl"`
+	NotFound    []string           `json:"not_found"` // Symbols not found, in portfolio order
+	Timestamp   time.Time          `json:"timestamp"` // When the valuation was made
+}
+
+// Value values the portfolio at the current quotes, fetched with a single
+// GetMultipleSecuritiesDetailed call (and therefore served from the cache when
+// possible). Each holding's market value is Quantity × Last, and its day P&L is the
+// change of that value implied by the security's Change, the percentage change since
+// the previous close. Symbols not found in any collection are listed in NotFound and
+// left out of the totals rather than failing the valuation.
+func (p *Portfolio) Value(ctx context.Context, client Client) (*PortfolioValuation, error) {
+	valuation := &PortfolioValuation{
+		Holdings:  make([]HoldingValuation, 0, len(p.holdings)),
+		NotFound:  []string{},
+		Timestamp: time.Now(),
+	}
+	if len(p.holdings) == 0 {
+		return valuation, nil
+	}
+
+	symbols := make([]string, len(p.holdings))
+	for i, holding := range p.holdings {
+		symbols[i] = holding.Symbol
+	}
+	securities, notFound, err := client.GetMultipleSecuritiesDetailed(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+	valuation.NotFound = notFound
+
+	for _, holding := range p.holdings {
+		item := HoldingValuation{Holding: holding}
+		if security, found := securities[holding.Symbol]; found {
+			quantity := decimal.NewFromFloat(holding.Quantity)
+			item.Security = security
+			item.MarketValue = security.LastDecimal().Mul(quantity)
+			item.DayPnL = dayChange(*security).Mul(quantity)
+			valuation.MarketValue = valuation.MarketValue.Add(item.MarketValue)
+			valuation.DayPnL = valuation.DayPnL.Add(item.DayPnL)
+		}
+		valuation.Holdings = append(valuation.Holdings, item)
+	}
+	return valuation, nil
+}
+
+// dayChange returns the price change of a security since the previous close, derived
+// from Last and the percentage Change: Last − Last / (1 + Change/100)
+func dayChange(security Security) decimal.Decimal {
+	hundred := decimal.NewFromInt(100)
+	factor := Decimal(security.Change).Add(hundred)
+	if !factor.IsPositive() {
+		return decimal.Zero
+	}
+	last := security.LastDecimal()
+	return last.Sub(last.Mul(hundred).DivRound(factor, 8)).Round(8)
+}
@@ -2,11 +2,21 @@ package openbymadata
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
+	"log/slog"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/carvalab/openbymadata/internal/api"
+	"github.com/carvalab/openbymadata/internal/cache"
+	"github.com/carvalab/openbymadata/internal/helpers"
+	"github.com/shopspring/decimal"
 )
 
 // =============================================================================
@@ -17,13 +27,20 @@ import (
 type Client interface {
 	// Market status and general info
 	IsWorkingDay(ctx context.Context) (bool, error)
+	GetMarketTime(ctx context.Context) (*MarketTime, error)
 	GetIndices(ctx context.Context) ([]Index, error)
 	MarketResume(ctx context.Context) ([]MarketSummary, error)
+	GetMarketSummaryFor(ctx context.Context, assetType string) (*MarketSummary, error)
+	TotalMarketVolume(ctx context.Context) (float64, int64, error)
+	ValidateMarketResume(ctx context.Context) (*ValidationReport, error)
+	GetDollarRates(ctx context.Context) ([]DollarRate, error)
 
 	// Securities
 	GetBluechips(ctx context.Context) ([]Security, error)
 	GetGalpones(ctx context.Context) ([]Security, error)
 	GetCedears(ctx context.Context) ([]Security, error)
+	GetCedearsPage(ctx context.Context, offset, limit int) ([]Security, int, error)
+	GetEtfs(ctx context.Context) ([]Security, error)
 
 	// Fixed Income
 	GetBonds(ctx context.Context) ([]Bond, error)
@@ -36,29 +53,89 @@ type Client interface {
 
 	// News and Financial Data
 	GetNews(ctx context.Context) ([]News, error)
+	GetNewsFor(ctx context.Context, emisor string, from, to time.Time) ([]News, error)
 	GetIncomeStatement(ctx context.Context, ticker string) ([]IncomeStatement, error)
+	DownloadAttachment(ctx context.Context, documentURL string) ([]byte, string, error)
+	DownloadIncomeStatement(ctx context.Context, documentURL string) ([]byte, error)
+	DownloadLatestStatements(ctx context.Context, tickers []string, dir string) (map[string]string, error)
+
+	// Iterators over the collections, without copying them
+	IterBluechips(ctx context.Context) iter.Seq2[Security, error]
+	IterGalpones(ctx context.Context) iter.Seq2[Security, error]
+	IterCedears(ctx context.Context) iter.Seq2[Security, error]
+	IterEtfs(ctx context.Context) iter.Seq2[Security, error]
+	IterBonds(ctx context.Context) iter.Seq2[Bond, error]
+	IterShortTermBonds(ctx context.Context) iter.Seq2[Bond, error]
+	IterCorporateBonds(ctx context.Context) iter.Seq2[Bond, error]
+	IterOptions(ctx context.Context) iter.Seq2[Option, error]
+	IterFutures(ctx context.Context) iter.Seq2[Future, error]
 
 	// Individual security lookups
 	GetSecurity(ctx context.Context, symbol string) (*Security, error)
 	GetBluechip(ctx context.Context, symbol string) (*Security, error)
 	GetCedear(ctx context.Context, symbol string) (*Security, error)
+	GetCedearWithUSD(ctx context.Context, symbol string) (*CedearQuote, error)
 	GetGalpone(ctx context.Context, symbol string) (*Security, error)
+	GetEtf(ctx context.Context, symbol string) (*Security, error)
 	GetBond(ctx context.Context, symbol string) (*Bond, error)
 	GetOption(ctx context.Context, symbol string) (*Option, error)
+	GetOptionsForUnderlying(ctx context.Context, underlying string) ([]Option, error)
 	GetFuture(ctx context.Context, symbol string) (*Future, error)
+	GetSecuritiesByBoard(ctx context.Context, board string) ([]Security, error)
+	GetAllSecurities(ctx context.Context) ([]Security, error)
+	GetAnySecurity(ctx context.Context, symbol string) (*AnySecurity, error)
+	GetSecurityType(ctx context.Context, symbol string) (AssetClass, error)
+	QuoteAge(ctx context.Context, symbol string) (time.Duration, error)
 
 	// Batch operations
 	GetMultipleSecurities(ctx context.Context, symbols []string) (map[string]*Security, error)
-	SearchSecurities(ctx context.Context, searchText string) ([]Security, error)
+	GetMultipleSecuritiesDetailed(ctx context.Context, symbols []string) (map[string]*Security, []string, error)
+	GetMultipleSecuritiesPartial(ctx context.Context, symbols []string) (map[string]*Security, []*CollectionError)
+	GetMultipleSecuritiesMaxAge(ctx context.Context, symbols []string, maxAge time.Duration) (map[string]*Security, error)
+	GetMultipleSecuritiesFields(ctx context.Context, symbols []string, fields ...SecurityField) (map[string]*Security, error)
+	GetMultipleBonds(ctx context.Context, symbols []string) (map[string]*Bond, error)
+	GetMultipleOptions(ctx context.Context, symbols []string) (map[string]*Option, error)
+	GetMultipleFutures(ctx context.Context, symbols []string) (map[string]*Future, error)
+	SearchSecurities(ctx context.Context, searchText string, opts ...SearchOptions) ([]Security, error)
+
+	// Subscriptions
+	SubscribeSecurities(ctx context.Context, symbols []string, interval time.Duration) (<-chan SecurityUpdate, error)
+	StreamIndices(ctx context.Context) (<-chan Index, error)
 
 	// Historical Data
 	GetHistory(ctx context.Context, symbol, resolution string, from, to time.Time) (*OHLCV, error)
 	GetHistoryLastDays(ctx context.Context, symbol string, days int) (*OHLCV, error)
+	GetHistoryLastTradingDays(ctx context.Context, symbol string, bars int) (*OHLCV, error)
+	GetFiftyTwoWeekRange(ctx context.Context, symbol string) (low, high float64, err error)
+	TotalReturnHistory(ctx context.Context, symbol string, from, to time.Time, actions []CorporateAction) (*OHLCV, error)
+	GetHistoryIntraday(ctx context.Context, symbol string, minutes int, from, to time.Time) (*OHLCV, error)
 	ConvertToHistoricalData(slices *OHLCV) ([]HistoricalData, error)
 
 	// Cache management
 	GetCacheInfo() map[string]interface{}
 	ClearCache()
+	Flush() error
+	CacheStats() CacheStats
+	RetryStats() RetryStats
+	ResetStats()
+
+	// Snapshots
+	Snapshot(ctx context.Context, name string) error
+	GetSnapshot(name string) (*MarketSnapshot, error)
+
+	// Translation dictionary
+	RefreshDictionary(ctx context.Context) error
+
+	// Request headers
+	SetHeader(key, value string)
+	RemoveHeader(key string)
+
+	// Debugging
+	LastRawResponse(endpoint string) []byte
+
+	// Lifecycle
+	Close() error
+	CloseWithTimeout(drainTimeout time.Duration) error
 }
 
 // =============================================================================
@@ -66,6 +143,9 @@ type Client interface {
 // =============================================================================
 
 // HTTPClient defines the interface for HTTP operations
+//
+// Deprecated: this interface is not used by the client. To customize the HTTP
+// layer, set ClientOptions.HTTPClient to an *http.Client instead.
 type HTTPClient interface {
 	Get(url string) (*HTTPResponse, error)
 	Post(url string, data []byte) (*HTTPResponse, error)
@@ -86,24 +166,224 @@ type ConfigProvider interface {
 	GetBaseURL() string
 }
 
+// CCLSource provides the CCL (contado con liquidación) exchange rate, in ARS per USD,
+// used to derive implied USD prices for CEDEARs
+type CCLSource interface {
+	GetCCL(ctx context.Context) (float64, error)
+}
+
+// FixedCCL is a CCLSource that always returns the same rate
+type FixedCCL float64
+
+// GetCCL returns the fixed rate
+func (f FixedCCL) GetCCL(ctx context.Context) (float64, error) {
+	return float64(f), nil
+}
+
 // =============================================================================
 // Data Models (Type Aliases)
 // =============================================================================
 
 // Type aliases to internal types
 type (
-	Security        = api.Security
-	Bond            = api.Bond
-	Option          = api.Option
-	Future          = api.Future
-	Index           = api.Index
-	MarketSummary   = api.MarketSummary
-	News            = api.News
-	IncomeStatement = api.IncomeStatement
-	HistoricalData  = api.HistoricalData
-	OHLCV           = api.OHLCV
+	Security         = api.Security
+	Bond             = api.Bond
+	Option           = api.Option
+	OptionStrike     = api.OptionStrike
+	CorporateAction  = api.CorporateAction
+	Future           = api.Future
+	Index            = api.Index
+	MarketSummary    = api.MarketSummary
+	News             = api.News
+	IncomeStatement  = api.IncomeStatement
+	ValidationReport = api.ValidationReport
+	DollarRate       = api.DollarRate
+	MarketSnapshot   = api.MarketSnapshot
+	MarketTime       = api.MarketTime
+	HistoricalData   = api.HistoricalData
+	OHLCV            = api.OHLCV
+	RetryStats       = api.RetryStats
+	RequestInfo      = api.RequestInfo
+	Settlement       = api.Settlement
+
+	CacheStats         = cache.Stats
+	CacheCategoryStats = cache.CategoryStats
 )
 
+// RequestInfoFrom returns the endpoint and attempt number of an HTTP request sent by
+// the client, read from its context, so round-trippers installed with
+// ClientOptions.HTTPClient can tell retries apart. It reports false for requests the
+// client didn't send.
+//
+//	func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+//		if info, ok := openbymadata.RequestInfoFrom(req.Context()); ok && info.Attempt > 0 {
+//			log.Printf("retry %d of %s", info.Attempt, info.Endpoint)
+//		}
+//		return t.next.RoundTrip(req)
+//	}
+func RequestInfoFrom(ctx context.Context) (RequestInfo, bool) {
+	return api.RequestInfoFrom(ctx)
+}
+
+// Resample aggregates daily candles into weekly ("W") or monthly ("M") candles locally,
+// so several timeframes can be derived from a single GetHistory call. Each bucket is
+// stamped with the start of its period (Monday or the 1st, at 00:00); periods without
+// candles are skipped and a trailing incomplete period yields a partial bucket.
+//
+//	daily, _ := client.GetHistory(ctx, "GGAL", "D", from, to)
+//	weekly, err := openbymadata.Resample(daily, "W")
+func Resample(data *OHLCV, target string) (*OHLCV, error) {
+	return api.Resample(data, target)
+}
+
+// AdjustForSplits back-adjusts a price series for splits so that it can be used for
+// long-horizon analysis: prices before each split's ex-date are divided by its ratio and
+// volumes multiplied by it. Dividends are ignored. The series returned by GetHistory is
+// not adjusted and is left untouched; a new series is returned.
+//
+//	actions := []openbymadata.CorporateAction{{
+//		Symbol: "GGAL",
+//		Type:   openbymadata.CorporateActionSplit,
+//		ExDate: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+//		Ratio:  2, // 2:1
+//	}}
+//	adjusted := openbymadata.AdjustForSplits(history, actions)
+func AdjustForSplits(data *OHLCV, actions []CorporateAction) *OHLCV {
+	return api.AdjustForSplits(data, actions)
+}
+
+// Paginate returns the page of items starting at offset with at most limit elements,
+// together with the total number of items so callers can render pagination controls.
+// It works on any collection (securities, bonds, options...); a limit of zero returns
+// every item from offset on, an offset past the end yields an empty page, and negative
+// values return an ErrInvalidPage error. The page is a copy of the items.
+//
+//	bonds, _ := client.GetBonds(ctx)
+//	page, total, err := openbymadata.Paginate(bonds, 20, 10)
+func Paginate[T any](items []T, offset, limit int) ([]T, int, error) {
+	page, err := helpers.Paginate(items, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return page, len(items), nil
+}
+
+// FilterOptionsByExpiration returns the options expiring between from and to, both
+// dates included. A zero bound leaves that side of the range open.
+func FilterOptionsByExpiration(options []Option, from, to time.Time) []Option {
+	return helpers.FilterOptionsByExpiration(options, from, to)
+}
+
+// FilterOptionsByKind returns the calls (OptionCall) or puts (OptionPut) among options,
+// according to their Kind
+func FilterOptionsByKind(options []Option, kind string) []Option {
+	return helpers.FilterOptionsByKind(options, kind)
+}
+
+// BuildOptionChain groups options by strike into a simple option chain, ordered from the
+// lowest strike up. Filter the options down to one underlying and expiration first.
+//
+//	options, _ := client.GetOptionsForUnderlying(ctx, "GGAL")
+//	chain := openbymadata.BuildOptionChain(options)
+func BuildOptionChain(options []Option) []OptionStrike {
+	return helpers.BuildOptionChain(options)
+}
+
+// ParseOptionSymbol derives the kind (OptionCall or OptionPut) and strike of an option
+// from its BYMA symbol, e.g. "GFGC3000OC" is a 3000 call. ok is false when the symbol
+// doesn't follow the BYMA naming convention.
+func ParseOptionSymbol(symbol string) (kind string, strike float64, ok bool) {
+	return api.ParseOptionSymbol(symbol)
+}
+
+// Decimal converts a price to a decimal.Decimal holding exactly the value BYMA quoted,
+// e.g. 4505.25 rather than the nearest float64. Security, Bond, Option and Future also
+// have LastDecimal, BidDecimal, AskDecimal and TurnoverDecimal methods. Sum money in
+// decimals to avoid accumulating float rounding errors:
+//
+//	total := decimal.Zero
+//	for _, position := range portfolio {
+//		total = total.Add(position.Security.LastDecimal().Mul(decimal.NewFromInt(position.Quantity)))
+//	}
+func Decimal(price float64) decimal.Decimal {
+	return api.Decimal(price)
+}
+
+// ParseSecurity decodes a Security from its public JSON representation, as produced
+// by json.Marshal. It does not accept raw BYMA API payloads.
+//
+//	data, _ := json.Marshal(security)
+//	restored, err := openbymadata.ParseSecurity(data)
+func ParseSecurity(data json.RawMessage) (Security, error) {
+	var security Security
+	if err := json.Unmarshal(data, &security); err != nil {
+		return Security{}, fmt.Errorf("failed to parse security: %w", err)
+	}
+	return security, nil
+}
+
+// SecurityApproxEqual reports whether two securities are equal within epsilon.
+// Price and amount fields (Bid, Ask, Last, Close, Change, Open, High, Low,
+// PreviousClose, Turnover) may differ by at most epsilon (inclusive); every other
+// field must match exactly. This is useful to compare quotes without float noise:
+//
+//	if !openbymadata.SecurityApproxEqual(previous, current, 0.005) {
+//		fmt.Printf("%s changed: $%.2f\n", current.Symbol, current.Last)
+//	}
+func SecurityApproxEqual(a, b Security, epsilon float64) bool {
+	return helpers.SecurityApproxEqual(a, b, epsilon)
+}
+
+// freshDataKey is the context key set by WithFreshData
+type freshDataKey struct{}
+
+// WithFreshData returns a copy of ctx for calls that must not be served from the
+// cache. Methods called with it always request BYMA, and the fresh data replaces the
+// cached entry, so later calls benefit from it; other categories stay cached. It is
+// the per-call alternative to ClearCache:
+//
+//	// Guaranteed-fresh quote right before placing an order
+//	quote, err := client.GetSecurity(openbymadata.WithFreshData(ctx), "GGAL")
+func WithFreshData(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshDataKey{}, true)
+}
+
+// maxAgeKey is the context key set by WithMaxAge
+type maxAgeKey struct{}
+
+// WithMaxAge returns a copy of ctx for calls that only accept cached data at most
+// maxAge old. Older data is fetched again, as with WithFreshData, while newer data is
+// served from the cache. The cache itself is never dropped: other calls keep being
+// served from it, it is only replaced by the data fetched, and a failed fetch leaves
+// it in place. When ctx already has a max age, the shorter one applies; a maxAge of
+// zero or less is the same as WithFreshData.
+//
+//	// Quotes at most 10 seconds old for this refresh, cached ones for everything else
+//	quotes, err := client.GetMultipleSecurities(openbymadata.WithMaxAge(ctx, 10*time.Second), watchlist)
+func WithMaxAge(ctx context.Context, maxAge time.Duration) context.Context {
+	if maxAge <= 0 {
+		return WithFreshData(ctx)
+	}
+	if current := maxAgeFrom(ctx); current > 0 && current < maxAge {
+		return ctx
+	}
+	return context.WithValue(ctx, maxAgeKey{}, maxAge)
+}
+
+// WithSettlement returns a copy of ctx for calls that request quotes for the given
+// settlement term instead of the default T1 (24hs). It applies to the equity, CEDEAR,
+// ETF and bond collections and the lookups built on them, such as GetSecurity and
+// GetMultipleSecurities; each term is cached apart, so T0 and T1 quotes don't replace
+// each other. A term other than T0, T1 or T2 fails those calls with an
+// ErrInvalidSettlement error.
+//
+//	// Contado inmediato quotes
+//	ci := openbymadata.WithSettlement(ctx, openbymadata.SettlementT0)
+//	ggal, err := client.GetSecurity(ci, "GGAL")
+func WithSettlement(ctx context.Context, settlement Settlement) context.Context {
+	return api.WithSettlement(ctx, settlement)
+}
+
 // =============================================================================
 // Configuration Types
 // =============================================================================
@@ -133,25 +413,305 @@ type MarketTimeResponse struct {
 	IsWorkingDay bool `json:"isWorkingDay"`
 }
 
+// DefaultFuturesMultiplier is the factor applied to futures prices unless
+// ClientOptions.FuturesMultipliers sets another one for the contract's underlying.
+// BYMA quotes futures prices in thousands, so a quoted 1.45 becomes 1450 pesos.
+const DefaultFuturesMultiplier = api.DefaultFuturesMultiplier
+
+// Corporate action types reported in CorporateAction.Type
+const (
+	CorporateActionDividend = api.CorporateActionDividend
+	CorporateActionSplit    = api.CorporateActionSplit
+)
+
+// Settlement terms requested with WithSettlement
+const (
+	SettlementT0 = api.SettlementT0 // Contado inmediato (CI)
+	SettlementT1 = api.SettlementT1 // 24hs, the default
+	SettlementT2 = api.SettlementT2 // 48hs
+)
+
+// Option kinds reported in Option.Kind, as derived from option symbols by ParseOptionSymbol
+const (
+	OptionCall = api.OptionCall
+	OptionPut  = api.OptionPut
+)
+
+// MaxIntradayRange is the longest range requested for intraday history; longer
+// ranges are shortened to end at the requested end
+const MaxIntradayRange = api.MaxIntradayRange
+
+// Cache categories, used as keys in ClientOptions.CacheTTLOverrides
+const (
+	CacheBluechips        = cache.CategoryBluechips
+	CacheCedears          = cache.CategoryCedears
+	CacheGalpones         = cache.CategoryGalpones
+	CacheEtfs             = cache.CategoryEtfs
+	CacheBonds            = cache.CategoryBonds
+	CacheShortTermBonds   = cache.CategoryShortTermBonds
+	CacheCorporateBonds   = cache.CategoryCorporateBonds
+	CacheOptions          = cache.CategoryOptions
+	CacheFutures          = cache.CategoryFutures
+	CacheIndices          = cache.CategoryIndices
+	CacheMarketSummary    = cache.CategoryMarketSummary
+	CacheNews             = cache.CategoryNews
+	CacheIncomeStatements = cache.CategoryIncomeStatements
+	CacheHistory          = cache.CategoryHistory
+	CacheDictionary       = cache.CategoryDictionary // Defaults to DefaultDictionaryTTL
+)
+
+// DefaultDictionaryTTL is how long the translation dictionary is cached unless
+// ClientOptions.CacheTTLOverrides sets another TTL for CacheDictionary
+const DefaultDictionaryTTL = cache.DefaultDictionaryDuration
+
+// SecurityField identifies a single Security field by its JSON name
+type SecurityField string
+
+// Security fields that can be selected with GetMultipleSecuritiesFields
+const (
+	FieldSettlement    SecurityField = "settlement"
+	FieldBidSize       SecurityField = "bid_size"
+	FieldBid           SecurityField = "bid"
+	FieldAsk           SecurityField = "ask"
+	FieldAskSize       SecurityField = "ask_size"
+	FieldLast          SecurityField = "last"
+	FieldClose         SecurityField = "close"
+	FieldChange        SecurityField = "change"
+	FieldOpen          SecurityField = "open"
+	FieldHigh          SecurityField = "high"
+	FieldLow           SecurityField = "low"
+	FieldPreviousClose SecurityField = "previous_close"
+	FieldTurnover      SecurityField = "turnover"
+	FieldVolume        SecurityField = "volume"
+	FieldOperations    SecurityField = "operations"
+	FieldDateTime      SecurityField = "datetime"
+	FieldGroup         SecurityField = "group"
+	FieldPanel         SecurityField = "panel"
+	FieldCollection    SecurityField = "collection"
+)
+
+// SearchMode selects how SearchSecurities matches the search text
+type SearchMode string
+
+// Match modes for SearchOptions. Every mode ignores case.
+const (
+	SearchSubstring SearchMode = helpers.SearchSubstring // Anywhere in the field (default)
+	SearchPrefix    SearchMode = helpers.SearchPrefix    // At the start of the field, e.g. for autocomplete
+	SearchExact     SearchMode = helpers.SearchExact     // The whole field
+)
+
+// SearchField is a field SearchSecurities can match against
+type SearchField string
+
+// Fields for SearchOptions
+const (
+	SearchBySymbol SearchField = helpers.SearchFieldSymbol
+	// SearchByDescription matches the name BYMA's translation dictionary gives the
+	// symbol; securities without an entry only match by symbol
+	SearchByDescription SearchField = helpers.SearchFieldDescription
+)
+
+// SearchOptions configures SearchSecurities
+type SearchOptions struct {
+	Mode   SearchMode    // How the text must match (default: SearchSubstring)
+	Fields []SearchField // Fields to search (default: symbol only)
+	Limit  int           // Maximum number of results, 0 for all
+}
+
+// CedearQuote represents a CEDEAR price in ARS together with its implied USD value
+type CedearQuote struct {
+	Symbol        string  `json:"symbol"`
+	LastARS       float64 `json:"last_ars"`       // Last price in ARS as quoted on BYMA
+	LastUSD       float64 `json:"last_usd"`       // Implied USD price of one CEDEAR (LastARS / CCL), zero when unavailable
+	CCL           float64 `json:"ccl"`            // CCL exchange rate used, in ARS per USD, zero when unavailable
+	USDAvailable  bool    `json:"usd_available"`  // Whether a valid CCL rate was available
+	Ratio         float64 `json:"ratio"`          // CEDEARs per share of the underlying, from ClientOptions.CedearRatios, zero when unknown
+	UnderlyingUSD float64 `json:"underlying_usd"` // Implied USD price of one underlying share (LastUSD * Ratio), zero when USD or ratio is unavailable
+}
+
+// AssetClass identifies the kind of instrument matched by GetAnySecurity or
+// GetSecurityType
+type AssetClass string
+
+// Asset classes reported by GetAnySecurity and GetSecurityType
+const (
+	AssetClassEquity        AssetClass = "equity" // Blue chips and general equity (GetAnySecurity only)
+	AssetClassCedear        AssetClass = "cedear"
+	AssetClassETF           AssetClass = "etf"
+	AssetClassBond          AssetClass = "bond"
+	AssetClassShortTermBond AssetClass = "short_term_bond"
+	AssetClassCorporateBond AssetClass = "corporate_bond"
+	AssetClassIndex         AssetClass = "index"
+)
+
+// Asset classes reported by GetSecurityType only
+const (
+	AssetClassBluechip      AssetClass = "bluechip"
+	AssetClassGeneralEquity AssetClass = "general_equity"
+	AssetClassOption        AssetClass = "option"
+	AssetClassFuture        AssetClass = "future"
+	AssetClassNotFound      AssetClass = "not_found" // The symbol is not listed in any collection
+)
+
+// AnySecurity is the result of a lookup across every asset class. Exactly one of
+// Security, Bond or Index is set, according to AssetClass.
+type AnySecurity struct {
+	Symbol     string     `json:"symbol"`
+	AssetClass AssetClass `json:"asset_class"`
+	Security   *Security  `json:"security,omitempty"` // Equities, CEDEARs and ETFs
+	Bond       *Bond      `json:"bond,omitempty"`     // Government, short-term and corporate bonds
+	Index      *Index     `json:"index,omitempty"`
+}
+
+// SecurityUpdate is a quote change emitted by SubscribeSecurities
+type SecurityUpdate struct {
+	Symbol   string    `json:"symbol"`
+	Security Security  `json:"security"`
+	Previous *Security `json:"previous,omitempty"` // Last value seen, nil on the first update
+}
+
 // ClientOptions represents configuration options for the client
 type ClientOptions struct {
 	BaseURL       string
-	Timeout       time.Duration
+	Timeout       time.Duration // Per-attempt timeout for calls whose context has no deadline (default: 30s)
 	RetryAttempts int
 	Logger        Logger
-	HTTPClient    HTTPClient
-	EnableCache   bool // Enable 5-minute caching (default: true)
+	EnableCache   bool      // Enable caching (default: true)
+	CCLSource     CCLSource // CCL rate used by GetCedearWithUSD (optional)
+
+	// CedearRatios maps CEDEAR symbols to their conversion ratio, the number of CEDEARs
+	// that represent one share of the underlying, e.g. {"AAPL": 20} (optional). BYMA
+	// doesn't publish ratios through this API, so GetCedearWithUSD only reports
+	// CedearQuote.Ratio and UnderlyingUSD for the symbols listed here.
+	CedearRatios map[string]float64
+
+	// RetryBaseDelay and RetryMaxDelay bound the exponential backoff between retries.
+	// Each wait is randomized between RetryBaseDelay and min(RetryBaseDelay*2^attempt,
+	// RetryMaxDelay) so concurrent clients don't retry in lockstep (default: 1s and 30s).
+	// A Retry-After header on the failed response replaces the backoff
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+
+	// OnRetry is called before each retry with the retry number (starting at 1), the
+	// error that failed the previous attempt and the backoff about to be waited
+	// (optional). It runs on the requesting goroutine, so it should return quickly.
+	OnRetry func(attempt int, err error, nextDelay time.Duration)
+
+	// OnRequest is called after each HTTP attempt, retries included, with the endpoint
+	// (e.g. "cedears"), how long the attempt took and its error, nil on success
+	// (optional). Together with OnError, OnCacheHit and OnCacheMiss it lets you export
+	// metrics; the openbymadataprom package wires all four to Prometheus. Like OnRetry,
+	// these hooks run on the requesting goroutine, so they should return quickly.
+	OnRequest func(endpoint string, duration time.Duration, err error)
+
+	// OnError is called once for each request that still fails after any retries, with
+	// the endpoint and the error returned to the caller (optional). Use errors.As to
+	// get its *BYMAError and Code.
+	OnError func(endpoint string, err error)
+
+	// OnCacheHit and OnCacheMiss are called for every cache lookup counted by
+	// CacheStats, with its Cache* category (optional). They run while the cache is
+	// locked, so they must not call the client.
+	OnCacheHit  func(category string)
+	OnCacheMiss func(category string)
+
+	// UserAgent replaces the default browser User-Agent header, e.g. to present the
+	// one a WAF expects (optional)
+	UserAgent string
+
+	// ExtraHeaders are sent with every request, e.g. an API key or a tracing header
+	// (optional). They are added to the default headers, replacing any with the same
+	// name regardless of case; UserAgent takes precedence over a User-Agent entry.
+	ExtraHeaders map[string]string
+
+	// Debug logs raw API responses and their field names at debug level, like setting
+	// the DEBUG=true environment variable but for this client only (default: false)
+	Debug bool
+
+	// FuturesMultipliers sets the factor applied to the prices BYMA quotes for futures,
+	// keyed by underlying: the symbol part before "/", e.g. "DLR" for "DLR/OCT25"
+	// (optional). Contracts whose underlying isn't listed use DefaultFuturesMultiplier.
+	// BYMA's payload doesn't report contract multipliers, so set this for contracts
+	// quoted on a different scale; Future.QuotedPrice recovers the quoted price.
+	FuturesMultipliers map[string]float64
+
+	// RequestsPerSecond caps the rate of requests sent to BYMA, so bursts of cache
+	// misses stay under its rate limiting (optional, zero means no limit). Every
+	// attempt, retries included, waits for its turn within the caller's context;
+	// requests are spaced evenly rather than sent in bursts.
+	RequestsPerSecond float64
+
+	// SkipSessionInit constructs the client without the two blocking requests it
+	// otherwise makes to establish a session and load the translation dictionary
+	// (default: false). Construction then costs no network round trip, e.g. for
+	// hermetic tests or latency-sensitive services; descriptions are left untranslated
+	// and NewClientStrict can't detect an unreachable BYMA.
+	SkipSessionInit bool
+
+	// CacheTTL is how long cached data stays fresh (default: 5 minutes when zero)
+	CacheTTL time.Duration
+
+	// CacheTTLOverrides sets a different TTL for specific cache categories, keyed by
+	// the Cache* category constants, e.g. to keep news longer than quotes (optional)
+	CacheTTLOverrides map[string]time.Duration
+
+	// CacheDir persists the cache to this directory so it survives process restarts,
+	// e.g. for short-lived CLI invocations (optional). Each category is stored as
+	// <CacheDir>/<category>.json, and income statements and history one file per
+	// ticker or range under <CacheDir>/<category>/; files older than the TTL are
+	// ignored on load. Files are written by the call that updated the cache once the
+	// cache is unlocked, so lookups never wait on the disk.
+	CacheDir string
+
+	// StaleWhileRevalidate enables serving expired data for up to this long after its
+	// TTL (optional). Within that window the cached data is returned immediately and
+	// refreshed in the background, so callers never wait for the network on expiry.
+	StaleWhileRevalidate time.Duration
+
+	// NegativeCacheTTL makes GetSecurity remember symbols that were not found in any
+	// collection for this long, returning INVALID_TICKER without looking them up again
+	// (optional, keep it short). Clearing the cache or refreshing any security
+	// collection forgets them.
+	NegativeCacheTTL time.Duration
+
+	// IndexStreamInterval is how often StreamIndices polls for index changes
+	// (default: 5s)
+	IndexStreamInterval time.Duration
+
+	// StrictParsing makes quote collections (equities, CEDEARs, ETFs, bonds, options,
+	// futures, indices and the market summary) fail with an INVALID_RESPONSE error
+	// when an item lacks its symbol or has a field of the wrong type, naming every bad
+	// field, instead of reading those fields as zero (default: false)
+	StrictParsing bool
+
+	// InsecureSkipVerify disables TLS certificate verification (default: false).
+	// Only enable it if you understand the risk, e.g. to work around an incomplete
+	// certificate chain; prefer RootCAs to trust a specific certificate instead.
+	InsecureSkipVerify bool
+
+	// RootCAs sets the certificate pool used to verify the server (optional).
+	// When nil, the system roots are used.
+	RootCAs *x509.CertPool
+
+	// HTTPClient is the HTTP client used for every request (optional). Use it to share
+	// a connection pool or add custom round-trippers. When set, it is used as is:
+	// InsecureSkipVerify and RootCAs only configure the default client, and its own
+	// http.Client.Timeout, if any, still caps every request.
+	HTTPClient *http.Client
 }
 
 // DefaultClientOptions returns default client options
 func DefaultClientOptions() *ClientOptions {
 	return &ClientOptions{
-		BaseURL:       "https://open.bymadata.com.ar",
-		Timeout:       30 * time.Second,
-		RetryAttempts: 3,
-		Logger:        &NoOpLogger{},
-		HTTPClient:    nil,  // Will be created by client
-		EnableCache:   true, // Cache enabled by default
+		BaseURL:        "https://open.bymadata.com.ar",
+		Timeout:        30 * time.Second,
+		RetryAttempts:  3,
+		RetryBaseDelay: api.DefaultRetryBaseDelay,
+		RetryMaxDelay:  api.DefaultRetryMaxDelay,
+		Logger:         &NoOpLogger{},
+		EnableCache:    true, // Cache enabled by default
+
+		IndexStreamInterval: 5 * time.Second,
 	}
 }
 
@@ -163,89 +723,127 @@ func (l *NoOpLogger) Info(msg string, fields ...LogField)  {}
 func (l *NoOpLogger) Warn(msg string, fields ...LogField)  {}
 func (l *NoOpLogger) Error(msg string, fields ...LogField) {}
 
+// NewSlogLogger returns a Logger that writes to logger, mapping each LogField to a
+// slog attribute. A nil logger uses slog.Default().
+//
+//	client := openbymadata.NewClient(&openbymadata.ClientOptions{
+//		Logger: openbymadata.NewSlogLogger(slog.Default()),
+//	})
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogLogger{logger: logger}
+}
+
+// slogLogger adapts a *slog.Logger to the Logger interface
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l *slogLogger) Debug(msg string, fields ...LogField) { l.log(slog.LevelDebug, msg, fields) }
+func (l *slogLogger) Info(msg string, fields ...LogField)  { l.log(slog.LevelInfo, msg, fields) }
+func (l *slogLogger) Warn(msg string, fields ...LogField)  { l.log(slog.LevelWarn, msg, fields) }
+func (l *slogLogger) Error(msg string, fields ...LogField) { l.log(slog.LevelError, msg, fields) }
+
+func (l *slogLogger) log(level slog.Level, msg string, fields []LogField) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := make([]slog.Attr, len(fields))
+	for i, f := range fields {
+		attrs[i] = slog.Any(f.Key, f.Value)
+	}
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
 // =============================================================================
 // Error Types
 // =============================================================================
 
 // Error types for the BYMA library
 var (
-	ErrInvalidResponse = &BYMAError{Code: "INVALID_RESPONSE", Message: "Invalid API response"}
-	ErrAPIUnavailable  = &BYMAError{Code: "API_UNAVAILABLE", Message: "BYMA API is unavailable"}
-	ErrInvalidTicker   = &BYMAError{Code: "INVALID_TICKER", Message: "Invalid ticker symbol"}
-	ErrTimeout         = &BYMAError{Code: "TIMEOUT", Message: "Request timeout"}
-	ErrUnauthorized    = &BYMAError{Code: "UNAUTHORIZED", Message: "Unauthorized access"}
-	ErrRateLimited     = &BYMAError{Code: "RATE_LIMITED", Message: "Rate limit exceeded"}
-	ErrInternalError   = &BYMAError{Code: "INTERNAL_ERROR", Message: "Internal server error"}
+	ErrInvalidResponse = api.ErrInvalidResponse
+	ErrAPIUnavailable  = api.ErrAPIUnavailable
+	ErrInvalidTicker   = api.ErrInvalidTicker
+	ErrTimeout         = api.ErrTimeout
+	ErrUnauthorized    = api.ErrUnauthorized
+	ErrRateLimited     = api.ErrRateLimited
+	ErrInternalError   = api.ErrInternalError
+
+	ErrInvalidResolution = api.ErrInvalidResolution
+	ErrInvalidDateRange  = api.ErrInvalidDateRange
+	ErrNoHistory         = api.ErrNoHistory
+
+	ErrInvalidSearch = api.ErrInvalidSearch
+	ErrInvalidPage   = api.ErrInvalidPage
+
+	ErrNoTradeTime = api.ErrNoTradeTime
+
+	ErrInvalidDocumentURL = api.ErrInvalidDocumentURL
+
+	ErrInvalidSnapshotName = api.ErrInvalidSnapshotName
+	ErrSnapshotNotFound    = api.ErrSnapshotNotFound
+
+	ErrInvalidSettlement = api.ErrInvalidSettlement
 )
 
-// BYMAError represents a custom error from the BYMA library
-type BYMAError struct {
-	Code       string `json:"code"`
-	Message    string `json:"message"`
-	StatusCode int    `json:"status_code,omitempty"`
-	Underlying error  `json:"-"`
-}
+// ErrClientClosed is returned by requests made after Close or CloseWithTimeout
+var ErrClientClosed = api.ErrClientClosed
 
-// Error implements the error interface
-func (e *BYMAError) Error() string {
-	if e.Underlying != nil {
-		return fmt.Sprintf("%s: %s (underlying: %v)", e.Code, e.Message, e.Underlying)
-	}
-	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+// CollectionError reports a security collection that failed to load, identified by
+// its Cache* category, e.g. CacheCedears. It is returned by GetMultipleSecuritiesPartial.
+type CollectionError struct {
+	Collection string
+	Err        error
 }
 
-// Unwrap returns the underlying error
-func (e *BYMAError) Unwrap() error {
-	return e.Underlying
+func (e *CollectionError) Error() string {
+	return fmt.Sprintf("failed to load %s: %v", e.Collection, e.Err)
 }
 
-// WithUnderlying adds an underlying error
-func (e *BYMAError) WithUnderlying(err error) *BYMAError {
-	return &BYMAError{
-		Code:       e.Code,
-		Message:    e.Message,
-		StatusCode: e.StatusCode,
-		Underlying: err,
-	}
+// Unwrap returns the error the collection failed with
+func (e *CollectionError) Unwrap() error {
+	return e.Err
 }
 
-// WithStatusCode adds an HTTP status code
-func (e *BYMAError) WithStatusCode(code int) *BYMAError {
-	return &BYMAError{
-		Code:       e.Code,
-		Message:    e.Message,
-		StatusCode: code,
-		Underlying: e.Underlying,
-	}
-}
+// BYMAError represents a custom error from the BYMA library
+type BYMAError = api.BYMAError
 
 // NewBYMAError creates a new BYMA error
 func NewBYMAError(code, message string) *BYMAError {
-	return &BYMAError{
-		Code:    code,
-		Message: message,
-	}
+	return api.NewBYMAError(code, message)
 }
 
 // MapHTTPError maps HTTP status codes to BYMA errors
 func MapHTTPError(statusCode int) *BYMAError {
-	switch statusCode {
-	case http.StatusUnauthorized:
-		return ErrUnauthorized.WithStatusCode(statusCode)
-	case http.StatusTooManyRequests:
-		return ErrRateLimited.WithStatusCode(statusCode)
-	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable:
-		return ErrAPIUnavailable.WithStatusCode(statusCode)
-	case http.StatusRequestTimeout:
-		return ErrTimeout.WithStatusCode(statusCode)
-	default:
-		return NewBYMAError("HTTP_ERROR", fmt.Sprintf("HTTP error %d", statusCode)).WithStatusCode(statusCode)
+	return api.MapHTTPError(statusCode)
+}
+
+// TickerErrors collects per-ticker failures from batch operations
+type TickerErrors map[string]error
+
+// Error implements the error interface
+func (e TickerErrors) Error() string {
+	tickers := make([]string, 0, len(e))
+	for ticker := range e {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+
+	messages := make([]string, len(tickers))
+	for i, ticker := range tickers {
+		messages[i] = fmt.Sprintf("%s: %v", ticker, e[ticker])
 	}
+	return fmt.Sprintf("%d ticker(s) failed: %s", len(e), strings.Join(messages, "; "))
 }
 
-// IsRetryable determines if an error is retryable
+// IsRetryable determines if an error is retryable. Wrapped BYMA errors, as returned
+// by the client, are recognized too.
 func IsRetryable(err error) bool {
-	if bymaErr, ok := err.(*BYMAError); ok {
+	var bymaErr *BYMAError
+	if errors.As(err, &bymaErr) {
 		switch bymaErr.Code {
 		case "TIMEOUT", "API_UNAVAILABLE", "RATE_LIMITED":
 			return true